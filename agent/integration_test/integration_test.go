@@ -3,11 +3,13 @@ package integration_test
 import (
 	"context"
 	"github.com/alexGoLyceum/calculator-service/agent/internal/agent"
+	"github.com/alexGoLyceum/calculator-service/agent/internal/client"
 	"github.com/alexGoLyceum/calculator-service/agent/internal/config"
 	"github.com/alexGoLyceum/calculator-service/agent/internal/tasks"
 	"github.com/alexGoLyceum/calculator-service/pkg/logging/mocks"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/mock/gomock"
@@ -17,35 +19,42 @@ type mockClient struct {
 	mock.Mock
 }
 
-func (m *mockClient) StreamTasks(ctx context.Context, handler func(task *tasks.Task) error) error {
-	args := m.Called(ctx, handler)
+func (m *mockClient) RegisterAgent(ctx context.Context, registration client.Registration) error {
+	return nil
+}
+
+func (m *mockClient) StreamTasks(ctx context.Context, credits <-chan int32, handler func(task *tasks.Task) error) error {
+	args := m.Called(ctx, credits, handler)
 	return args.Error(0)
 }
 
-func (m *mockClient) SetTaskResult(ctx context.Context, task tasks.Task, result float64) error {
+func (m *mockClient) SetTaskResult(ctx context.Context, task tasks.Task, result tasks.Result) error {
 	args := m.Called(ctx, task, result)
 	return args.Error(0)
 }
 
+func (m *mockClient) Heartbeat(ctx context.Context, taskIDs []uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
 func (m *mockClient) Close() error {
 	return nil
 }
 
 func TestAgent_Start(t *testing.T) {
 	mockGrpcClient := new(mockClient)
-	mockGrpcClient.On("StreamTasks", mock.Anything, mock.Anything).
+	mockGrpcClient.On("StreamTasks", mock.Anything, mock.Anything, mock.Anything).
 		Return(nil).
 		Run(func(args mock.Arguments) {
-			handler := args.Get(1).(func(task *tasks.Task) error)
+			handler := args.Get(2).(func(task *tasks.Task) error)
 			task := &tasks.Task{
-				Arg1:     tasks.Operand{Value: 2},
-				Arg2:     tasks.Operand{Value: 3},
+				Args:     []tasks.Operand{{Value: 2}, {Value: 3}},
 				Operator: "+",
 			}
 			_ = handler(task)
 		})
 
-	mockGrpcClient.On("SetTaskResult", mock.Anything, mock.Anything, float64(5)).
+	mockGrpcClient.On("SetTaskResult", mock.Anything, mock.Anything, tasks.Result{Value: 5}).
 		Return(nil)
 
 	cfg := &config.Config{}
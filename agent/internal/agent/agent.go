@@ -2,12 +2,19 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/alexGoLyceum/calculator-service/agent/internal/client"
 	"github.com/alexGoLyceum/calculator-service/agent/internal/config"
 	"github.com/alexGoLyceum/calculator-service/agent/internal/tasks"
 	"github.com/alexGoLyceum/calculator-service/pkg/logging"
+
+	"github.com/google/uuid"
 )
 
 type Agent interface {
@@ -18,10 +25,13 @@ type Impl struct {
 	Config *config.Config
 	Logger logging.Logger
 	Client client.Client
+
+	mu       sync.Mutex
+	inFlight map[uuid.UUID]struct{}
 }
 
 func NewAgent(cfg *config.Config, logger logging.Logger) (*Impl, error) {
-	grpcClient, err := client.NewClient(cfg.Orchestrator)
+	grpcClient, err := client.NewClient(cfg.Orchestrator, cfg.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create grpc client: %w", err)
 	}
@@ -35,17 +45,126 @@ func NewAgent(cfg *config.Config, logger logging.Logger) (*Impl, error) {
 func (a *Impl) Start() error {
 	defer a.Client.Close()
 
-	ctx := context.Background()
-	err := a.Client.StreamTasks(ctx, func(task *tasks.Task) error {
-		result := tasks.Calculate(task)
-		if err := a.Client.SetTaskResult(ctx, *task, result); err != nil {
-			return err
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	workers := max(a.Config.ComputingPower, 1)
+	if err := a.Client.RegisterAgent(ctx, client.Registration{
+		Hostname:  a.Config.Hostname,
+		Version:   a.Config.Version,
+		Capacity:  workers,
+		Operators: tasks.Operators,
+	}); err != nil {
+		return err
+	}
+
+	a.inFlight = make(map[uuid.UUID]struct{})
+	var heartbeats sync.WaitGroup
+	if a.Config.HeartbeatInterval > 0 {
+		heartbeats.Add(1)
+		go func() {
+			defer heartbeats.Done()
+			a.heartbeat(ctx, a.Config.HeartbeatInterval)
+		}()
+	}
+
+	jobs := make(chan *tasks.Task, workers)
+	credits := make(chan int32, workers+1)
+	credits <- int32(workers)
+
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		workErr error
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range jobs {
+				if err := a.process(ctx, task); err != nil {
+					errOnce.Do(func() {
+						workErr = err
+						cancel()
+					})
+					continue
+				}
+				select {
+				case credits <- 1:
+				default:
+				}
+			}
+		}()
+	}
+
+	err := a.Client.StreamTasks(ctx, credits, func(task *tasks.Task) error {
+		select {
+		case jobs <- task:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
 		}
-		return nil
 	})
+	close(jobs)
+	wg.Wait()
+	cancel()
+	heartbeats.Wait()
 
-	if err != nil {
+	if workErr != nil {
+		return workErr
+	}
+	return err
+}
+
+func (a *Impl) process(ctx context.Context, task *tasks.Task) error {
+	a.track(task.ID, true)
+	defer a.track(task.ID, false)
+
+	result := tasks.Evaluate(task)
+	if err := a.Client.SetTaskResult(ctx, *task, result); err != nil {
+		if errors.Is(err, client.ErrTaskNotFound) || errors.Is(err, client.ErrLeaseLost) {
+			a.Logger.Warn("Task result discarded", logging.String("task_id", task.ID.String()), logging.Error(err))
+			return nil
+		}
 		return err
 	}
 	return nil
 }
+
+func (a *Impl) track(id uuid.UUID, running bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if running {
+		a.inFlight[id] = struct{}{}
+	} else {
+		delete(a.inFlight, id)
+	}
+}
+
+// heartbeat renews the leases of in-flight tasks. It keeps beating while the
+// agent is idle so the orchestrator still sees it as live.
+func (a *Impl) heartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.mu.Lock()
+			ids := slices.Collect(maps.Keys(a.inFlight))
+			a.mu.Unlock()
+
+			lost, err := a.Client.Heartbeat(ctx, ids)
+			if err != nil {
+				if ctx.Err() == nil {
+					a.Logger.Warn("Heartbeat failed", logging.Error(err))
+				}
+				continue
+			}
+			for _, id := range lost {
+				a.Logger.Warn("Task lease lost", logging.String("task_id", id.String()))
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -25,28 +26,24 @@ func TestAgent_Start_SuccessfulFlow(t *testing.T) {
 
 	mockClient := mocks.NewMockClient(ctrl)
 	mockLogger := logmock.NewMockLogger(ctrl)
+	mockClient.EXPECT().RegisterAgent(gomock.Any(), gomock.Any()).Return(nil)
 
 	testTask := &tasks.Task{
-		ID:           uuid.New(),
-		ExpressionID: uuid.Nil,
-		Arg1: tasks.Operand{
-			Value: 2.0,
-		},
-		Arg2: tasks.Operand{
-			Value: 2.0,
-		},
+		ID:            uuid.New(),
+		ExpressionID:  uuid.Nil,
+		Args:          []tasks.Operand{{Value: 2.0}, {Value: 2.0}},
 		Operator:      "+",
 		OperationTime: time.Now(),
 	}
 
-	mockClient.EXPECT().StreamTasks(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(_ context.Context, handler func(*tasks.Task) error) error {
+	mockClient.EXPECT().StreamTasks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ <-chan int32, handler func(*tasks.Task) error) error {
 			err := handler(testTask)
 			require.NoError(t, err)
 			return nil
 		})
 
-	expectedResult := tasks.Calculate(testTask)
+	expectedResult := tasks.Evaluate(testTask)
 	mockClient.EXPECT().SetTaskResult(gomock.Any(), *testTask, expectedResult).Return(nil)
 	mockClient.EXPECT().Close().Return(nil)
 
@@ -65,27 +62,23 @@ func TestAgent_Start_SetTaskResultError(t *testing.T) {
 
 	mockClient := mocks.NewMockClient(ctrl)
 	mockLogger := logmock.NewMockLogger(ctrl)
+	mockClient.EXPECT().RegisterAgent(gomock.Any(), gomock.Any()).Return(nil)
 
 	testTask := &tasks.Task{
-		ID:           uuid.New(),
-		ExpressionID: uuid.Nil,
-		Arg1: tasks.Operand{
-			Value: 2.0,
-		},
-		Arg2: tasks.Operand{
-			Value: 2.0,
-		},
+		ID:            uuid.New(),
+		ExpressionID:  uuid.Nil,
+		Args:          []tasks.Operand{{Value: 2.0}, {Value: 2.0}},
 		Operator:      "+",
 		OperationTime: time.Now(),
 	}
 
-	mockClient.EXPECT().StreamTasks(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(_ context.Context, handler func(*tasks.Task) error) error {
+	mockClient.EXPECT().StreamTasks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ <-chan int32, handler func(*tasks.Task) error) error {
 			_ = handler(testTask)
 			return nil
 		})
 
-	mockClient.EXPECT().SetTaskResult(gomock.Any(), *testTask, tasks.Calculate(testTask)).
+	mockClient.EXPECT().SetTaskResult(gomock.Any(), *testTask, tasks.Evaluate(testTask)).
 		Return(errors.New("set task result failed"))
 
 	mockClient.EXPECT().Close().Return(nil)
@@ -99,15 +92,137 @@ func TestAgent_Start_SetTaskResultError(t *testing.T) {
 	a.Start()
 }
 
+func TestAgent_Start_DiscardedTaskContinues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClient(ctrl)
+	mockLogger := logmock.NewMockLogger(ctrl)
+	mockClient.EXPECT().RegisterAgent(gomock.Any(), gomock.Any()).Return(nil)
+
+	first := &tasks.Task{ID: uuid.New(), Args: []tasks.Operand{{Value: 1}, {Value: 2}}, Operator: "+"}
+	second := &tasks.Task{ID: uuid.New(), Args: []tasks.Operand{{Value: 3}, {Value: 4}}, Operator: "*"}
+
+	mockClient.EXPECT().StreamTasks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ <-chan int32, handler func(*tasks.Task) error) error {
+			require.NoError(t, handler(first))
+			require.NoError(t, handler(second))
+			return nil
+		})
+	gomock.InOrder(
+		mockClient.EXPECT().SetTaskResult(gomock.Any(), *first, tasks.Evaluate(first)).Return(client.ErrTaskNotFound),
+		mockClient.EXPECT().SetTaskResult(gomock.Any(), *second, tasks.Evaluate(second)).Return(nil),
+	)
+	mockLogger.EXPECT().Warn("Task result discarded", gomock.Any())
+	mockClient.EXPECT().Close().Return(nil)
+
+	a := &agent.Impl{
+		Config: &config.Config{},
+		Logger: mockLogger,
+		Client: mockClient,
+	}
+
+	require.NoError(t, a.Start())
+}
+
+func TestAgent_Start_WorkerPool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClient(ctrl)
+	mockLogger := logmock.NewMockLogger(ctrl)
+	mockClient.EXPECT().RegisterAgent(gomock.Any(), gomock.Any()).Return(nil)
+
+	const power = 3
+	started := make(chan struct{}, power)
+	release := make(chan struct{})
+	mockClient.EXPECT().SetTaskResult(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, tasks.Task, tasks.Result) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}).Times(power)
+
+	mockClient.EXPECT().StreamTasks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, credits <-chan int32, handler func(*tasks.Task) error) error {
+			require.Equal(t, int32(power), <-credits)
+			for i := 0; i < power; i++ {
+				require.NoError(t, handler(&tasks.Task{ID: uuid.New(), Args: []tasks.Operand{{Value: 1}, {Value: 2}}, Operator: "+"}))
+			}
+			for i := 0; i < power; i++ {
+				<-started
+			}
+			require.Empty(t, credits)
+
+			close(release)
+			for i := 0; i < power; i++ {
+				require.Equal(t, int32(1), <-credits)
+			}
+			return nil
+		})
+	mockClient.EXPECT().Close().Return(nil)
+
+	a := &agent.Impl{
+		Config: &config.Config{ComputingPower: power},
+		Logger: mockLogger,
+		Client: mockClient,
+	}
+
+	require.NoError(t, a.Start())
+}
+
+func TestAgent_Start_Heartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClient(ctrl)
+	mockLogger := logmock.NewMockLogger(ctrl)
+	mockClient.EXPECT().RegisterAgent(gomock.Any(), gomock.Any()).Return(nil)
+
+	task := &tasks.Task{ID: uuid.New(), Args: []tasks.Operand{{Value: 1}, {Value: 2}}, Operator: "+"}
+	renewed := make(chan struct{})
+	var once sync.Once
+
+	mockClient.EXPECT().Heartbeat(gomock.Any(), gomock.Len(0)).Return(nil, nil).AnyTimes()
+	mockClient.EXPECT().Heartbeat(gomock.Any(), []uuid.UUID{task.ID}).
+		DoAndReturn(func(context.Context, []uuid.UUID) ([]uuid.UUID, error) {
+			once.Do(func() { close(renewed) })
+			return []uuid.UUID{task.ID}, nil
+		}).MinTimes(1)
+	mockLogger.EXPECT().Warn("Task lease lost", gomock.Any()).MinTimes(1)
+
+	mockClient.EXPECT().SetTaskResult(gomock.Any(), *task, tasks.Evaluate(task)).
+		DoAndReturn(func(context.Context, tasks.Task, tasks.Result) error {
+			<-renewed
+			return client.ErrLeaseLost
+		})
+	mockLogger.EXPECT().Warn("Task result discarded", gomock.Any())
+
+	mockClient.EXPECT().StreamTasks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ <-chan int32, handler func(*tasks.Task) error) error {
+			return handler(task)
+		})
+	mockClient.EXPECT().Close().Return(nil)
+
+	a := &agent.Impl{
+		Config: &config.Config{ComputingPower: 1, HeartbeatInterval: time.Millisecond},
+		Logger: mockLogger,
+		Client: mockClient,
+	}
+
+	require.NoError(t, a.Start())
+}
+
 func TestAgent_Start_StreamTasksError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockClient := mocks.NewMockClient(ctrl)
 	mockLogger := logmock.NewMockLogger(ctrl)
+	mockClient.EXPECT().RegisterAgent(gomock.Any(), gomock.Any()).Return(nil)
 
 	expectedErr := errors.New("stream error")
-	mockClient.EXPECT().StreamTasks(gomock.Any(), gomock.Any()).Return(expectedErr)
+	mockClient.EXPECT().StreamTasks(gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedErr)
 	mockClient.EXPECT().Close().Return(nil)
 
 	a := &agent.Impl{
@@ -122,6 +237,47 @@ func TestAgent_Start_StreamTasksError(t *testing.T) {
 	}
 }
 
+func TestAgent_Start_Registration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockClient(ctrl)
+	mockClient.EXPECT().RegisterAgent(gomock.Any(), client.Registration{
+		Hostname:  "host",
+		Version:   "1.0.0",
+		Capacity:  3,
+		Operators: tasks.Operators,
+	}).Return(nil)
+	mockClient.EXPECT().StreamTasks(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mockClient.EXPECT().Close().Return(nil)
+
+	a := &agent.Impl{
+		Config: &config.Config{Hostname: "host", Version: "1.0.0", ComputingPower: 3},
+		Logger: logmock.NewMockLogger(ctrl),
+		Client: mockClient,
+	}
+
+	require.NoError(t, a.Start())
+}
+
+func TestAgent_Start_RegistrationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expectedErr := errors.New("registration failed")
+	mockClient := mocks.NewMockClient(ctrl)
+	mockClient.EXPECT().RegisterAgent(gomock.Any(), gomock.Any()).Return(expectedErr)
+	mockClient.EXPECT().Close().Return(nil)
+
+	a := &agent.Impl{
+		Config: &config.Config{},
+		Logger: logmock.NewMockLogger(ctrl),
+		Client: mockClient,
+	}
+
+	require.ErrorIs(t, a.Start(), expectedErr)
+}
+
 func TestNewAgent_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -132,7 +288,7 @@ func TestNewAgent_Success(t *testing.T) {
 	}()
 
 	mockClient := mocks.NewMockClient(ctrl)
-	client.NewClient = func(cfg config.OrchestratorConfig) (client.Client, error) {
+	client.NewClient = func(cfg config.OrchestratorConfig, agentID string) (client.Client, error) {
 		return mockClient, nil
 	}
 
@@ -164,7 +320,7 @@ func TestNewAgent_ClientCreationError(t *testing.T) {
 	}()
 
 	expectedErr := errors.New("connection error")
-	client.NewClient = func(cfg config.OrchestratorConfig) (client.Client, error) {
+	client.NewClient = func(cfg config.OrchestratorConfig, agentID string) (client.Client, error) {
 		return nil, expectedErr
 	}
 
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,25 +12,41 @@ import (
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+var (
+	ErrTaskNotFound = errors.New("task no longer exists")
+	ErrLeaseLost    = errors.New("task lease is no longer held")
+)
+
+type Registration struct {
+	Hostname  string
+	Version   string
+	Capacity  int
+	Operators []string
+}
+
 type Client interface {
-	StreamTasks(ctx context.Context, handler func(task *tasks.Task) error) error
-	SetTaskResult(ctx context.Context, task tasks.Task, result float64) error
+	RegisterAgent(ctx context.Context, registration Registration) error
+	StreamTasks(ctx context.Context, credits <-chan int32, handler func(task *tasks.Task) error) error
+	SetTaskResult(ctx context.Context, task tasks.Task, result tasks.Result) error
+	Heartbeat(ctx context.Context, taskIDs []uuid.UUID) ([]uuid.UUID, error)
 	Close() error
 }
 
 type Impl struct {
-	Client pb.OrchestratorServiceClient
-	Conn   *grpc.ClientConn
+	Client  pb.OrchestratorServiceClient
+	Conn    *grpc.ClientConn
+	AgentID string
 }
 
-type NewClientFunc func(cfg config.OrchestratorConfig) (Client, error)
+type NewClientFunc func(cfg config.OrchestratorConfig, agentID string) (Client, error)
 
 var NewClient NewClientFunc = defaultNewClient
 
-func defaultNewClient(cfg config.OrchestratorConfig) (Client, error) {
+func defaultNewClient(cfg config.OrchestratorConfig, agentID string) (Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -46,17 +63,48 @@ func defaultNewClient(cfg config.OrchestratorConfig) (Client, error) {
 	c := pb.NewOrchestratorServiceClient(conn)
 
 	return &Impl{
-		Client: c,
-		Conn:   conn,
+		Client:  c,
+		Conn:    conn,
+		AgentID: agentID,
 	}, nil
 }
 
-func (c *Impl) StreamTasks(ctx context.Context, handler func(task *tasks.Task) error) error {
-	stream, err := c.Client.AssignTasks(ctx, &pb.AssignTasksRequest{})
+func (c *Impl) RegisterAgent(ctx context.Context, registration Registration) error {
+	req := &pb.RegisterAgentRequest{
+		AgentId:   c.AgentID,
+		Hostname:  registration.Hostname,
+		Version:   registration.Version,
+		Capacity:  int32(registration.Capacity),
+		Operators: registration.Operators,
+	}
+	if _, err := c.Client.RegisterAgent(ctx, req); err != nil {
+		return fmt.Errorf("failed to register agent: %w", err)
+	}
+	return nil
+}
+
+func (c *Impl) StreamTasks(ctx context.Context, credits <-chan int32, handler func(task *tasks.Task) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.Client.AssignTasks(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to start stream: %w", err)
 	}
 
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case n := <-credits:
+				if err := stream.Send(&pb.AssignTasksRequest{Credits: n, AgentId: c.AgentID}); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -69,14 +117,25 @@ func (c *Impl) StreamTasks(ctx context.Context, handler func(task *tasks.Task) e
 			exprID, _ := uuid.Parse(t.ExpressionId)
 			taskID, _ := uuid.Parse(t.Id)
 
+			args := make([]tasks.Operand, len(t.Args))
+			for i, value := range t.Args {
+				args[i] = tasks.Operand{Value: value}
+				if i < len(t.DecimalArgs) {
+					args[i].Decimal = t.DecimalArgs[i]
+				}
+			}
+
 			task := &tasks.Task{
-				ID:            taskID,
-				ExpressionID:  exprID,
-				Arg1:          tasks.Operand{Value: t.Arg1Num},
-				Arg2:          tasks.Operand{Value: t.Arg2Num},
-				Operator:      t.Operator,
-				OperationTime: t.OperationTime.AsTime(),
-				FinalTask:     t.FinalTask,
+				ID:              taskID,
+				ExpressionID:    exprID,
+				Args:            args,
+				Operator:        t.Operator,
+				OperationTime:   t.OperationTime.AsTime(),
+				FinalTask:       t.FinalTask,
+				Precision:       t.Precision,
+				Scale:           int(t.Scale),
+				Policy:          t.Policy,
+				AssignmentToken: t.AssignmentToken,
 			}
 
 			if err := handler(task); err != nil {
@@ -86,26 +145,49 @@ func (c *Impl) StreamTasks(ctx context.Context, handler func(task *tasks.Task) e
 	}
 }
 
-func (c *Impl) SetTaskResult(ctx context.Context, task tasks.Task, result float64) error {
+func (c *Impl) SetTaskResult(ctx context.Context, task tasks.Task, result tasks.Result) error {
 	req := &pb.SubmitTaskRequest{
-		Task: &pb.Task{
-			Id:            task.ID.String(),
-			ExpressionId:  task.ExpressionID.String(),
-			Arg1Num:       task.Arg1.Value,
-			Arg2Num:       task.Arg2.Value,
-			Operator:      task.Operator,
-			OperationTime: timestamppb.New(task.OperationTime),
-			FinalTask:     task.FinalTask,
-		},
-		Result: result,
+		TaskId:          task.ID.String(),
+		AssignmentToken: task.AssignmentToken,
+		Result:          result.Value,
+		DecimalResult:   result.Decimal,
+		Error:           result.Error,
 	}
 
 	if _, err := c.Client.SubmitTask(ctx, req); err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return ErrTaskNotFound
+		case codes.FailedPrecondition:
+			return ErrLeaseLost
+		}
 		return fmt.Errorf("failed to submit task result: %w", err)
 	}
 	return nil
 }
 
+func (c *Impl) Heartbeat(ctx context.Context, taskIDs []uuid.UUID) ([]uuid.UUID, error) {
+	req := &pb.HeartbeatRequest{AgentId: c.AgentID, TaskIds: make([]string, len(taskIDs))}
+	for i, id := range taskIDs {
+		req.TaskIds[i] = id.String()
+	}
+
+	resp, err := c.Client.Heartbeat(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send heartbeat: %w", err)
+	}
+
+	lost := make([]uuid.UUID, 0, len(resp.LostTaskIds))
+	for _, id := range resp.LostTaskIds {
+		taskID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		lost = append(lost, taskID)
+	}
+	return lost, nil
+}
+
 func (c *Impl) Close() error {
 	return c.Conn.Close()
 }
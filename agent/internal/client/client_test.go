@@ -17,6 +17,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -29,19 +31,24 @@ func TestSetTaskResult_Success(t *testing.T) {
 	task := tasks.Task{
 		ID:            uuid.New(),
 		ExpressionID:  uuid.New(),
-		Arg1:          tasks.Operand{Value: 5},
-		Arg2:          tasks.Operand{Value: 3},
+		Args:          []tasks.Operand{{Value: 5}, {Value: 3}},
 		Operator:      "+",
 		OperationTime: time.Now(),
 		FinalTask:     false,
 	}
 
+	task.AssignmentToken = uuid.New().String()
+
 	mockClient.EXPECT().
-		SubmitTask(gomock.Any(), gomock.Any()).
+		SubmitTask(gomock.Any(), &pb.SubmitTaskRequest{
+			TaskId:          task.ID.String(),
+			AssignmentToken: task.AssignmentToken,
+			Result:          8,
+		}).
 		Return(&pb.SubmitTaskResponse{}, nil)
 
 	c := &client.Impl{Client: mockClient}
-	err := c.SetTaskResult(context.Background(), task, 8)
+	err := c.SetTaskResult(context.Background(), task, tasks.Result{Value: 8})
 	require.NoError(t, err)
 }
 
@@ -53,8 +60,7 @@ func TestSetTaskResult_Error(t *testing.T) {
 	task := tasks.Task{
 		ID:            uuid.New(),
 		ExpressionID:  uuid.New(),
-		Arg1:          tasks.Operand{Value: 2},
-		Arg2:          tasks.Operand{Value: 3},
+		Args:          []tasks.Operand{{Value: 2}, {Value: 3}},
 		Operator:      "*",
 		OperationTime: time.Now(),
 		FinalTask:     false,
@@ -65,19 +71,32 @@ func TestSetTaskResult_Error(t *testing.T) {
 		Return(nil, errors.New("submit failed"))
 
 	c := &client.Impl{Client: mockClient}
-	err := c.SetTaskResult(context.Background(), task, 6)
+	err := c.SetTaskResult(context.Background(), task, tasks.Result{Value: 6})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "submit failed")
 }
 
 type mockStream struct {
 	pb.OrchestratorService_AssignTasksClient
-	tasks []*pb.Task
-	index int
+	tasks   []*pb.Task
+	index   int
+	credits chan int32
+	wait    <-chan struct{}
+}
+
+func (m *mockStream) Send(req *pb.AssignTasksRequest) error {
+	if req.AgentId != "agent-1" {
+		return errors.New("unexpected agent id")
+	}
+	m.credits <- req.Credits
+	return nil
 }
 
 func (m *mockStream) Recv() (*pb.Task, error) {
 	if m.index >= len(m.tasks) {
+		if m.wait != nil {
+			<-m.wait
+		}
 		return nil, errors.New("EOF")
 	}
 	t := m.tasks[m.index]
@@ -94,8 +113,7 @@ func TestStreamTasks_Success(t *testing.T) {
 	task := &pb.Task{
 		Id:            uuid.New().String(),
 		ExpressionId:  uuid.New().String(),
-		Arg1Num:       1,
-		Arg2Num:       2,
+		Args:          []float64{1, 2},
 		Operator:      "+",
 		OperationTime: timestamppb.Now(),
 		FinalTask:     false,
@@ -106,19 +124,203 @@ func TestStreamTasks_Success(t *testing.T) {
 	}
 
 	mockClient.EXPECT().
-		AssignTasks(gomock.Any(), gomock.Any()).
+		AssignTasks(gomock.Any()).
 		Return(stream, nil)
 
 	c := &client.Impl{Client: mockClient}
-	err := c.StreamTasks(context.Background(), func(task *tasks.Task) error {
-		require.Equal(t, 1.0, task.Arg1.Value)
-		require.Equal(t, 2.0, task.Arg2.Value)
+	err := c.StreamTasks(context.Background(), nil, func(task *tasks.Task) error {
+		require.Equal(t, 1.0, task.Args[0].Value)
+		require.Equal(t, 2.0, task.Args[1].Value)
 		require.Equal(t, "+", task.Operator)
 		return nil
 	})
 	require.ErrorContains(t, err, "EOF")
 }
 
+func TestStreamTasks_SendsCredits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockOrchestratorServiceClient(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &mockStream{credits: make(chan int32, 2), wait: ctx.Done()}
+	mockClient.EXPECT().AssignTasks(gomock.Any()).Return(stream, nil)
+
+	credits := make(chan int32, 2)
+	credits <- 4
+	credits <- 1
+
+	done := make(chan error)
+	go func() {
+		done <- (&client.Impl{Client: mockClient, AgentID: "agent-1"}).StreamTasks(ctx, credits, func(*tasks.Task) error { return nil })
+	}()
+	require.Equal(t, int32(4), <-stream.credits)
+	require.Equal(t, int32(1), <-stream.credits)
+	cancel()
+	require.Error(t, <-done)
+}
+
+func TestStreamTasks_Decimal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockOrchestratorServiceClient(ctrl)
+
+	stream := &mockStream{
+		tasks: []*pb.Task{{
+			Id:              uuid.New().String(),
+			ExpressionId:    uuid.New().String(),
+			Args:            []float64{0.1, 0.2},
+			DecimalArgs:     []string{"0.1", "0.2"},
+			Operator:        "+",
+			OperationTime:   timestamppb.Now(),
+			Precision:       tasks.PrecisionDecimal,
+			Scale:           2,
+			AssignmentToken: "token",
+		}},
+	}
+
+	mockClient.EXPECT().
+		AssignTasks(gomock.Any()).
+		Return(stream, nil)
+
+	c := &client.Impl{Client: mockClient}
+	err := c.StreamTasks(context.Background(), nil, func(task *tasks.Task) error {
+		require.Equal(t, "0.1", task.Args[0].Decimal)
+		require.Equal(t, "0.2", task.Args[1].Decimal)
+		require.Equal(t, tasks.PrecisionDecimal, task.Precision)
+		require.Equal(t, 2, task.Scale)
+		require.Equal(t, "token", task.AssignmentToken)
+		return nil
+	})
+	require.ErrorContains(t, err, "EOF")
+}
+
+func TestSetTaskResult_Decimal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockOrchestratorServiceClient(ctrl)
+	task := tasks.Task{
+		ID:           uuid.New(),
+		ExpressionID: uuid.New(),
+		Args:         []tasks.Operand{{Value: 0.1, Decimal: "0.1"}, {Value: 0.2, Decimal: "0.2"}},
+		Operator:     "+",
+		Precision:    tasks.PrecisionDecimal,
+		Scale:        2,
+	}
+
+	mockClient.EXPECT().
+		SubmitTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *pb.SubmitTaskRequest, _ ...grpc.CallOption) (*pb.SubmitTaskResponse, error) {
+			require.Equal(t, "0.30", req.DecimalResult)
+			return &pb.SubmitTaskResponse{}, nil
+		})
+
+	c := &client.Impl{Client: mockClient}
+	err := c.SetTaskResult(context.Background(), task, tasks.Result{Value: 0.3, Decimal: "0.30"})
+	require.NoError(t, err)
+}
+
+func TestSetTaskResult_EvaluationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockOrchestratorServiceClient(ctrl)
+	task := tasks.Task{
+		ID:           uuid.New(),
+		ExpressionID: uuid.New(),
+		Args:         []tasks.Operand{{Value: 1}, {Value: 0}},
+		Operator:     "/",
+	}
+
+	mockClient.EXPECT().
+		SubmitTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *pb.SubmitTaskRequest, _ ...grpc.CallOption) (*pb.SubmitTaskResponse, error) {
+			require.Equal(t, tasks.ErrDivisionByZero.Error(), req.Error)
+			return &pb.SubmitTaskResponse{}, nil
+		})
+
+	c := &client.Impl{Client: mockClient}
+	err := c.SetTaskResult(context.Background(), task, tasks.Evaluate(&task))
+	require.NoError(t, err)
+}
+
+func TestSetTaskResult_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockOrchestratorServiceClient(ctrl)
+	mockClient.EXPECT().
+		SubmitTask(gomock.Any(), gomock.Any()).
+		Return(nil, status.Error(codes.NotFound, "task id not found"))
+
+	c := &client.Impl{Client: mockClient}
+	err := c.SetTaskResult(context.Background(), tasks.Task{ID: uuid.New()}, tasks.Result{Value: 1})
+	require.ErrorIs(t, err, client.ErrTaskNotFound)
+}
+
+func TestSetTaskResult_LeaseLost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockOrchestratorServiceClient(ctrl)
+	mockClient.EXPECT().
+		SubmitTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *pb.SubmitTaskRequest, _ ...grpc.CallOption) (*pb.SubmitTaskResponse, error) {
+			return nil, status.Error(codes.FailedPrecondition, "task lease is not held")
+		})
+
+	c := &client.Impl{Client: mockClient}
+	err := c.SetTaskResult(context.Background(), tasks.Task{ID: uuid.New()}, tasks.Result{Value: 1})
+	require.ErrorIs(t, err, client.ErrLeaseLost)
+}
+
+func TestHeartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockOrchestratorServiceClient(ctrl)
+	held, lost := uuid.New(), uuid.New()
+
+	mockClient.EXPECT().
+		Heartbeat(gomock.Any(), &pb.HeartbeatRequest{AgentId: "agent-1", TaskIds: []string{held.String(), lost.String()}}).
+		Return(&pb.HeartbeatResponse{LostTaskIds: []string{lost.String()}}, nil)
+
+	c := &client.Impl{Client: mockClient, AgentID: "agent-1"}
+	result, err := c.Heartbeat(context.Background(), []uuid.UUID{held, lost})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{lost}, result)
+
+	mockClient.EXPECT().Heartbeat(gomock.Any(), gomock.Any()).Return(nil, errors.New("unavailable"))
+	_, err = c.Heartbeat(context.Background(), []uuid.UUID{held})
+	require.ErrorContains(t, err, "failed to send heartbeat")
+}
+
+func TestRegisterAgent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mocks.NewMockOrchestratorServiceClient(ctrl)
+	mockClient.EXPECT().
+		RegisterAgent(gomock.Any(), &pb.RegisterAgentRequest{
+			AgentId:   "agent-1",
+			Hostname:  "host",
+			Version:   "1.0.0",
+			Capacity:  4,
+			Operators: []string{"+", "-"},
+		}).
+		Return(&pb.RegisterAgentResponse{}, nil)
+
+	c := &client.Impl{Client: mockClient, AgentID: "agent-1"}
+	registration := client.Registration{Hostname: "host", Version: "1.0.0", Capacity: 4, Operators: []string{"+", "-"}}
+	require.NoError(t, c.RegisterAgent(context.Background(), registration))
+
+	mockClient.EXPECT().RegisterAgent(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.InvalidArgument, "agent capacity must be greater than 0"))
+	err := c.RegisterAgent(context.Background(), client.Registration{})
+	require.ErrorContains(t, err, "failed to register agent")
+}
+
 func TestStreamTasks_AssignError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -126,11 +328,11 @@ func TestStreamTasks_AssignError(t *testing.T) {
 	mockClient := mocks.NewMockOrchestratorServiceClient(ctrl)
 
 	mockClient.EXPECT().
-		AssignTasks(gomock.Any(), gomock.Any()).
+		AssignTasks(gomock.Any()).
 		Return(nil, errors.New("assign failed"))
 
 	c := &client.Impl{Client: mockClient}
-	err := c.StreamTasks(context.Background(), func(t *tasks.Task) error { return nil })
+	err := c.StreamTasks(context.Background(), nil, func(t *tasks.Task) error { return nil })
 	require.ErrorContains(t, err, "assign failed")
 }
 
@@ -149,7 +351,7 @@ func TestNewClient_Success(t *testing.T) {
 		Port: lis.Addr().(*net.TCPAddr).Port,
 	}
 
-	cl, err := client.NewClient(cfg)
+	cl, err := client.NewClient(cfg, "agent-1")
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -165,8 +367,7 @@ func TestStreamTasks_HandlerError(t *testing.T) {
 	task := &pb.Task{
 		Id:            uuid.New().String(),
 		ExpressionId:  uuid.New().String(),
-		Arg1Num:       1,
-		Arg2Num:       2,
+		Args:          []float64{1, 2},
 		Operator:      "+",
 		OperationTime: timestamppb.Now(),
 		FinalTask:     false,
@@ -177,13 +378,13 @@ func TestStreamTasks_HandlerError(t *testing.T) {
 	}
 
 	mockClient.EXPECT().
-		AssignTasks(gomock.Any(), gomock.Any()).
+		AssignTasks(gomock.Any()).
 		Return(stream, nil)
 
 	c := &client.Impl{Client: mockClient}
 
 	handlerErr := errors.New("handler failed")
-	err := c.StreamTasks(context.Background(), func(task *tasks.Task) error {
+	err := c.StreamTasks(context.Background(), nil, func(task *tasks.Task) error {
 		return handlerErr
 	})
 
@@ -197,7 +398,7 @@ func TestNewClient_Error(t *testing.T) {
 		Port: 12345,
 	}
 
-	cl, err := client.NewClient(cfg)
+	cl, err := client.NewClient(cfg, "agent-1")
 	require.Error(t, err)
 	require.Nil(t, cl)
 	require.Contains(t, err.Error(), "could not connect")
@@ -217,11 +418,11 @@ func TestStreamTasks_ContextCancel(t *testing.T) {
 	}
 
 	mockClient.EXPECT().
-		AssignTasks(gomock.Any(), gomock.Any()).
+		AssignTasks(gomock.Any()).
 		Return(stream, nil)
 
 	c := &client.Impl{Client: mockClient}
-	err := c.StreamTasks(ctx, func(task *tasks.Task) error {
+	err := c.StreamTasks(ctx, nil, func(task *tasks.Task) error {
 		return nil
 	})
 	require.Error(t, err)
@@ -2,20 +2,32 @@ package config
 
 import (
 	"errors"
+	"os"
+	"time"
 
 	"github.com/alexGoLyceum/calculator-service/pkg/logging"
 
+	"github.com/google/uuid"
 	"github.com/spf13/viper"
 )
 
+// Version is reported to the orchestrator on registration. Release builds set it
+// with -ldflags "-X github.com/alexGoLyceum/calculator-service/agent/internal/config.Version=...".
+var Version = "dev"
+
 type OrchestratorConfig struct {
 	Host string
 	Port int
 }
 
 type Config struct {
-	Orchestrator OrchestratorConfig
-	Log          logging.LoggerConfig
+	ID                string
+	Hostname          string
+	Version           string
+	Orchestrator      OrchestratorConfig
+	ComputingPower    int
+	HeartbeatInterval time.Duration
+	Log               logging.LoggerConfig
 }
 
 func LoadConfig() (*Config, error) {
@@ -30,11 +42,35 @@ func LoadConfig() (*Config, error) {
 		return nil, errors.New("invalid orchestrator configuration: check ORCHESTRATOR_HOST and ORCHESTRATOR_PORT")
 	}
 
+	computingPower := viper.GetInt("COMPUTING_POWER")
+	if computingPower <= 0 {
+		return nil, errors.New("COMPUTING_POWER must be greater than 0")
+	}
+
+	heartbeatInterval := viper.GetDuration("HEARTBEAT_INTERVAL")
+	if heartbeatInterval <= 0 {
+		return nil, errors.New("HEARTBEAT_INTERVAL must be greater than 0")
+	}
+
+	id := viper.GetString("AGENT_ID")
+	if id == "" {
+		id = uuid.NewString()
+	}
+	hostname, _ := os.Hostname()
+
 	logger := logging.LoggerConfig{
 		Level:             viper.GetString("LOG_LEVEL"),
 		FilePath:          viper.GetString("LOG_PATH"),
 		EnableFileLogging: viper.GetBool("LOG_ENABLE_FILE_LOGGING"),
 	}
 
-	return &Config{Orchestrator: orchestrator, Log: logger}, nil
+	return &Config{
+		ID:                id,
+		Hostname:          hostname,
+		Version:           Version,
+		Orchestrator:      orchestrator,
+		ComputingPower:    computingPower,
+		HeartbeatInterval: heartbeatInterval,
+		Log:               logger,
+	}, nil
 }
@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/alexGoLyceum/calculator-service/agent/internal/config"
 
@@ -23,6 +24,8 @@ func setValidEnv(t *testing.T) {
 	t.Helper()
 	setEnv(t, "ORCHESTRATOR_HOST", "localhost")
 	setEnv(t, "ORCHESTRATOR_GRPC_PORT", "9090")
+	setEnv(t, "COMPUTING_POWER", "4")
+	setEnv(t, "HEARTBEAT_INTERVAL", "2s")
 
 	setEnv(t, "LOG_LEVEL", "info")
 	setEnv(t, "LOG_PATH", "/tmp/log")
@@ -37,6 +40,10 @@ func TestLoadConfig_Success(t *testing.T) {
 
 	require.Equal(t, "localhost", cfg.Orchestrator.Host)
 	require.Equal(t, 9090, cfg.Orchestrator.Port)
+	require.Equal(t, 4, cfg.ComputingPower)
+	require.Equal(t, 2*time.Second, cfg.HeartbeatInterval)
+	require.NotEmpty(t, cfg.ID)
+	require.Equal(t, config.Version, cfg.Version)
 
 	require.Equal(t, "info", cfg.Log.Level)
 	require.Equal(t, "/tmp/log", cfg.Log.FilePath)
@@ -50,3 +57,28 @@ func TestLoadConfig_InvalidPort(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid orchestrator configuration")
 }
+
+func TestLoadConfig_InvalidComputingPower(t *testing.T) {
+	setValidEnv(t)
+	setEnv(t, "COMPUTING_POWER", "0")
+
+	_, err := config.LoadConfig()
+	require.EqualError(t, err, "COMPUTING_POWER must be greater than 0")
+}
+
+func TestLoadConfig_InvalidHeartbeatInterval(t *testing.T) {
+	setValidEnv(t)
+	setEnv(t, "HEARTBEAT_INTERVAL", "0s")
+
+	_, err := config.LoadConfig()
+	require.EqualError(t, err, "HEARTBEAT_INTERVAL must be greater than 0")
+}
+
+func TestLoadConfig_AgentID(t *testing.T) {
+	setValidEnv(t)
+	setEnv(t, "AGENT_ID", "agent-7")
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	require.Equal(t, "agent-7", cfg.ID)
+}
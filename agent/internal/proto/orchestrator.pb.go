@@ -22,15 +22,129 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type RegisterAgentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Hostname      string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Capacity      int32                  `protobuf:"varint,4,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Operators     []string               `protobuf:"bytes,5,rep,name=operators,proto3" json:"operators,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterAgentRequest) Reset() {
+	*x = RegisterAgentRequest{}
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAgentRequest) ProtoMessage() {}
+
+func (x *RegisterAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAgentRequest.ProtoReflect.Descriptor instead.
+func (*RegisterAgentRequest) Descriptor() ([]byte, []int) {
+	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RegisterAgentRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *RegisterAgentRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *RegisterAgentRequest) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *RegisterAgentRequest) GetOperators() []string {
+	if x != nil {
+		return x.Operators
+	}
+	return nil
+}
+
+type RegisterAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterAgentResponse) Reset() {
+	*x = RegisterAgentResponse{}
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAgentResponse) ProtoMessage() {}
+
+func (x *RegisterAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAgentResponse.ProtoReflect.Descriptor instead.
+func (*RegisterAgentResponse) Descriptor() ([]byte, []int) {
+	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{1}
+}
+
 type AssignTasksRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Credits       int32                  `protobuf:"varint,1,opt,name=credits,proto3" json:"credits,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AssignTasksRequest) Reset() {
 	*x = AssignTasksRequest{}
-	mi := &file_agent_internal_orchestrator_proto_msgTypes[0]
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -42,7 +156,7 @@ func (x *AssignTasksRequest) String() string {
 func (*AssignTasksRequest) ProtoMessage() {}
 
 func (x *AssignTasksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_internal_orchestrator_proto_msgTypes[0]
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -55,20 +169,37 @@ func (x *AssignTasksRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AssignTasksRequest.ProtoReflect.Descriptor instead.
 func (*AssignTasksRequest) Descriptor() ([]byte, []int) {
-	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{0}
+	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AssignTasksRequest) GetCredits() int32 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+func (x *AssignTasksRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
 }
 
 type SubmitTaskRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
-	Result        float64                `protobuf:"fixed64,2,opt,name=result,proto3" json:"result,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TaskId          string                 `protobuf:"bytes,6,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	AssignmentToken string                 `protobuf:"bytes,7,opt,name=assignment_token,json=assignmentToken,proto3" json:"assignment_token,omitempty"`
+	Result          float64                `protobuf:"fixed64,2,opt,name=result,proto3" json:"result,omitempty"`
+	DecimalResult   string                 `protobuf:"bytes,3,opt,name=decimal_result,json=decimalResult,proto3" json:"decimal_result,omitempty"`
+	Error           string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *SubmitTaskRequest) Reset() {
 	*x = SubmitTaskRequest{}
-	mi := &file_agent_internal_orchestrator_proto_msgTypes[1]
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -80,7 +211,7 @@ func (x *SubmitTaskRequest) String() string {
 func (*SubmitTaskRequest) ProtoMessage() {}
 
 func (x *SubmitTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_internal_orchestrator_proto_msgTypes[1]
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -93,14 +224,21 @@ func (x *SubmitTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitTaskRequest.ProtoReflect.Descriptor instead.
 func (*SubmitTaskRequest) Descriptor() ([]byte, []int) {
-	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{1}
+	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *SubmitTaskRequest) GetTask() *Task {
+func (x *SubmitTaskRequest) GetTaskId() string {
 	if x != nil {
-		return x.Task
+		return x.TaskId
 	}
-	return nil
+	return ""
+}
+
+func (x *SubmitTaskRequest) GetAssignmentToken() string {
+	if x != nil {
+		return x.AssignmentToken
+	}
+	return ""
 }
 
 func (x *SubmitTaskRequest) GetResult() float64 {
@@ -110,6 +248,20 @@ func (x *SubmitTaskRequest) GetResult() float64 {
 	return 0
 }
 
+func (x *SubmitTaskRequest) GetDecimalResult() string {
+	if x != nil {
+		return x.DecimalResult
+	}
+	return ""
+}
+
+func (x *SubmitTaskRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type SubmitTaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -118,7 +270,7 @@ type SubmitTaskResponse struct {
 
 func (x *SubmitTaskResponse) Reset() {
 	*x = SubmitTaskResponse{}
-	mi := &file_agent_internal_orchestrator_proto_msgTypes[2]
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -130,7 +282,7 @@ func (x *SubmitTaskResponse) String() string {
 func (*SubmitTaskResponse) ProtoMessage() {}
 
 func (x *SubmitTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_internal_orchestrator_proto_msgTypes[2]
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -143,25 +295,125 @@ func (x *SubmitTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitTaskResponse.ProtoReflect.Descriptor instead.
 func (*SubmitTaskResponse) Descriptor() ([]byte, []int) {
-	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{2}
+	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{4}
 }
 
-type Task struct {
+type HeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	TaskIds       []string               `protobuf:"bytes,2,rep,name=task_ids,json=taskIds,proto3" json:"task_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HeartbeatRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetTaskIds() []string {
+	if x != nil {
+		return x.TaskIds
+	}
+	return nil
+}
+
+type HeartbeatResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ExpressionId  string                 `protobuf:"bytes,2,opt,name=expression_id,json=expressionId,proto3" json:"expression_id,omitempty"`
-	Arg1Num       float64                `protobuf:"fixed64,3,opt,name=arg1_num,json=arg1Num,proto3" json:"arg1_num,omitempty"`
-	Arg2Num       float64                `protobuf:"fixed64,4,opt,name=arg2_num,json=arg2Num,proto3" json:"arg2_num,omitempty"`
-	Operator      string                 `protobuf:"bytes,5,opt,name=operator,proto3" json:"operator,omitempty"`
-	OperationTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=operation_time,json=operationTime,proto3" json:"operation_time,omitempty"`
-	FinalTask     bool                   `protobuf:"varint,7,opt,name=final_task,json=finalTask,proto3" json:"final_task,omitempty"`
+	LostTaskIds   []string               `protobuf:"bytes,1,rep,name=lost_task_ids,json=lostTaskIds,proto3" json:"lost_task_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HeartbeatResponse) GetLostTaskIds() []string {
+	if x != nil {
+		return x.LostTaskIds
+	}
+	return nil
+}
+
+type Task struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpressionId    string                 `protobuf:"bytes,2,opt,name=expression_id,json=expressionId,proto3" json:"expression_id,omitempty"`
+	Operator        string                 `protobuf:"bytes,5,opt,name=operator,proto3" json:"operator,omitempty"`
+	OperationTime   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=operation_time,json=operationTime,proto3" json:"operation_time,omitempty"`
+	FinalTask       bool                   `protobuf:"varint,7,opt,name=final_task,json=finalTask,proto3" json:"final_task,omitempty"`
+	Args            []float64              `protobuf:"fixed64,8,rep,packed,name=args,proto3" json:"args,omitempty"`
+	Precision       string                 `protobuf:"bytes,9,opt,name=precision,proto3" json:"precision,omitempty"`
+	Scale           int32                  `protobuf:"varint,10,opt,name=scale,proto3" json:"scale,omitempty"`
+	DecimalArgs     []string               `protobuf:"bytes,11,rep,name=decimal_args,json=decimalArgs,proto3" json:"decimal_args,omitempty"`
+	Policy          string                 `protobuf:"bytes,12,opt,name=policy,proto3" json:"policy,omitempty"`
+	AssignmentToken string                 `protobuf:"bytes,13,opt,name=assignment_token,json=assignmentToken,proto3" json:"assignment_token,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *Task) Reset() {
 	*x = Task{}
-	mi := &file_agent_internal_orchestrator_proto_msgTypes[3]
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -173,7 +425,7 @@ func (x *Task) String() string {
 func (*Task) ProtoMessage() {}
 
 func (x *Task) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_internal_orchestrator_proto_msgTypes[3]
+	mi := &file_agent_internal_orchestrator_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -186,7 +438,7 @@ func (x *Task) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Task.ProtoReflect.Descriptor instead.
 func (*Task) Descriptor() ([]byte, []int) {
-	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{3}
+	return file_agent_internal_orchestrator_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Task) GetId() string {
@@ -203,64 +455,116 @@ func (x *Task) GetExpressionId() string {
 	return ""
 }
 
-func (x *Task) GetArg1Num() float64 {
+func (x *Task) GetOperator() string {
 	if x != nil {
-		return x.Arg1Num
+		return x.Operator
 	}
-	return 0
+	return ""
 }
 
-func (x *Task) GetArg2Num() float64 {
+func (x *Task) GetOperationTime() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Arg2Num
+		return x.OperationTime
 	}
-	return 0
+	return nil
 }
 
-func (x *Task) GetOperator() string {
+func (x *Task) GetFinalTask() bool {
 	if x != nil {
-		return x.Operator
+		return x.FinalTask
+	}
+	return false
+}
+
+func (x *Task) GetArgs() []float64 {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *Task) GetPrecision() string {
+	if x != nil {
+		return x.Precision
 	}
 	return ""
 }
 
-func (x *Task) GetOperationTime() *timestamppb.Timestamp {
+func (x *Task) GetScale() int32 {
 	if x != nil {
-		return x.OperationTime
+		return x.Scale
+	}
+	return 0
+}
+
+func (x *Task) GetDecimalArgs() []string {
+	if x != nil {
+		return x.DecimalArgs
 	}
 	return nil
 }
 
-func (x *Task) GetFinalTask() bool {
+func (x *Task) GetPolicy() string {
 	if x != nil {
-		return x.FinalTask
+		return x.Policy
 	}
-	return false
+	return ""
+}
+
+func (x *Task) GetAssignmentToken() string {
+	if x != nil {
+		return x.AssignmentToken
+	}
+	return ""
 }
 
 var File_agent_internal_orchestrator_proto protoreflect.FileDescriptor
 
 const file_agent_internal_orchestrator_proto_rawDesc = "" +
 	"\n" +
-	"!agent/internal/orchestrator.proto\x12\x05proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x14\n" +
-	"\x12AssignTasksRequest\"L\n" +
-	"\x11SubmitTaskRequest\x12\x1f\n" +
-	"\x04task\x18\x01 \x01(\v2\v.proto.TaskR\x04task\x12\x16\n" +
-	"\x06result\x18\x02 \x01(\x01R\x06result\"\x14\n" +
-	"\x12SubmitTaskResponse\"\xef\x01\n" +
+	"!agent/internal/orchestrator.proto\x12\x05proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa1\x01\n" +
+	"\x14RegisterAgentRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1a\n" +
+	"\bhostname\x18\x02 \x01(\tR\bhostname\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x1a\n" +
+	"\bcapacity\x18\x04 \x01(\x05R\bcapacity\x12\x1c\n" +
+	"\toperators\x18\x05 \x03(\tR\toperators\"\x17\n" +
+	"\x15RegisterAgentResponse\"I\n" +
+	"\x12AssignTasksRequest\x12\x18\n" +
+	"\acredits\x18\x01 \x01(\x05R\acredits\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"\xc8\x01\n" +
+	"\x11SubmitTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x06 \x01(\tR\x06taskId\x12)\n" +
+	"\x10assignment_token\x18\a \x01(\tR\x0fassignmentToken\x12\x16\n" +
+	"\x06result\x18\x02 \x01(\x01R\x06result\x12%\n" +
+	"\x0edecimal_result\x18\x03 \x01(\tR\rdecimalResult\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05errorJ\x04\b\x01\x10\x02J\x04\b\x05\x10\x06R\x04taskR\bagent_id\"\x14\n" +
+	"\x12SubmitTaskResponse\"H\n" +
+	"\x10HeartbeatRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x19\n" +
+	"\btask_ids\x18\x02 \x03(\tR\ataskIds\"7\n" +
+	"\x11HeartbeatResponse\x12\"\n" +
+	"\rlost_task_ids\x18\x01 \x03(\tR\vlostTaskIds\"\x87\x03\n" +
 	"\x04Task\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
-	"\rexpression_id\x18\x02 \x01(\tR\fexpressionId\x12\x19\n" +
-	"\barg1_num\x18\x03 \x01(\x01R\aarg1Num\x12\x19\n" +
-	"\barg2_num\x18\x04 \x01(\x01R\aarg2Num\x12\x1a\n" +
+	"\rexpression_id\x18\x02 \x01(\tR\fexpressionId\x12\x1a\n" +
 	"\boperator\x18\x05 \x01(\tR\boperator\x12A\n" +
 	"\x0eoperation_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\roperationTime\x12\x1d\n" +
 	"\n" +
-	"final_task\x18\a \x01(\bR\tfinalTask2\x91\x01\n" +
-	"\x13OrchestratorService\x127\n" +
-	"\vAssignTasks\x12\x19.proto.AssignTasksRequest\x1a\v.proto.Task0\x01\x12A\n" +
+	"final_task\x18\a \x01(\bR\tfinalTask\x12\x12\n" +
+	"\x04args\x18\b \x03(\x01R\x04args\x12\x1c\n" +
+	"\tprecision\x18\t \x01(\tR\tprecision\x12\x14\n" +
+	"\x05scale\x18\n" +
+	" \x01(\x05R\x05scale\x12!\n" +
+	"\fdecimal_args\x18\v \x03(\tR\vdecimalArgs\x12\x16\n" +
+	"\x06policy\x18\f \x01(\tR\x06policy\x12)\n" +
+	"\x10assignment_token\x18\r \x01(\tR\x0fassignmentTokenJ\x04\b\x03\x10\x04J\x04\b\x04\x10\x05R\barg1_numR\barg2_num2\x9f\x02\n" +
+	"\x13OrchestratorService\x12J\n" +
+	"\rRegisterAgent\x12\x1b.proto.RegisterAgentRequest\x1a\x1c.proto.RegisterAgentResponse\x129\n" +
+	"\vAssignTasks\x12\x19.proto.AssignTasksRequest\x1a\v.proto.Task(\x010\x01\x12A\n" +
 	"\n" +
-	"SubmitTask\x12\x18.proto.SubmitTaskRequest\x1a\x19.proto.SubmitTaskResponseB\x18Z\x16./agent/internal/protob\x06proto3"
+	"SubmitTask\x12\x18.proto.SubmitTaskRequest\x1a\x19.proto.SubmitTaskResponse\x12>\n" +
+	"\tHeartbeat\x12\x17.proto.HeartbeatRequest\x1a\x18.proto.HeartbeatResponseB\x18Z\x16./agent/internal/protob\x06proto3"
 
 var (
 	file_agent_internal_orchestrator_proto_rawDescOnce sync.Once
@@ -274,26 +578,33 @@ func file_agent_internal_orchestrator_proto_rawDescGZIP() []byte {
 	return file_agent_internal_orchestrator_proto_rawDescData
 }
 
-var file_agent_internal_orchestrator_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_agent_internal_orchestrator_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_agent_internal_orchestrator_proto_goTypes = []any{
-	(*AssignTasksRequest)(nil),    // 0: proto.AssignTasksRequest
-	(*SubmitTaskRequest)(nil),     // 1: proto.SubmitTaskRequest
-	(*SubmitTaskResponse)(nil),    // 2: proto.SubmitTaskResponse
-	(*Task)(nil),                  // 3: proto.Task
-	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+	(*RegisterAgentRequest)(nil),  // 0: proto.RegisterAgentRequest
+	(*RegisterAgentResponse)(nil), // 1: proto.RegisterAgentResponse
+	(*AssignTasksRequest)(nil),    // 2: proto.AssignTasksRequest
+	(*SubmitTaskRequest)(nil),     // 3: proto.SubmitTaskRequest
+	(*SubmitTaskResponse)(nil),    // 4: proto.SubmitTaskResponse
+	(*HeartbeatRequest)(nil),      // 5: proto.HeartbeatRequest
+	(*HeartbeatResponse)(nil),     // 6: proto.HeartbeatResponse
+	(*Task)(nil),                  // 7: proto.Task
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
 }
 var file_agent_internal_orchestrator_proto_depIdxs = []int32{
-	3, // 0: proto.SubmitTaskRequest.task:type_name -> proto.Task
-	4, // 1: proto.Task.operation_time:type_name -> google.protobuf.Timestamp
-	0, // 2: proto.OrchestratorService.AssignTasks:input_type -> proto.AssignTasksRequest
-	1, // 3: proto.OrchestratorService.SubmitTask:input_type -> proto.SubmitTaskRequest
-	3, // 4: proto.OrchestratorService.AssignTasks:output_type -> proto.Task
-	2, // 5: proto.OrchestratorService.SubmitTask:output_type -> proto.SubmitTaskResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	8, // 0: proto.Task.operation_time:type_name -> google.protobuf.Timestamp
+	0, // 1: proto.OrchestratorService.RegisterAgent:input_type -> proto.RegisterAgentRequest
+	2, // 2: proto.OrchestratorService.AssignTasks:input_type -> proto.AssignTasksRequest
+	3, // 3: proto.OrchestratorService.SubmitTask:input_type -> proto.SubmitTaskRequest
+	5, // 4: proto.OrchestratorService.Heartbeat:input_type -> proto.HeartbeatRequest
+	1, // 5: proto.OrchestratorService.RegisterAgent:output_type -> proto.RegisterAgentResponse
+	7, // 6: proto.OrchestratorService.AssignTasks:output_type -> proto.Task
+	4, // 7: proto.OrchestratorService.SubmitTask:output_type -> proto.SubmitTaskResponse
+	6, // 8: proto.OrchestratorService.Heartbeat:output_type -> proto.HeartbeatResponse
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_agent_internal_orchestrator_proto_init() }
@@ -307,7 +618,7 @@ func file_agent_internal_orchestrator_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_agent_internal_orchestrator_proto_rawDesc), len(file_agent_internal_orchestrator_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
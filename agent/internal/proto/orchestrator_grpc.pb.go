@@ -19,16 +19,20 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OrchestratorService_AssignTasks_FullMethodName = "/proto.OrchestratorService/AssignTasks"
-	OrchestratorService_SubmitTask_FullMethodName  = "/proto.OrchestratorService/SubmitTask"
+	OrchestratorService_RegisterAgent_FullMethodName = "/proto.OrchestratorService/RegisterAgent"
+	OrchestratorService_AssignTasks_FullMethodName   = "/proto.OrchestratorService/AssignTasks"
+	OrchestratorService_SubmitTask_FullMethodName    = "/proto.OrchestratorService/SubmitTask"
+	OrchestratorService_Heartbeat_FullMethodName     = "/proto.OrchestratorService/Heartbeat"
 )
 
 // OrchestratorServiceClient is the client API for OrchestratorService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type OrchestratorServiceClient interface {
-	AssignTasks(ctx context.Context, in *AssignTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Task], error)
+	RegisterAgent(ctx context.Context, in *RegisterAgentRequest, opts ...grpc.CallOption) (*RegisterAgentResponse, error)
+	AssignTasks(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AssignTasksRequest, Task], error)
 	SubmitTask(ctx context.Context, in *SubmitTaskRequest, opts ...grpc.CallOption) (*SubmitTaskResponse, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
 }
 
 type orchestratorServiceClient struct {
@@ -39,24 +43,28 @@ func NewOrchestratorServiceClient(cc grpc.ClientConnInterface) OrchestratorServi
 	return &orchestratorServiceClient{cc}
 }
 
-func (c *orchestratorServiceClient) AssignTasks(ctx context.Context, in *AssignTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Task], error) {
+func (c *orchestratorServiceClient) RegisterAgent(ctx context.Context, in *RegisterAgentRequest, opts ...grpc.CallOption) (*RegisterAgentResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &OrchestratorService_ServiceDesc.Streams[0], OrchestratorService_AssignTasks_FullMethodName, cOpts...)
+	out := new(RegisterAgentResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_RegisterAgent_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[AssignTasksRequest, Task]{ClientStream: stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) AssignTasks(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AssignTasksRequest, Task], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrchestratorService_ServiceDesc.Streams[0], OrchestratorService_AssignTasks_FullMethodName, cOpts...)
+	if err != nil {
 		return nil, err
 	}
+	x := &grpc.GenericClientStream[AssignTasksRequest, Task]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type OrchestratorService_AssignTasksClient = grpc.ServerStreamingClient[Task]
+type OrchestratorService_AssignTasksClient = grpc.BidiStreamingClient[AssignTasksRequest, Task]
 
 func (c *orchestratorServiceClient) SubmitTask(ctx context.Context, in *SubmitTaskRequest, opts ...grpc.CallOption) (*SubmitTaskResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
@@ -68,12 +76,24 @@ func (c *orchestratorServiceClient) SubmitTask(ctx context.Context, in *SubmitTa
 	return out, nil
 }
 
+func (c *orchestratorServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrchestratorServiceServer is the server API for OrchestratorService service.
 // All implementations must embed UnimplementedOrchestratorServiceServer
 // for forward compatibility.
 type OrchestratorServiceServer interface {
-	AssignTasks(*AssignTasksRequest, grpc.ServerStreamingServer[Task]) error
+	RegisterAgent(context.Context, *RegisterAgentRequest) (*RegisterAgentResponse, error)
+	AssignTasks(grpc.BidiStreamingServer[AssignTasksRequest, Task]) error
 	SubmitTask(context.Context, *SubmitTaskRequest) (*SubmitTaskResponse, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
 	mustEmbedUnimplementedOrchestratorServiceServer()
 }
 
@@ -84,12 +104,18 @@ type OrchestratorServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedOrchestratorServiceServer struct{}
 
-func (UnimplementedOrchestratorServiceServer) AssignTasks(*AssignTasksRequest, grpc.ServerStreamingServer[Task]) error {
+func (UnimplementedOrchestratorServiceServer) RegisterAgent(context.Context, *RegisterAgentRequest) (*RegisterAgentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterAgent not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) AssignTasks(grpc.BidiStreamingServer[AssignTasksRequest, Task]) error {
 	return status.Errorf(codes.Unimplemented, "method AssignTasks not implemented")
 }
 func (UnimplementedOrchestratorServiceServer) SubmitTask(context.Context, *SubmitTaskRequest) (*SubmitTaskResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SubmitTask not implemented")
 }
+func (UnimplementedOrchestratorServiceServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
 func (UnimplementedOrchestratorServiceServer) mustEmbedUnimplementedOrchestratorServiceServer() {}
 func (UnimplementedOrchestratorServiceServer) testEmbeddedByValue()                             {}
 
@@ -111,16 +137,30 @@ func RegisterOrchestratorServiceServer(s grpc.ServiceRegistrar, srv Orchestrator
 	s.RegisterService(&OrchestratorService_ServiceDesc, srv)
 }
 
-func _OrchestratorService_AssignTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(AssignTasksRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _OrchestratorService_RegisterAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(OrchestratorServiceServer).AssignTasks(m, &grpc.GenericServerStream[AssignTasksRequest, Task]{ServerStream: stream})
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).RegisterAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_RegisterAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).RegisterAgent(ctx, req.(*RegisterAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_AssignTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OrchestratorServiceServer).AssignTasks(&grpc.GenericServerStream[AssignTasksRequest, Task]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type OrchestratorService_AssignTasksServer = grpc.ServerStreamingServer[Task]
+type OrchestratorService_AssignTasksServer = grpc.BidiStreamingServer[AssignTasksRequest, Task]
 
 func _OrchestratorService_SubmitTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SubmitTaskRequest)
@@ -140,6 +180,24 @@ func _OrchestratorService_SubmitTask_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrchestratorService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrchestratorService_ServiceDesc is the grpc.ServiceDesc for OrchestratorService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -147,16 +205,25 @@ var OrchestratorService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.OrchestratorService",
 	HandlerType: (*OrchestratorServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterAgent",
+			Handler:    _OrchestratorService_RegisterAgent_Handler,
+		},
 		{
 			MethodName: "SubmitTask",
 			Handler:    _OrchestratorService_SubmitTask_Handler,
 		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _OrchestratorService_Heartbeat_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "AssignTasks",
 			Handler:       _OrchestratorService_AssignTasks_Handler,
 			ServerStreams: true,
+			ClientStreams: true,
 		},
 	},
 	Metadata: "agent/internal/orchestrator.proto",
@@ -0,0 +1,222 @@
+package tasks
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+const maxDecimalExponent = 10000
+
+// maxDecimalBits bounds the size of decimal operands and results so chained
+// powers and shifts cannot grow past what the orchestrator accepts.
+const maxDecimalBits = 1 << 16
+
+func ratBits(value *big.Rat) int {
+	return value.Num().BitLen() + value.Denom().BitLen()
+}
+
+// resultBits estimates the size of results that grow faster than their
+// operands, so they can be rejected before they are computed.
+func resultBits(operator string, args []*big.Rat) int64 {
+	if len(args) != 2 || !args[1].IsInt() || !args[1].Num().IsInt64() {
+		return 0
+	}
+	n := args[1].Num().Int64()
+	switch operator {
+	case "^":
+		base := args[0]
+		if base.Sign() == 0 || (base.IsInt() && base.Num().CmpAbs(big.NewInt(1)) == 0) {
+			return 0
+		}
+		return int64(ratBits(base)-2) * max(n, -n)
+	case "<<":
+		if n > 0 {
+			return int64(args[0].Num().BitLen()) + n
+		}
+	}
+	return 0
+}
+
+func CalculateDecimal(task *Task) Result {
+	waitForOperationTime(task)
+
+	args := make([]*big.Rat, len(task.Args))
+	for i, arg := range task.Args {
+		value, ok := new(big.Rat).SetString(arg.Decimal)
+		if !ok {
+			return Result{Value: math.NaN(), Decimal: "NaN"}
+		}
+		if ratBits(value) > maxDecimalBits {
+			return Result{Value: math.NaN(), Decimal: "NaN", Error: ErrDecimalTooLarge.Error()}
+		}
+		args[i] = value
+	}
+	if resultBits(task.Operator, args) > maxDecimalBits {
+		return Result{Value: math.NaN(), Decimal: "NaN", Error: ErrDecimalTooLarge.Error()}
+	}
+
+	result := calculateRat(task.Operator, args, task.Scale)
+	if result == nil {
+		return Result{Value: math.NaN(), Decimal: "NaN"}
+	}
+	if ratBits(result) > maxDecimalBits {
+		return Result{Value: math.NaN(), Decimal: "NaN", Error: ErrDecimalTooLarge.Error()}
+	}
+
+	decimal := result.FloatString(task.Scale)
+	value, _ := strconv.ParseFloat(decimal, 64)
+	return Result{Value: value, Decimal: decimal}
+}
+
+func calculateRat(operator string, args []*big.Rat, scale int) *big.Rat {
+	if len(args) == 1 {
+		switch operator {
+		case "neg":
+			return new(big.Rat).Neg(args[0])
+		case "abs":
+			return new(big.Rat).Abs(args[0])
+		case "sqrt":
+			return sqrtRat(args[0], scale)
+		case "!":
+			return boolRat(args[0].Sign() == 0)
+		}
+	}
+
+	if len(args) == 2 {
+		switch operator {
+		case "+":
+			return new(big.Rat).Add(args[0], args[1])
+		case "-":
+			return new(big.Rat).Sub(args[0], args[1])
+		case "*":
+			return new(big.Rat).Mul(args[0], args[1])
+		case "/":
+			if args[1].Sign() != 0 {
+				return new(big.Rat).Quo(args[0], args[1])
+			}
+		case "^":
+			return powRat(args[0], args[1])
+		case "<":
+			return boolRat(args[0].Cmp(args[1]) < 0)
+		case "<=":
+			return boolRat(args[0].Cmp(args[1]) <= 0)
+		case ">":
+			return boolRat(args[0].Cmp(args[1]) > 0)
+		case ">=":
+			return boolRat(args[0].Cmp(args[1]) >= 0)
+		case "==":
+			return boolRat(args[0].Cmp(args[1]) == 0)
+		case "!=":
+			return boolRat(args[0].Cmp(args[1]) != 0)
+		case "&&":
+			return boolRat(args[0].Sign() != 0 && args[1].Sign() != 0)
+		case "||":
+			return boolRat(args[0].Sign() != 0 || args[1].Sign() != 0)
+		case "%", "//", "&", "|", "xor", "<<", ">>":
+			if args[0].IsInt() && args[1].IsInt() {
+				if result := integerRat(operator, args[0].Num(), args[1].Num()); result != nil {
+					return new(big.Rat).SetInt(result)
+				}
+			}
+		}
+	}
+
+	if len(args) > 0 {
+		switch operator {
+		case "min":
+			result := args[0]
+			for _, arg := range args[1:] {
+				if arg.Cmp(result) < 0 {
+					result = arg
+				}
+			}
+			return result
+		case "max":
+			result := args[0]
+			for _, arg := range args[1:] {
+				if arg.Cmp(result) > 0 {
+					result = arg
+				}
+			}
+			return result
+		}
+	}
+	return nil
+}
+
+func integerRat(operator string, x, y *big.Int) *big.Int {
+	switch operator {
+	case "%", "//":
+		if y.Sign() == 0 {
+			return nil
+		}
+		q, m := new(big.Int).QuoRem(x, y, new(big.Int))
+		if m.Sign() != 0 && (m.Sign() < 0) != (y.Sign() < 0) {
+			m.Add(m, y)
+			q.Sub(q, big.NewInt(1))
+		}
+		if operator == "%" {
+			return m
+		}
+		return q
+	case "&":
+		return new(big.Int).And(x, y)
+	case "|":
+		return new(big.Int).Or(x, y)
+	case "xor":
+		return new(big.Int).Xor(x, y)
+	case "<<", ">>":
+		if !y.IsInt64() || y.Sign() < 0 || y.Int64() > maxDecimalExponent {
+			return nil
+		}
+		if operator == "<<" {
+			return new(big.Int).Lsh(x, uint(y.Int64()))
+		}
+		return new(big.Int).Rsh(x, uint(y.Int64()))
+	}
+	return nil
+}
+
+func boolRat(value bool) *big.Rat {
+	return big.NewRat(int64(boolValue(value)), 1)
+}
+
+func powRat(base, exponent *big.Rat) *big.Rat {
+	if !exponent.IsInt() || !exponent.Num().IsInt64() {
+		return nil
+	}
+	n := exponent.Num().Int64()
+	if n > maxDecimalExponent || n < -maxDecimalExponent {
+		return nil
+	}
+	if n < 0 {
+		if base.Sign() == 0 {
+			return nil
+		}
+		base = new(big.Rat).Inv(base)
+		n = -n
+	}
+
+	result := big.NewRat(1, 1)
+	square := new(big.Rat).Set(base)
+	for n > 0 {
+		if n&1 == 1 {
+			result.Mul(result, square)
+		}
+		square.Mul(square, square)
+		n >>= 1
+	}
+	return result
+}
+
+func sqrtRat(value *big.Rat, scale int) *big.Rat {
+	if value.Sign() < 0 {
+		return nil
+	}
+	prec := uint(value.Num().BitLen()+value.Denom().BitLen()) + uint(scale)*4 + 64
+	root := new(big.Float).SetPrec(prec).SetRat(value)
+	root.Sqrt(root)
+	result, _ := root.Rat(nil)
+	return result
+}
@@ -0,0 +1,235 @@
+package tasks_test
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/alexGoLyceum/calculator-service/agent/internal/tasks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decimalTask(operator string, scale int, args ...string) *tasks.Task {
+	operands := make([]tasks.Operand, len(args))
+	for i, arg := range args {
+		operands[i] = tasks.Operand{Decimal: arg}
+	}
+	return &tasks.Task{
+		Args:      operands,
+		Operator:  operator,
+		Precision: tasks.PrecisionDecimal,
+		Scale:     scale,
+	}
+}
+
+func TestCalculateDecimal(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     *tasks.Task
+		expected string
+	}{
+		{"exact addition", decimalTask("+", 2, "0.1", "0.2"), "0.30"},
+		{"subtraction", decimalTask("-", 1, "1", "0.9"), "0.1"},
+		{"multiplication rounds half away from zero", decimalTask("*", 2, "1.005", "1"), "1.01"},
+		{"division rounded to scale", decimalTask("/", 4, "1", "3"), "0.3333"},
+		{"negative division", decimalTask("/", 2, "-2", "3"), "-0.67"},
+		{"zero scale", decimalTask("/", 0, "7", "2"), "4"},
+		{"negation", decimalTask("neg", 1, "2.5"), "-2.5"},
+		{"absolute value", decimalTask("abs", 3, "-0.125"), "0.125"},
+		{"integer power", decimalTask("^", 3, "1.1", "3"), "1.331"},
+		{"negative power", decimalTask("^", 3, "2", "-3"), "0.125"},
+		{"square root", decimalTask("sqrt", 10, "2"), "1.4142135624"},
+		{"minimum", decimalTask("min", 1, "0.3", "-0.1", "0.2"), "-0.1"},
+		{"maximum", decimalTask("max", 1, "0.3", "-0.1", "0.2"), "0.3"},
+		{"large values", decimalTask("+", 0, "12345678901234567890", "1"), "12345678901234567891"},
+		{"exact comparison", decimalTask("==", 2, "0.30", "0.3"), "1.00"},
+		{"not equal", decimalTask("!=", 0, "0.1", "0.10000000000000000001"), "1"},
+		{"less or equal", decimalTask("<=", 0, "2", "1.5"), "0"},
+		{"logical and", decimalTask("&&", 0, "0.5", "-1"), "1"},
+		{"logical not", decimalTask("!", 0, "0.000"), "1"},
+		{"modulo of large integers", decimalTask("%", 0, "123456789012345678901234567890", "97"), "52"},
+		{"negative modulo is floored", decimalTask("%", 0, "7", "-3"), "-2"},
+		{"integer division", decimalTask("//", 2, "-7", "2"), "-4.00"},
+		{"bitwise xor", decimalTask("xor", 0, "255", "15"), "240"},
+		{"wide shift", decimalTask("<<", 0, "1", "70"), "1180591620717411303424"},
+		{"power of one is not bounded", decimalTask("^", 0, "-1", "9999"), "-1"},
+		{"power within budget", decimalTask("^", 0, "2", "10000"), new(big.Int).Lsh(big.NewInt(1), 10000).String()},
+		{"shift right", decimalTask(">>", 0, "-9", "1"), "-5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tasks.CalculateDecimal(tt.task)
+			assert.Equal(t, tt.expected, result.Decimal)
+		})
+	}
+}
+
+func TestCalculateDecimal_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		task *tasks.Task
+	}{
+		{"division by zero", decimalTask("/", 2, "1", "0")},
+		{"fractional exponent", decimalTask("^", 2, "2", "0.5")},
+		{"zero to negative power", decimalTask("^", 2, "0", "-1")},
+		{"negative square root", decimalTask("sqrt", 2, "-4")},
+		{"unsupported function", decimalTask("sin", 2, "1")},
+		{"malformed operand", decimalTask("+", 2, "abc", "1")},
+		{"fractional modulo operand", decimalTask("%", 2, "7.5", "2")},
+		{"modulo by zero", decimalTask("%", 0, "7", "0")},
+		{"negative shift", decimalTask("<<", 0, "1", "-1")},
+		{"power too large", decimalTask("^", 0, "99999", "10000")},
+		{"chained power too large", decimalTask("^", 0, strings.Repeat("9", 9543), "1000")},
+		{"shift too large", decimalTask("<<", 0, "1"+strings.Repeat("0", 19000), "10000")},
+		{"operand too large", decimalTask("+", 0, strings.Repeat("9", 20000), "1")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tasks.CalculateDecimal(tt.task)
+			assert.Equal(t, "NaN", result.Decimal)
+			assert.True(t, math.IsNaN(result.Value))
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	a, b := 0.1, 0.2
+	floatTask := &tasks.Task{
+		Args:     []tasks.Operand{{Value: a}, {Value: b}},
+		Operator: "+",
+	}
+	assert.Equal(t, tasks.Result{Value: a + b}, tasks.Evaluate(floatTask))
+
+	result := tasks.Evaluate(decimalTask("+", 1, "0.1", "0.2"))
+	assert.Equal(t, tasks.Result{Value: 0.3, Decimal: "0.3"}, result)
+}
+
+func TestEvaluate_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     *tasks.Task
+		expected error
+	}{
+		{
+			name:     "float division by zero",
+			task:     &tasks.Task{Args: []tasks.Operand{{Value: 1}, {Value: 0}}, Operator: "/"},
+			expected: tasks.ErrDivisionByZero,
+		},
+		{
+			name:     "float modulo by zero",
+			task:     &tasks.Task{Args: []tasks.Operand{{Value: 7}, {Value: 0}}, Operator: "%"},
+			expected: tasks.ErrDivisionByZero,
+		},
+		{
+			name:     "float non-integer operand",
+			task:     &tasks.Task{Args: []tasks.Operand{{Value: 1.5}, {Value: 2}}, Operator: "&"},
+			expected: tasks.ErrIntegerOperandRequired,
+		},
+		{
+			name:     "float square root of negative",
+			task:     &tasks.Task{Args: []tasks.Operand{{Value: -4}}, Operator: "sqrt"},
+			expected: tasks.ErrInvalidResult,
+		},
+		{
+			name:     "float overflow",
+			task:     &tasks.Task{Args: []tasks.Operand{{Value: 1e308}, {Value: 10}}, Operator: "*"},
+			expected: tasks.ErrInvalidResult,
+		},
+		{
+			name:     "decimal division by zero",
+			task:     decimalTask("/", 2, "1", "0.00"),
+			expected: tasks.ErrDivisionByZero,
+		},
+		{
+			name:     "decimal non-integer operand",
+			task:     decimalTask("//", 2, "7.5", "2"),
+			expected: tasks.ErrIntegerOperandRequired,
+		},
+		{
+			name:     "decimal power too large",
+			task:     decimalTask("^", 0, "99999", "10000"),
+			expected: tasks.ErrDecimalTooLarge,
+		},
+		{
+			name:     "decimal unsupported operator",
+			task:     decimalTask("sin", 2, "1"),
+			expected: tasks.ErrInvalidResult,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tasks.Evaluate(tt.task)
+			assert.Equal(t, tt.expected.Error(), result.Error)
+		})
+	}
+}
+
+func TestEvaluate_NumericPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     *tasks.Task
+		value    float64
+		expected error
+	}{
+		{
+			name:     "strict overflow",
+			task:     &tasks.Task{Args: []tasks.Operand{{Value: 1e308}, {Value: 10}}, Operator: "*", Policy: tasks.PolicyStrict},
+			value:    math.Inf(1),
+			expected: tasks.ErrInvalidResult,
+		},
+		{
+			name:  "ieee overflow",
+			task:  &tasks.Task{Args: []tasks.Operand{{Value: 1e308}, {Value: 10}}, Operator: "*", Policy: tasks.PolicyIEEE},
+			value: math.Inf(1),
+		},
+		{
+			name:  "ieee division by zero",
+			task:  &tasks.Task{Args: []tasks.Operand{{Value: -1}, {Value: 0}}, Operator: "/", Policy: tasks.PolicyIEEE},
+			value: math.Inf(-1),
+		},
+		{
+			name:     "ieee modulo by zero",
+			task:     &tasks.Task{Args: []tasks.Operand{{Value: 1}, {Value: 0}}, Operator: "%", Policy: tasks.PolicyIEEE},
+			value:    math.NaN(),
+			expected: tasks.ErrDivisionByZero,
+		},
+		{
+			name:  "saturate overflow",
+			task:  &tasks.Task{Args: []tasks.Operand{{Value: -1e308}, {Value: 10}}, Operator: "*", Policy: tasks.PolicySaturate},
+			value: -math.MaxFloat64,
+		},
+		{
+			name:     "saturate nan",
+			task:     &tasks.Task{Args: []tasks.Operand{{Value: -1}}, Operator: "sqrt", Policy: tasks.PolicySaturate},
+			value:    math.NaN(),
+			expected: tasks.ErrInvalidResult,
+		},
+		{
+			name:     "saturate division by zero",
+			task:     &tasks.Task{Args: []tasks.Operand{{Value: 1}, {Value: 0}}, Operator: "/", Policy: tasks.PolicySaturate},
+			value:    math.NaN(),
+			expected: tasks.ErrDivisionByZero,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tasks.Evaluate(tt.task)
+			if math.IsNaN(tt.value) {
+				assert.True(t, math.IsNaN(result.Value))
+			} else {
+				assert.Equal(t, tt.value, result.Value)
+			}
+			if tt.expected != nil {
+				assert.Equal(t, tt.expected.Error(), result.Error)
+			} else {
+				assert.Empty(t, result.Error)
+			}
+		})
+	}
+}
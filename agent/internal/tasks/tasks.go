@@ -1,7 +1,9 @@
 package tasks
 
 import (
+	"errors"
 	"math"
+	"math/big"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,40 +11,261 @@ import (
 
 type Status string
 
+const PrecisionDecimal = "decimal"
+
+const (
+	PolicyStrict   = "strict"
+	PolicyIEEE     = "ieee"
+	PolicySaturate = "saturate"
+)
+
 type Task struct {
-	ID            uuid.UUID `json:"id"`
-	ExpressionID  uuid.UUID `json:"expression_id"`
-	Arg1          Operand   `json:"arg1"`
-	Arg2          Operand   `json:"arg2"`
-	Operator      string    `json:"operator"`
-	OperationTime time.Time `json:"operation_time"`
-	FinalTask     bool      `json:"final_task"`
+	ID              uuid.UUID `json:"id"`
+	ExpressionID    uuid.UUID `json:"expression_id"`
+	Args            []Operand `json:"args"`
+	Operator        string    `json:"operator"`
+	OperationTime   time.Time `json:"operation_time"`
+	FinalTask       bool      `json:"final_task"`
+	Precision       string    `json:"precision"`
+	Scale           int       `json:"scale"`
+	Policy          string    `json:"policy"`
+	AssignmentToken string    `json:"assignment_token"`
 }
 
 type Operand struct {
-	Value  float64   `json:"value"`
-	TaskID uuid.UUID `json:"task_id"`
+	Value   float64   `json:"value"`
+	Decimal string    `json:"decimal"`
+	TaskID  uuid.UUID `json:"task_id"`
 }
 
-func Calculate(task *Task) float64 {
-	if !task.OperationTime.IsZero() {
-		duration := task.OperationTime.Sub(time.Now())
-		if duration > 0 {
-			time.Sleep(duration)
+type Result struct {
+	Value   float64
+	Decimal string
+	Error   string
+}
+
+var Operators = []string{
+	"+", "-", "*", "/", "^", "neg", "sqrt", "sin", "cos", "log", "abs", "min", "max",
+	"<", "<=", ">", ">=", "==", "!=", "&&", "||", "!", "%", "//", "&", "|", "xor", "<<", ">>",
+}
+
+var (
+	ErrDivisionByZero         = errors.New("division by zero")
+	ErrIntegerOperandRequired = errors.New("operator requires integer operands")
+	ErrInvalidResult          = errors.New("result is not a finite number")
+	ErrDecimalTooLarge        = errors.New("decimal value is too large")
+)
+
+func Evaluate(task *Task) Result {
+	var result Result
+	if task.Precision == PrecisionDecimal {
+		result = CalculateDecimal(task)
+		if result.Decimal == "NaN" && result.Error == "" {
+			result.Error = evaluationError(task).Error()
+		}
+	} else {
+		result = Result{Value: Calculate(task)}
+		if math.IsNaN(result.Value) || math.IsInf(result.Value, 0) {
+			result = applyPolicy(task, result)
 		}
 	}
+	return result
+}
 
+func applyPolicy(task *Task, result Result) Result {
+	err := evaluationError(task)
+	switch {
+	case !errors.Is(err, ErrInvalidResult):
+	case task.Policy == PolicyIEEE:
+		return result
+	case task.Policy == PolicySaturate && math.IsInf(result.Value, 0):
+		result.Value = math.Copysign(math.MaxFloat64, result.Value)
+		return result
+	}
+	result.Error = err.Error()
+	return result
+}
+
+func evaluationError(task *Task) error {
+	switch task.Operator {
+	case "/", "//", "%":
+		if task.Operator == "/" && task.Policy == PolicyIEEE {
+			break
+		}
+		if len(task.Args) == 2 && isZero(task.Args[1]) {
+			return ErrDivisionByZero
+		}
+	}
 	switch task.Operator {
-	case "+":
-		return task.Arg1.Value + task.Arg2.Value
-	case "-":
-		return task.Arg1.Value - task.Arg2.Value
-	case "*":
-		return task.Arg1.Value * task.Arg2.Value
-	case "/":
-		if task.Arg2.Value != 0 {
-			return task.Arg1.Value / task.Arg2.Value
+	case "%", "//", "&", "|", "xor", "<<", ">>":
+		for _, arg := range task.Args {
+			if !isInteger(arg) {
+				return ErrIntegerOperandRequired
+			}
+		}
+	}
+	return ErrInvalidResult
+}
+
+func isZero(arg Operand) bool {
+	if arg.Decimal != "" {
+		value, ok := new(big.Rat).SetString(arg.Decimal)
+		return ok && value.Sign() == 0
+	}
+	return arg.Value == 0
+}
+
+func isInteger(arg Operand) bool {
+	if arg.Decimal != "" {
+		value, ok := new(big.Rat).SetString(arg.Decimal)
+		return ok && value.IsInt()
+	}
+	return arg.Value == math.Trunc(arg.Value)
+}
+
+func Calculate(task *Task) float64 {
+	waitForOperationTime(task)
+
+	args := make([]float64, len(task.Args))
+	for i, arg := range task.Args {
+		args[i] = arg.Value
+	}
+
+	if len(args) == 1 {
+		switch task.Operator {
+		case "neg":
+			return -args[0]
+		case "sqrt":
+			return math.Sqrt(args[0])
+		case "sin":
+			return math.Sin(args[0])
+		case "cos":
+			return math.Cos(args[0])
+		case "log":
+			return math.Log(args[0])
+		case "abs":
+			return math.Abs(args[0])
+		case "!":
+			return boolValue(args[0] == 0)
+		}
+	}
+
+	if len(args) == 2 {
+		switch task.Operator {
+		case "+":
+			return args[0] + args[1]
+		case "-":
+			return args[0] - args[1]
+		case "*":
+			return args[0] * args[1]
+		case "/":
+			if args[1] != 0 || task.Policy == PolicyIEEE {
+				return args[0] / args[1]
+			}
+		case "^":
+			return math.Pow(args[0], args[1])
+		case "log":
+			return math.Log(args[0]) / math.Log(args[1])
+		case "<":
+			return boolValue(args[0] < args[1])
+		case "<=":
+			return boolValue(args[0] <= args[1])
+		case ">":
+			return boolValue(args[0] > args[1])
+		case ">=":
+			return boolValue(args[0] >= args[1])
+		case "==":
+			return boolValue(args[0] == args[1])
+		case "!=":
+			return boolValue(args[0] != args[1])
+		case "&&":
+			return boolValue(args[0] != 0 && args[1] != 0)
+		case "||":
+			return boolValue(args[0] != 0 || args[1] != 0)
+		case "%", "//", "&", "|", "xor", "<<", ">>":
+			return calculateInteger(task.Operator, args[0], args[1])
+		}
+	}
+
+	if len(args) > 0 {
+		switch task.Operator {
+		case "min":
+			result := args[0]
+			for _, arg := range args[1:] {
+				result = math.Min(result, arg)
+			}
+			return result
+		case "max":
+			result := args[0]
+			for _, arg := range args[1:] {
+				result = math.Max(result, arg)
+			}
+			return result
 		}
 	}
 	return math.NaN()
 }
+
+func calculateInteger(operator string, x, y float64) float64 {
+	a, okA := toInt64(x)
+	b, okB := toInt64(y)
+	if !okA || !okB {
+		return math.NaN()
+	}
+	switch operator {
+	case "%":
+		if b != 0 {
+			m := a % b
+			if m != 0 && (m < 0) != (b < 0) {
+				m += b
+			}
+			return float64(m)
+		}
+	case "//":
+		if b != 0 {
+			q := a / b
+			if a%b != 0 && (a < 0) != (b < 0) {
+				q--
+			}
+			return float64(q)
+		}
+	case "&":
+		return float64(a & b)
+	case "|":
+		return float64(a | b)
+	case "xor":
+		return float64(a ^ b)
+	case "<<":
+		if b >= 0 && b < 64 && a<<b>>b == a {
+			return float64(a << b)
+		}
+	case ">>":
+		if b >= 0 {
+			return float64(a >> b)
+		}
+	}
+	return math.NaN()
+}
+
+func toInt64(value float64) (int64, bool) {
+	if value != math.Trunc(value) || value < math.MinInt64 || value >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(value), true
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func waitForOperationTime(task *Task) {
+	if !task.OperationTime.IsZero() {
+		duration := task.OperationTime.Sub(time.Now())
+		if duration > 0 {
+			time.Sleep(duration)
+		}
+	}
+}
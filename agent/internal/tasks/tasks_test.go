@@ -22,8 +22,7 @@ func TestCalculate_AllOperators(t *testing.T) {
 		{
 			name: "Addition",
 			task: tasks.Task{
-				Arg1:     tasks.Operand{Value: 2},
-				Arg2:     tasks.Operand{Value: 3},
+				Args:     []tasks.Operand{{Value: 2}, {Value: 3}},
 				Operator: "+",
 			},
 			expected: 5,
@@ -31,8 +30,7 @@ func TestCalculate_AllOperators(t *testing.T) {
 		{
 			name: "Subtraction",
 			task: tasks.Task{
-				Arg1:     tasks.Operand{Value: 5},
-				Arg2:     tasks.Operand{Value: 2},
+				Args:     []tasks.Operand{{Value: 5}, {Value: 2}},
 				Operator: "-",
 			},
 			expected: 3,
@@ -40,8 +38,7 @@ func TestCalculate_AllOperators(t *testing.T) {
 		{
 			name: "Multiplication",
 			task: tasks.Task{
-				Arg1:     tasks.Operand{Value: 3},
-				Arg2:     tasks.Operand{Value: 4},
+				Args:     []tasks.Operand{{Value: 3}, {Value: 4}},
 				Operator: "*",
 			},
 			expected: 12,
@@ -49,17 +46,215 @@ func TestCalculate_AllOperators(t *testing.T) {
 		{
 			name: "Division",
 			task: tasks.Task{
-				Arg1:     tasks.Operand{Value: 10},
-				Arg2:     tasks.Operand{Value: 2},
+				Args:     []tasks.Operand{{Value: 10}, {Value: 2}},
 				Operator: "/",
 			},
 			expected: 5,
 		},
+		{
+			name: "Exponentiation",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 2}, {Value: 10}},
+				Operator: "^",
+			},
+			expected: 1024,
+		},
+		{
+			name: "Square root",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 16}},
+				Operator: "sqrt",
+			},
+			expected: 4,
+		},
+		{
+			name: "Absolute value",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: -3}},
+				Operator: "abs",
+			},
+			expected: 3,
+		},
+		{
+			name: "Cosine",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 0}},
+				Operator: "cos",
+			},
+			expected: 1,
+		},
+		{
+			name: "Logarithm with base",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 8}, {Value: 2}},
+				Operator: "log",
+			},
+			expected: 3,
+		},
+		{
+			name: "Variadic minimum",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 4}, {Value: -1}, {Value: 7}},
+				Operator: "min",
+			},
+			expected: -1,
+		},
+		{
+			name: "Variadic maximum",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 4}, {Value: -1}, {Value: 7}},
+				Operator: "max",
+			},
+			expected: 7,
+		},
+		{
+			name: "Wrong number of arguments",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 4}, {Value: 1}},
+				Operator: "sqrt",
+			},
+			expected: math.NaN(),
+		},
+		{
+			name: "Negation",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 7}},
+				Operator: "neg",
+			},
+			expected: -7,
+		},
+		{
+			name: "Less than",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 1}, {Value: 2}},
+				Operator: "<",
+			},
+			expected: 1,
+		},
+		{
+			name: "Greater or equal is false",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 1}, {Value: 2}},
+				Operator: ">=",
+			},
+			expected: 0,
+		},
+		{
+			name: "Equality",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 2.5}, {Value: 2.5}},
+				Operator: "==",
+			},
+			expected: 1,
+		},
+		{
+			name: "Logical and",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 3}, {Value: 0}},
+				Operator: "&&",
+			},
+			expected: 0,
+		},
+		{
+			name: "Logical or",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 0}, {Value: -2}},
+				Operator: "||",
+			},
+			expected: 1,
+		},
+		{
+			name: "Logical not",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 0}},
+				Operator: "!",
+			},
+			expected: 1,
+		},
+		{
+			name: "Floored modulo",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: -7}, {Value: 3}},
+				Operator: "%",
+			},
+			expected: 2,
+		},
+		{
+			name: "Floored integer division",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: -7}, {Value: 2}},
+				Operator: "//",
+			},
+			expected: -4,
+		},
+		{
+			name: "Bitwise and",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 12}, {Value: 10}},
+				Operator: "&",
+			},
+			expected: 8,
+		},
+		{
+			name: "Bitwise or",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 12}, {Value: 10}},
+				Operator: "|",
+			},
+			expected: 14,
+		},
+		{
+			name: "Bitwise xor",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 12}, {Value: 10}},
+				Operator: "xor",
+			},
+			expected: 6,
+		},
+		{
+			name: "Shift left",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 3}, {Value: 4}},
+				Operator: "<<",
+			},
+			expected: 48,
+		},
+		{
+			name: "Arithmetic shift right",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: -16}, {Value: 2}},
+				Operator: ">>",
+			},
+			expected: -4,
+		},
+		{
+			name: "Shift overflow",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 1}, {Value: 64}},
+				Operator: "<<",
+			},
+			expected: math.NaN(),
+		},
+		{
+			name: "Modulo of non-integer",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 7.5}, {Value: 2}},
+				Operator: "%",
+			},
+			expected: math.NaN(),
+		},
+		{
+			name: "Modulo by zero",
+			task: tasks.Task{
+				Args:     []tasks.Operand{{Value: 7}, {Value: 0}},
+				Operator: "%",
+			},
+			expected: math.NaN(),
+		},
 		{
 			name: "Division by zero",
 			task: tasks.Task{
-				Arg1:     tasks.Operand{Value: 10},
-				Arg2:     tasks.Operand{Value: 0},
+				Args:     []tasks.Operand{{Value: 10}, {Value: 0}},
 				Operator: "/",
 			},
 			expected: math.NaN(),
@@ -67,17 +262,15 @@ func TestCalculate_AllOperators(t *testing.T) {
 		{
 			name: "Unknown operator",
 			task: tasks.Task{
-				Arg1:     tasks.Operand{Value: 10},
-				Arg2:     tasks.Operand{Value: 2},
-				Operator: "^",
+				Args:     []tasks.Operand{{Value: 10}, {Value: 2}},
+				Operator: "?",
 			},
 			expected: math.NaN(),
 		},
 		{
 			name: "Future operation time sleeps",
 			task: tasks.Task{
-				Arg1:          tasks.Operand{Value: 1},
-				Arg2:          tasks.Operand{Value: 2},
+				Args:          []tasks.Operand{{Value: 1}, {Value: 2}},
 				Operator:      "+",
 				OperationTime: futureTime,
 			},
@@ -86,8 +279,7 @@ func TestCalculate_AllOperators(t *testing.T) {
 		{
 			name: "Past operation time does not sleep",
 			task: tasks.Task{
-				Arg1:          tasks.Operand{Value: 1},
-				Arg2:          tasks.Operand{Value: 2},
+				Args:          []tasks.Operand{{Value: 1}, {Value: 2}},
 				Operator:      "+",
 				OperationTime: baseTime.Add(-1 * time.Minute),
 			},
@@ -113,3 +305,11 @@ func TestCalculate_AllOperators(t *testing.T) {
 		})
 	}
 }
+
+func TestOperators_Supported(t *testing.T) {
+	for _, operator := range tasks.Operators {
+		unary := tasks.Calculate(&tasks.Task{Operator: operator, Args: []tasks.Operand{{Value: 4}}})
+		binary := tasks.Calculate(&tasks.Task{Operator: operator, Args: []tasks.Operand{{Value: 6}, {Value: 3}}})
+		assert.False(t, math.IsNaN(unary) && math.IsNaN(binary), operator)
+	}
+}
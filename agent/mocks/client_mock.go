@@ -13,7 +13,9 @@ import (
 	context "context"
 	reflect "reflect"
 
+	client "github.com/alexGoLyceum/calculator-service/agent/internal/client"
 	tasks "github.com/alexGoLyceum/calculator-service/agent/internal/tasks"
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -55,8 +57,37 @@ func (mr *MockClientMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockClient)(nil).Close))
 }
 
+// Heartbeat mocks base method.
+func (m *MockClient) Heartbeat(ctx context.Context, taskIDs []uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", ctx, taskIDs)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockClientMockRecorder) Heartbeat(ctx, taskIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockClient)(nil).Heartbeat), ctx, taskIDs)
+}
+
+// RegisterAgent mocks base method.
+func (m *MockClient) RegisterAgent(ctx context.Context, registration client.Registration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterAgent", ctx, registration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterAgent indicates an expected call of RegisterAgent.
+func (mr *MockClientMockRecorder) RegisterAgent(ctx, registration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAgent", reflect.TypeOf((*MockClient)(nil).RegisterAgent), ctx, registration)
+}
+
 // SetTaskResult mocks base method.
-func (m *MockClient) SetTaskResult(ctx context.Context, task tasks.Task, result float64) error {
+func (m *MockClient) SetTaskResult(ctx context.Context, task tasks.Task, result tasks.Result) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetTaskResult", ctx, task, result)
 	ret0, _ := ret[0].(error)
@@ -70,15 +101,15 @@ func (mr *MockClientMockRecorder) SetTaskResult(ctx, task, result any) *gomock.C
 }
 
 // StreamTasks mocks base method.
-func (m *MockClient) StreamTasks(ctx context.Context, handler func(*tasks.Task) error) error {
+func (m *MockClient) StreamTasks(ctx context.Context, credits <-chan int32, handler func(*tasks.Task) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StreamTasks", ctx, handler)
+	ret := m.ctrl.Call(m, "StreamTasks", ctx, credits, handler)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StreamTasks indicates an expected call of StreamTasks.
-func (mr *MockClientMockRecorder) StreamTasks(ctx, handler any) *gomock.Call {
+func (mr *MockClientMockRecorder) StreamTasks(ctx, credits, handler any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamTasks", reflect.TypeOf((*MockClient)(nil).StreamTasks), ctx, handler)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamTasks", reflect.TypeOf((*MockClient)(nil).StreamTasks), ctx, credits, handler)
 }
@@ -60,12 +60,16 @@ func NewApplication() *Impl {
 	JWTManager := auth.NewJWTManager(cfg.JwtSecret, cfg.JwtTTL)
 
 	userService := services.NewUserService(repo, JWTManager)
-	expressionTaskService := services.NewExpressionTaskService(repo, cfg.OperationTimesMs)
-	expressionTaskService.StartExpiredTaskReset(context.Background(), cfg.ResetInterval, cfg.ExpirationDelay)
-
-	handler := handlers.NewHandler(userService, expressionTaskService)
+	expressionTaskService := services.NewExpressionTaskService(repo, cfg.OperationTimesMs, cfg.FoldOperators, cfg.LeaseDuration)
+	variableService := services.NewVariableService(repo)
+	functionService := services.NewUserFunctionService(repo)
+	agentService := services.NewAgentService(repo, cfg.LeaseDuration)
+	expressionTaskService.StartExpiredTaskReset(context.Background(), cfg.ResetInterval)
+	expressionTaskService.StartTaskDispatch(context.Background())
+
+	handler := handlers.NewHandler(userService, expressionTaskService, variableService, functionService, agentService)
 	httpServer := http.NewServer(cfg, logger, handler, JWTManager)
-	grpcServer := grpc.NewServer(expressionTaskService, cfg.Orchestrator.GRPCHost, cfg.Orchestrator.GRPCPort)
+	grpcServer := grpc.NewServer(expressionTaskService, agentService, cfg.Orchestrator.GRPCHost, cfg.Orchestrator.GRPCPort)
 
 	return &Impl{
 		Config:     cfg,
@@ -73,8 +77,6 @@ func NewApplication() *Impl {
 		HTTPServer: httpServer,
 		GRPCServer: grpcServer,
 	}
-
-	return nil
 }
 
 func (app *Impl) Start() {
@@ -15,6 +15,7 @@ import (
 	pb "github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/grpc/proto"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/grpc/server"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/http/handlers"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/http/middlewares"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/http/routes"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/mocks"
 
@@ -135,11 +136,12 @@ func (s *IntegrationTestSuite) SetupSuite() {
 		Subtraction:    cfg.Subtraction,
 		Multiplication: cfg.Multiplication,
 		Division:       cfg.Division,
-	})
+	}, services.FoldableOperators, 30*time.Second)
 
 	grpcServer = grpc.NewServer()
 	grpcListener := bufconn.Listen(1024 * 1024)
-	orchestratorServer := server.NewServer(exprService, "localhost", 50051)
+	agentService := services.NewAgentService(repo, 30*time.Second)
+	orchestratorServer := server.NewServer(exprService, agentService, "localhost", 50051)
 	pb.RegisterOrchestratorServiceServer(grpcServer, orchestratorServer)
 
 	go func() {
@@ -154,8 +156,8 @@ func (s *IntegrationTestSuite) SetupSuite() {
 	httpServer.Use(middleware.Logger())
 	httpServer.Use(middleware.Recover())
 
-	handler := handlers.NewHandler(userService, exprService)
-	routes.RegisterRoutes(httpServer, handler)
+	handler := handlers.NewHandler(userService, exprService, services.NewVariableService(repo), services.NewUserFunctionService(repo), agentService)
+	routes.RegisterRoutes(httpServer, handler, middlewares.AdminMiddleware(""))
 
 	go func() {
 		if err := httpServer.Start(":8080"); err != nil && err != http.ErrServerClosed {
@@ -198,20 +200,68 @@ func applyMigrations() error {
 			user_id UUID REFERENCES users(id) ON DELETE CASCADE,
 			expression TEXT NOT NULL,
 			status TEXT NOT NULL,
-			result FLOAT
+			result FLOAT,
+			precision TEXT NOT NULL DEFAULT 'float',
+			scale INTEGER NOT NULL DEFAULT 0,
+			decimal_result TEXT,
+			variables JSONB,
+			error_message TEXT,
+			numeric_policy TEXT NOT NULL DEFAULT 'strict'
 		);
 
 		CREATE TABLE IF NOT EXISTS tasks (
 			id UUID PRIMARY KEY,
 			expression_id UUID REFERENCES expressions(id) ON DELETE CASCADE,
-			arg1_value FLOAT,
-			arg1_task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
-			arg2_value FLOAT,
-			arg2_task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
 			operator TEXT NOT NULL,
 			operation_time TIMESTAMP,
 			final_task BOOLEAN NOT NULL,
-			status TEXT NOT NULL
+			status TEXT NOT NULL DEFAULT 'pending',
+			branch_of UUID REFERENCES tasks(id) ON DELETE CASCADE,
+			then_branch BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			position INTEGER NOT NULL DEFAULT 0,
+			agent TEXT,
+			started_at TIMESTAMP,
+			finished_at TIMESTAMP,
+			result FLOAT,
+			decimal_result TEXT,
+			error_message TEXT
+		);
+
+		CREATE TABLE IF NOT EXISTS task_arguments (
+			task_id UUID REFERENCES tasks(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			value FLOAT,
+			decimal_value TEXT,
+			source_task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+			origin_task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+			PRIMARY KEY (task_id, position)
+		);
+
+		CREATE TABLE IF NOT EXISTS expression_values (
+			expression_id UUID REFERENCES expressions(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			name VARCHAR(64) NOT NULL,
+			value FLOAT,
+			decimal_value TEXT,
+			source_task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+			PRIMARY KEY (expression_id, position)
+		);
+
+		CREATE TABLE IF NOT EXISTS variables (
+			user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(64) NOT NULL,
+			value FLOAT NOT NULL,
+			decimal_value TEXT NOT NULL,
+			PRIMARY KEY (user_id, name)
+		);
+
+		CREATE TABLE IF NOT EXISTS user_functions (
+			user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(64) NOT NULL,
+			params TEXT[] NOT NULL,
+			body TEXT NOT NULL,
+			PRIMARY KEY (user_id, name)
 		);
 	`)
 	return err
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/database/postgres"
@@ -12,16 +13,35 @@ import (
 	"github.com/spf13/viper"
 )
 
+// DefaultOperationTime is used for operators that were added after the
+// original four, so existing deployments keep working without new variables.
+const DefaultOperationTime = time.Second
+
+var optionalOperationTimes = []string{
+	"TIME_EXPONENTIATIONS_MS",
+	"TIME_FUNCTIONS_MS",
+	"TIME_COMPARISONS_MS",
+	"TIME_MODULO_MS",
+	"TIME_INTEGER_DIVISIONS_MS",
+	"TIME_BITWISE_AND_MS",
+	"TIME_BITWISE_OR_MS",
+	"TIME_BITWISE_XOR_MS",
+	"TIME_SHIFT_LEFT_MS",
+	"TIME_SHIFT_RIGHT_MS",
+}
+
 type Config struct {
 	Orchestrator     OrchestratorConfig
 	Database         *postgres.Config
 	Log              *logging.LoggerConfig
 	OperationTimesMs *services.OperationTimesMS
+	FoldOperators    []string
 	MigrationDir     string
 	JwtSecret        []byte
 	JwtTTL           time.Duration
+	AdminToken       string
 	ResetInterval    time.Duration
-	ExpirationDelay  time.Duration
+	LeaseDuration    time.Duration
 }
 
 type OrchestratorConfig struct {
@@ -33,6 +53,9 @@ type OrchestratorConfig struct {
 
 func LoadConfig() (*Config, error) {
 	viper.AutomaticEnv()
+	for _, key := range optionalOperationTimes {
+		viper.SetDefault(key, DefaultOperationTime)
+	}
 
 	orchestrator := OrchestratorConfig{
 		HTTPHost: viper.GetString("ORCHESTRATOR_HTTP_HOST"),
@@ -46,16 +69,31 @@ func LoadConfig() (*Config, error) {
 	}
 
 	operationTimesMS := services.OperationTimesMS{
-		Addition:       viper.GetDuration("TIME_ADDITION_MS"),
-		Subtraction:    viper.GetDuration("TIME_SUBTRACTION_MS"),
-		Multiplication: viper.GetDuration("TIME_MULTIPLICATIONS_MS"),
-		Division:       viper.GetDuration("TIME_DIVISIONS_MS"),
+		Addition:        viper.GetDuration("TIME_ADDITION_MS"),
+		Subtraction:     viper.GetDuration("TIME_SUBTRACTION_MS"),
+		Multiplication:  viper.GetDuration("TIME_MULTIPLICATIONS_MS"),
+		Division:        viper.GetDuration("TIME_DIVISIONS_MS"),
+		Exponentiation:  viper.GetDuration("TIME_EXPONENTIATIONS_MS"),
+		Function:        viper.GetDuration("TIME_FUNCTIONS_MS"),
+		Comparison:      viper.GetDuration("TIME_COMPARISONS_MS"),
+		Modulo:          viper.GetDuration("TIME_MODULO_MS"),
+		IntegerDivision: viper.GetDuration("TIME_INTEGER_DIVISIONS_MS"),
+		BitwiseAnd:      viper.GetDuration("TIME_BITWISE_AND_MS"),
+		BitwiseOr:       viper.GetDuration("TIME_BITWISE_OR_MS"),
+		BitwiseXor:      viper.GetDuration("TIME_BITWISE_XOR_MS"),
+		ShiftLeft:       viper.GetDuration("TIME_SHIFT_LEFT_MS"),
+		ShiftRight:      viper.GetDuration("TIME_SHIFT_RIGHT_MS"),
 	}
 
 	if err := validateOperationTimes(operationTimesMS); err != nil {
 		return nil, err
 	}
 
+	foldOperators, err := parseFoldOperators(viper.GetString("FOLD_OPERATORS"))
+	if err != nil {
+		return nil, err
+	}
+
 	logger := &logging.LoggerConfig{
 		Level:             viper.GetString("LOG_LEVEL"),
 		FilePath:          viper.GetString("LOG_PATH"),
@@ -99,9 +137,9 @@ func LoadConfig() (*Config, error) {
 		return nil, errors.New("RESET_INTERVAL must be greater than 0")
 	}
 
-	expirationDelay := viper.GetDuration("EXPIRATION_DELAY")
-	if expirationDelay <= 0 {
-		return nil, errors.New("EXPIRATION_DELAY must be greater than 0")
+	leaseDuration := viper.GetDuration("LEASE_DURATION")
+	if leaseDuration <= 0 {
+		return nil, errors.New("LEASE_DURATION must be greater than 0")
 	}
 
 	return &Config{
@@ -109,11 +147,13 @@ func LoadConfig() (*Config, error) {
 		Database:         database,
 		Log:              logger,
 		OperationTimesMs: &operationTimesMS,
+		FoldOperators:    foldOperators,
 		MigrationDir:     migrationDir,
 		JwtSecret:        jwtSecret,
 		JwtTTL:           jwtTTL,
+		AdminToken:       viper.GetString("ADMIN_TOKEN"),
 		ResetInterval:    resetInterval,
-		ExpirationDelay:  expirationDelay,
+		LeaseDuration:    leaseDuration,
 	}, nil
 }
 
@@ -143,9 +183,57 @@ func validateOperationTimes(times services.OperationTimesMS) error {
 	if times.Division <= 0 {
 		return errors.New("TIME_DIVISIONS_MS must be greater than 0")
 	}
+	if times.Exponentiation <= 0 {
+		return errors.New("TIME_EXPONENTIATIONS_MS must be greater than 0")
+	}
+	if times.Function <= 0 {
+		return errors.New("TIME_FUNCTIONS_MS must be greater than 0")
+	}
+	if times.Comparison <= 0 {
+		return errors.New("TIME_COMPARISONS_MS must be greater than 0")
+	}
+	if times.Modulo <= 0 {
+		return errors.New("TIME_MODULO_MS must be greater than 0")
+	}
+	if times.IntegerDivision <= 0 {
+		return errors.New("TIME_INTEGER_DIVISIONS_MS must be greater than 0")
+	}
+	if times.BitwiseAnd <= 0 {
+		return errors.New("TIME_BITWISE_AND_MS must be greater than 0")
+	}
+	if times.BitwiseOr <= 0 {
+		return errors.New("TIME_BITWISE_OR_MS must be greater than 0")
+	}
+	if times.BitwiseXor <= 0 {
+		return errors.New("TIME_BITWISE_XOR_MS must be greater than 0")
+	}
+	if times.ShiftLeft <= 0 {
+		return errors.New("TIME_SHIFT_LEFT_MS must be greater than 0")
+	}
+	if times.ShiftRight <= 0 {
+		return errors.New("TIME_SHIFT_RIGHT_MS must be greater than 0")
+	}
 	return nil
 }
 
+func parseFoldOperators(value string) ([]string, error) {
+	switch strings.TrimSpace(value) {
+	case "":
+		return services.FoldableOperators, nil
+	case "none":
+		return []string{}, nil
+	}
+	var operators []string
+	for _, operator := range strings.Split(value, ",") {
+		operator = strings.TrimSpace(operator)
+		if !services.IsFoldableOperator(operator) {
+			return nil, fmt.Errorf("FOLD_OPERATORS contains unsupported operator %q", operator)
+		}
+		operators = append(operators, operator)
+	}
+	return operators, nil
+}
+
 func validateDatabase(cfg postgres.Config) error {
 	if cfg.Host == "" || cfg.Port == "" || cfg.Username == "" || cfg.Password == "" || cfg.Database == "" {
 		return errors.New("all POSTGRES_* fields must be set and non-empty")
@@ -3,8 +3,10 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/config"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/services"
 
 	"github.com/stretchr/testify/require"
 )
@@ -31,6 +33,16 @@ func setValidEnv(t *testing.T) {
 	setEnv(t, "TIME_SUBTRACTION_MS", "10ms")
 	setEnv(t, "TIME_MULTIPLICATIONS_MS", "10ms")
 	setEnv(t, "TIME_DIVISIONS_MS", "10ms")
+	setEnv(t, "TIME_EXPONENTIATIONS_MS", "10ms")
+	setEnv(t, "TIME_FUNCTIONS_MS", "10ms")
+	setEnv(t, "TIME_COMPARISONS_MS", "10ms")
+	setEnv(t, "TIME_MODULO_MS", "10ms")
+	setEnv(t, "TIME_INTEGER_DIVISIONS_MS", "10ms")
+	setEnv(t, "TIME_BITWISE_AND_MS", "10ms")
+	setEnv(t, "TIME_BITWISE_OR_MS", "10ms")
+	setEnv(t, "TIME_BITWISE_XOR_MS", "10ms")
+	setEnv(t, "TIME_SHIFT_LEFT_MS", "10ms")
+	setEnv(t, "TIME_SHIFT_RIGHT_MS", "10ms")
 
 	setEnv(t, "LOG_LEVEL", "info")
 	setEnv(t, "LOG_PATH", "/tmp/log")
@@ -45,7 +57,7 @@ func setValidEnv(t *testing.T) {
 	setEnv(t, "JWT_SECRET", "supersecret")
 	setEnv(t, "JWT_TTL", "15m")
 	setEnv(t, "RESET_INTERVAL", "5m")
-	setEnv(t, "EXPIRATION_DELAY", "10m")
+	setEnv(t, "LEASE_DURATION", "10m")
 }
 
 func TestLoadConfig_Success(t *testing.T) {
@@ -94,6 +106,27 @@ func TestLoadConfig_ZeroOperationTime(t *testing.T) {
 	require.ErrorContains(t, err, "TIME_ADDITION_MS must be greater than 0")
 }
 
+func TestLoadConfig_DefaultOperationTimes(t *testing.T) {
+	setValidEnv(t)
+	_ = os.Unsetenv("TIME_MODULO_MS")
+	_ = os.Unsetenv("TIME_SHIFT_RIGHT_MS")
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	require.Equal(t, config.DefaultOperationTime, cfg.OperationTimesMs.Modulo)
+	require.Equal(t, config.DefaultOperationTime, cfg.OperationTimesMs.ShiftRight)
+	require.Equal(t, 10*time.Millisecond, cfg.OperationTimesMs.Addition)
+}
+
+func TestLoadConfig_ZeroOptionalOperationTime(t *testing.T) {
+	setValidEnv(t)
+	setEnv(t, "TIME_MODULO_MS", "0s")
+
+	_, err := config.LoadConfig()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "TIME_MODULO_MS must be greater than 0")
+}
+
 func TestLoadConfig_MissingLogLevel(t *testing.T) {
 	setValidEnv(t)
 	_ = os.Unsetenv("LOG_LEVEL")
@@ -148,13 +181,13 @@ func TestLoadConfig_InvalidResetInterval(t *testing.T) {
 	require.ErrorContains(t, err, "RESET_INTERVAL must be greater than 0")
 }
 
-func TestLoadConfig_InvalidExpirationDelay(t *testing.T) {
+func TestLoadConfig_InvalidLeaseDuration(t *testing.T) {
 	setValidEnv(t)
-	setEnv(t, "EXPIRATION_DELAY", "0s")
+	setEnv(t, "LEASE_DURATION", "0s")
 
 	_, err := config.LoadConfig()
 	require.Error(t, err)
-	require.ErrorContains(t, err, "EXPIRATION_DELAY must be greater than 0")
+	require.ErrorContains(t, err, "LEASE_DURATION must be greater than 0")
 }
 
 func TestLoadConfig_ZeroSubtractionTime(t *testing.T) {
@@ -183,3 +216,72 @@ func TestLoadConfig_ZeroDivisionTime(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorContains(t, err, "TIME_DIVISIONS_MS must be greater than 0")
 }
+
+func TestLoadConfig_ZeroExponentiationTime(t *testing.T) {
+	setValidEnv(t)
+	setEnv(t, "TIME_EXPONENTIATIONS_MS", "0s")
+
+	_, err := config.LoadConfig()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "TIME_EXPONENTIATIONS_MS must be greater than 0")
+}
+
+func TestLoadConfig_ZeroFunctionTime(t *testing.T) {
+	setValidEnv(t)
+	setEnv(t, "TIME_FUNCTIONS_MS", "0s")
+
+	_, err := config.LoadConfig()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "TIME_FUNCTIONS_MS must be greater than 0")
+}
+
+func TestLoadConfig_ZeroComparisonTime(t *testing.T) {
+	setValidEnv(t)
+	setEnv(t, "TIME_COMPARISONS_MS", "0s")
+
+	_, err := config.LoadConfig()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "TIME_COMPARISONS_MS must be greater than 0")
+}
+
+func TestLoadConfig_ZeroIntegerOperationTimes(t *testing.T) {
+	for _, name := range []string{
+		"TIME_MODULO_MS",
+		"TIME_INTEGER_DIVISIONS_MS",
+		"TIME_BITWISE_AND_MS",
+		"TIME_BITWISE_OR_MS",
+		"TIME_BITWISE_XOR_MS",
+		"TIME_SHIFT_LEFT_MS",
+		"TIME_SHIFT_RIGHT_MS",
+	} {
+		t.Run(name, func(t *testing.T) {
+			setValidEnv(t)
+			setEnv(t, name, "0s")
+
+			_, err := config.LoadConfig()
+			require.ErrorContains(t, err, name+" must be greater than 0")
+		})
+	}
+}
+
+func TestLoadConfig_FoldOperators(t *testing.T) {
+	setValidEnv(t)
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	require.Equal(t, services.FoldableOperators, cfg.FoldOperators)
+
+	setEnv(t, "FOLD_OPERATORS", "+, *")
+	cfg, err = config.LoadConfig()
+	require.NoError(t, err)
+	require.Equal(t, []string{"+", "*"}, cfg.FoldOperators)
+
+	setEnv(t, "FOLD_OPERATORS", "none")
+	cfg, err = config.LoadConfig()
+	require.NoError(t, err)
+	require.Empty(t, cfg.FoldOperators)
+
+	setEnv(t, "FOLD_OPERATORS", "+,@")
+	_, err = config.LoadConfig()
+	require.ErrorContains(t, err, "FOLD_OPERATORS contains unsupported operator")
+}
@@ -0,0 +1,99 @@
+package expr
+
+type Node interface {
+	Pos() int
+	End() int
+}
+
+type NumberLit struct {
+	Value    float64
+	Raw      string
+	ValuePos int
+}
+
+type Ident struct {
+	Name    string
+	NamePos int
+}
+
+type UnaryExpr struct {
+	Op    string
+	OpPos int
+	X     Node
+}
+
+type BinaryExpr struct {
+	Op    string
+	OpPos int
+	X     Node
+	Y     Node
+}
+
+type CallExpr struct {
+	Func    string
+	NamePos int
+	Args    []Node
+	Rparen  int
+}
+
+type CondExpr struct {
+	Cond     Node
+	Question int
+	X        Node
+	Colon    int
+	Y        Node
+}
+
+type ParenExpr struct {
+	Lparen int
+	X      Node
+	Rparen int
+}
+
+func (n *NumberLit) Pos() int  { return n.ValuePos }
+func (n *NumberLit) End() int  { return n.ValuePos + len(n.Raw) }
+func (n *Ident) Pos() int      { return n.NamePos }
+func (n *Ident) End() int      { return n.NamePos + len(n.Name) }
+func (n *UnaryExpr) Pos() int  { return n.OpPos }
+func (n *UnaryExpr) End() int  { return n.X.End() }
+func (n *BinaryExpr) Pos() int { return n.X.Pos() }
+func (n *BinaryExpr) End() int { return n.Y.End() }
+func (n *CallExpr) Pos() int   { return n.NamePos }
+func (n *CallExpr) End() int   { return n.Rparen + 1 }
+func (n *CondExpr) Pos() int   { return n.Cond.Pos() }
+func (n *CondExpr) End() int   { return n.Y.End() }
+func (n *ParenExpr) Pos() int  { return n.Lparen }
+func (n *ParenExpr) End() int  { return n.Rparen + 1 }
+
+func Unparen(node Node) Node {
+	for {
+		paren, ok := node.(*ParenExpr)
+		if !ok {
+			return node
+		}
+		node = paren.X
+	}
+}
+
+func Walk(node Node, fn func(Node) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+	switch n := node.(type) {
+	case *UnaryExpr:
+		Walk(n.X, fn)
+	case *BinaryExpr:
+		Walk(n.X, fn)
+		Walk(n.Y, fn)
+	case *CallExpr:
+		for _, arg := range n.Args {
+			Walk(arg, fn)
+		}
+	case *CondExpr:
+		Walk(n.Cond, fn)
+		Walk(n.X, fn)
+		Walk(n.Y, fn)
+	case *ParenExpr:
+		Walk(n.X, fn)
+	}
+}
@@ -0,0 +1,51 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrEmptyExpression           = errors.New("empty input")
+	ErrInvalidCharacter          = errors.New("invalid character")
+	ErrNumberFormatIssue         = errors.New("incorrect number format")
+	ErrParenthesisIssue          = errors.New("mismatched or improperly placed parentheses")
+	ErrOperatorIssue             = errors.New("consecutive or misplaced operators")
+	ErrInvalidExpressionStartEnd = errors.New("expression cannot start or end with an operator")
+	ErrFunctionCallIssue         = errors.New("function name must be followed by its arguments in parentheses")
+	ErrSeparatorIssue            = errors.New("misplaced argument separator")
+	ErrUnexpectedToken           = errors.New("unexpected token")
+	ErrConditionalIssue          = errors.New("conditional operator '?' must be followed by ':' and an alternative")
+	ErrStatementIssue            = errors.New("statement must be an expression or an assignment \"name = expression\"")
+	ErrAssignmentIssue           = errors.New("invalid assignment target")
+	ErrNestingTooDeep            = errors.New("expression is nested too deeply")
+)
+
+type Error struct {
+	Err      error
+	Pos      int
+	End      int
+	Token    string
+	Expected []string
+}
+
+func NewError(err error, pos, end int, token string, expected ...string) *Error {
+	return &Error{
+		Err:      err,
+		Pos:      pos,
+		End:      end,
+		Token:    token,
+		Expected: expected,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%s at position %d", e.Err.Error(), e.Pos)
+	}
+	return fmt.Sprintf("%s at position %d: %q", e.Err.Error(), e.Pos, e.Token)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
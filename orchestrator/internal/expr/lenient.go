@@ -0,0 +1,67 @@
+package expr
+
+import "slices"
+
+func Normalize(tokens []Token) []Token {
+	out := make([]Token, 0, len(tokens))
+	for i, tok := range tokens {
+		if len(out) > 0 && impliesMultiplication(out[len(out)-1], tok) {
+			out = append(out, Token{Kind: Operator, Text: "*", Pos: tok.Pos})
+		}
+		if tok.IsOperator("%") && i+1 < len(tokens) && !startsOperand(tokens[i+1]) {
+			if start, ok := percentOperand(out); ok {
+				out = slices.Insert(out, start, Token{Kind: LeftParen, Text: "(", Pos: out[start].Pos})
+				out = append(out,
+					Token{Kind: Operator, Text: "/", Pos: tok.Pos},
+					Token{Kind: Number, Text: "100", Pos: tok.Pos},
+					Token{Kind: RightParen, Text: ")", Pos: tok.Pos},
+				)
+				continue
+			}
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+func impliesMultiplication(prev, tok Token) bool {
+	switch prev.Kind {
+	case Number:
+		return tok.Kind == Identifier || tok.Kind == LeftParen
+	case RightParen:
+		return startsOperand(tok)
+	}
+	return false
+}
+
+func startsOperand(tok Token) bool {
+	return tok.Kind == Number || tok.Kind == Identifier || tok.Kind == LeftParen
+}
+
+func percentOperand(tokens []Token) (int, bool) {
+	last := len(tokens) - 1
+	if last < 0 {
+		return 0, false
+	}
+	switch tokens[last].Kind {
+	case Number, Identifier:
+		return last, true
+	case RightParen:
+		depth := 0
+		for i := last; i >= 0; i-- {
+			switch tokens[i].Kind {
+			case RightParen:
+				depth++
+			case LeftParen:
+				depth--
+			}
+			if depth == 0 {
+				if i > 0 && tokens[i-1].Kind == Identifier {
+					return i - 1, true
+				}
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
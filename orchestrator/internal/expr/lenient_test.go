@@ -0,0 +1,59 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"number before parenthesis", "2(3+4)", "2*(3+4)"},
+		{"adjacent parentheses", "(1+2)(3+4)", "(1+2)*(3+4)"},
+		{"number before identifier", "2x + 3 sqrt(4)", "2*x+3*sqrt(4)"},
+		{"parenthesis before number", "(1+2)3", "(1+2)*3"},
+		{"function call is kept", "max(1,2)", "max(1,2)"},
+		{"percent literal", "15%", "(15/100)"},
+		{"percent of variable", "price * rate%", "price*(rate/100)"},
+		{"percent of group", "1 + (2+3)% - 1", "1+((2+3)/100)-1"},
+		{"percent of call", "max(10,20)%", "(max(10,20)/100)"},
+		{"percent inside arguments", "min(50%, 1)", "min((50/100),1)"},
+		{"modulo is kept", "17 % 5", "17%5"},
+		{"percent before parenthesis is modulo", "17%(5)", "17%(5)"},
+		{"percent before binary operator", "10%-3", "(10/100)-3"},
+		{"percent before sign is not modulo", "10 % -3", "(10/100)-3"},
+		{"modulo of negative operand needs parenthesis", "10 % (-3)", "10%(-3)"},
+		{"percent then implicit multiplication", "(50%)(4)", "((50/100))*(4)"},
+		{"unchanged expression", "1+2*3", "1+2*3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := expr.Lex(tt.input)
+			assert.NoError(t, err)
+			normalized := expr.Normalize(tokens)
+			assert.Equal(t, tt.expected, expr.Format(normalized))
+
+			_, err = expr.ParseTokens(normalized)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNormalize_KeepsSourcePositions(t *testing.T) {
+	tokens, err := expr.Lex("2(1+)")
+	assert.NoError(t, err)
+	_, err = expr.ParseTokens(expr.Normalize(tokens))
+
+	var exprErr *expr.Error
+	if assert.ErrorAs(t, err, &exprErr) {
+		assert.ErrorIs(t, err, expr.ErrOperatorIssue)
+		assert.Equal(t, 3, exprErr.Pos)
+	}
+}
@@ -0,0 +1,98 @@
+package expr
+
+import (
+	"slices"
+	"strings"
+)
+
+var operators = []string{
+	"**", "//", "<<", ">>", "<=", ">=", "==", "!=", "&&", "||",
+	"+", "-", "*", "/", "%", "^", "&", "|", "<", ">", "!", "?", ":",
+}
+
+var wordOperators = []string{"xor"}
+
+func Lex(input string) ([]Token, error) {
+	return lex(input, false)
+}
+
+func LexScript(input string) ([]Token, error) {
+	return lex(input, true)
+}
+
+func lex(input string, script bool) ([]Token, error) {
+	var tokens []Token
+	for i := 0; i < len(input); {
+		ch := input[i]
+		switch {
+		case isSpace(ch):
+			i++
+		case isDigit(ch) || ch == '.':
+			start := i
+			i = scanNumber(input, start)
+			text := input[start:i]
+			if _, err := ParseNumber(text); err != nil {
+				return nil, NewError(ErrNumberFormatIssue, start, i, text)
+			}
+			tokens = append(tokens, Token{Kind: Number, Text: text, Pos: start})
+		case isLetter(ch):
+			start := i
+			for i < len(input) && (isLetter(input[i]) || isDigit(input[i])) {
+				i++
+			}
+			kind := Identifier
+			if slices.Contains(wordOperators, input[start:i]) {
+				kind = Operator
+			}
+			tokens = append(tokens, Token{Kind: kind, Text: input[start:i], Pos: start})
+		case matchOperator(input[i:]) != "":
+			op := matchOperator(input[i:])
+			tokens = append(tokens, Token{Kind: Operator, Text: op, Pos: i})
+			i += len(op)
+		case ch == '(':
+			tokens = append(tokens, Token{Kind: LeftParen, Text: "(", Pos: i})
+			i++
+		case ch == ')':
+			tokens = append(tokens, Token{Kind: RightParen, Text: ")", Pos: i})
+			i++
+		case ch == ',':
+			tokens = append(tokens, Token{Kind: Comma, Text: ",", Pos: i})
+			i++
+		case script && ch == '=':
+			tokens = append(tokens, Token{Kind: Assign, Text: "=", Pos: i})
+			i++
+		case script && ch == ';':
+			tokens = append(tokens, Token{Kind: Semicolon, Text: ";", Pos: i})
+			i++
+		default:
+			end := i + 1
+			for end < len(input) && input[end]&0xC0 == 0x80 {
+				end++
+			}
+			return nil, NewError(ErrInvalidCharacter, i, end, input[i:end])
+		}
+	}
+	tokens = append(tokens, Token{Kind: EOF, Pos: len(input)})
+	return tokens, nil
+}
+
+func matchOperator(input string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(input, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isLetter(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch == '_'
+}
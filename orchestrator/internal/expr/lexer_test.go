@@ -0,0 +1,195 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLex(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []expr.Token
+	}{
+		{
+			name:  "binary expression with spaces",
+			input: "12.5 + x",
+			expected: []expr.Token{
+				{Kind: expr.Number, Text: "12.5", Pos: 0},
+				{Kind: expr.Operator, Text: "+", Pos: 5},
+				{Kind: expr.Identifier, Text: "x", Pos: 7},
+				{Kind: expr.EOF, Pos: 8},
+			},
+		},
+		{
+			name:  "function call",
+			input: "max(1,2)",
+			expected: []expr.Token{
+				{Kind: expr.Identifier, Text: "max", Pos: 0},
+				{Kind: expr.LeftParen, Text: "(", Pos: 3},
+				{Kind: expr.Number, Text: "1", Pos: 4},
+				{Kind: expr.Comma, Text: ",", Pos: 5},
+				{Kind: expr.Number, Text: "2", Pos: 6},
+				{Kind: expr.RightParen, Text: ")", Pos: 7},
+				{Kind: expr.EOF, Pos: 8},
+			},
+		},
+		{
+			name:  "double star power",
+			input: "2**3",
+			expected: []expr.Token{
+				{Kind: expr.Number, Text: "2", Pos: 0},
+				{Kind: expr.Operator, Text: "**", Pos: 1},
+				{Kind: expr.Number, Text: "3", Pos: 3},
+				{Kind: expr.EOF, Pos: 4},
+			},
+		},
+		{
+			name:  "exponent and non-decimal literals",
+			input: "1e-3+0x1F*0b1_010-0o17",
+			expected: []expr.Token{
+				{Kind: expr.Number, Text: "1e-3", Pos: 0},
+				{Kind: expr.Operator, Text: "+", Pos: 4},
+				{Kind: expr.Number, Text: "0x1F", Pos: 5},
+				{Kind: expr.Operator, Text: "*", Pos: 9},
+				{Kind: expr.Number, Text: "0b1_010", Pos: 10},
+				{Kind: expr.Operator, Text: "-", Pos: 17},
+				{Kind: expr.Number, Text: "0o17", Pos: 18},
+				{Kind: expr.EOF, Pos: 22},
+			},
+		},
+		{
+			name:  "exponent marker without digits",
+			input: "2e+x",
+			expected: []expr.Token{
+				{Kind: expr.Number, Text: "2", Pos: 0},
+				{Kind: expr.Identifier, Text: "e", Pos: 1},
+				{Kind: expr.Operator, Text: "+", Pos: 2},
+				{Kind: expr.Identifier, Text: "x", Pos: 3},
+				{Kind: expr.EOF, Pos: 4},
+			},
+		},
+		{
+			name:  "comparison and logical operators",
+			input: "a<=1&&!b!=c",
+			expected: []expr.Token{
+				{Kind: expr.Identifier, Text: "a", Pos: 0},
+				{Kind: expr.Operator, Text: "<=", Pos: 1},
+				{Kind: expr.Number, Text: "1", Pos: 3},
+				{Kind: expr.Operator, Text: "&&", Pos: 4},
+				{Kind: expr.Operator, Text: "!", Pos: 6},
+				{Kind: expr.Identifier, Text: "b", Pos: 7},
+				{Kind: expr.Operator, Text: "!=", Pos: 8},
+				{Kind: expr.Identifier, Text: "c", Pos: 10},
+				{Kind: expr.EOF, Pos: 11},
+			},
+		},
+		{
+			name:  "ternary",
+			input: "x?1:2",
+			expected: []expr.Token{
+				{Kind: expr.Identifier, Text: "x", Pos: 0},
+				{Kind: expr.Operator, Text: "?", Pos: 1},
+				{Kind: expr.Number, Text: "1", Pos: 2},
+				{Kind: expr.Operator, Text: ":", Pos: 3},
+				{Kind: expr.Number, Text: "2", Pos: 4},
+				{Kind: expr.EOF, Pos: 5},
+			},
+		},
+		{
+			name:  "integer operators",
+			input: "a//2%b<<1 xor c",
+			expected: []expr.Token{
+				{Kind: expr.Identifier, Text: "a", Pos: 0},
+				{Kind: expr.Operator, Text: "//", Pos: 1},
+				{Kind: expr.Number, Text: "2", Pos: 3},
+				{Kind: expr.Operator, Text: "%", Pos: 4},
+				{Kind: expr.Identifier, Text: "b", Pos: 5},
+				{Kind: expr.Operator, Text: "<<", Pos: 6},
+				{Kind: expr.Number, Text: "1", Pos: 8},
+				{Kind: expr.Operator, Text: "xor", Pos: 10},
+				{Kind: expr.Identifier, Text: "c", Pos: 14},
+				{Kind: expr.EOF, Pos: 15},
+			},
+		},
+		{
+			name:     "empty input",
+			input:    "  ",
+			expected: []expr.Token{{Kind: expr.EOF, Pos: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := expr.Lex(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, tokens)
+		})
+	}
+}
+
+func TestLex_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr error
+		pos, end    int
+		token       string
+	}{
+		{"invalid character", "2+$", expr.ErrInvalidCharacter, 2, 3, "$"},
+		{"multibyte character", "2+√4", expr.ErrInvalidCharacter, 2, 5, "√"},
+		{"two dots", "1+2..3", expr.ErrNumberFormatIssue, 2, 6, "2..3"},
+		{"leading dot", ".5", expr.ErrNumberFormatIssue, 0, 2, ".5"},
+		{"trailing dot", "5.", expr.ErrNumberFormatIssue, 0, 2, "5."},
+		{"leading zero", "007", expr.ErrNumberFormatIssue, 0, 3, "007"},
+		{"misplaced separator", "1+1__0", expr.ErrNumberFormatIssue, 2, 6, "1__0"},
+		{"invalid hex digit", "0x1G+1", expr.ErrNumberFormatIssue, 0, 4, "0x1G"},
+		{"exponent overflow", "1e400", expr.ErrNumberFormatIssue, 0, 5, "1e400"},
+		{"single equals sign", "a=1", expr.ErrInvalidCharacter, 1, 2, "="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := expr.Lex(tt.input)
+			assert.ErrorIs(t, err, tt.expectedErr)
+
+			var exprErr *expr.Error
+			if assert.ErrorAs(t, err, &exprErr) {
+				assert.Equal(t, tt.pos, exprErr.Pos)
+				assert.Equal(t, tt.end, exprErr.End)
+				assert.Equal(t, tt.token, exprErr.Token)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 + 2 * 3", "1+2*3"},
+		{" max( 1_000 , 0x1F ) ", "max(1_000,0x1F)"},
+		{"2 - -3", "2- -3"},
+		{"6.02E23 / 1e-3", "6.02E23/1e-3"},
+		{"a xor 0xFF & b", "a xor 0xFF&b"},
+		{"(a) xor -1", "(a)xor -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tokens, err := expr.Lex(tt.input)
+			assert.NoError(t, err)
+
+			formatted := expr.Format(tokens)
+			assert.Equal(t, tt.expected, formatted)
+
+			relexed, err := expr.Lex(formatted)
+			assert.NoError(t, err)
+			assert.Equal(t, expr.Format(relexed), formatted)
+		})
+	}
+}
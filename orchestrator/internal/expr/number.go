@@ -0,0 +1,121 @@
+package expr
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var errInexactLiteral = errors.New("integer literal cannot be represented exactly")
+
+func ParseNumber(text string) (float64, error) {
+	if hasBasePrefix(text) {
+		value, err := strconv.ParseUint(text, 0, 64)
+		if err != nil {
+			return 0, err
+		}
+		result := float64(value)
+		if result >= math.MaxUint64 || uint64(result) != value {
+			return 0, errInexactLiteral
+		}
+		return result, nil
+	}
+
+	mantissa := text
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		mantissa = text[:i]
+	}
+	if !isValidMantissa(mantissa) {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.ParseFloat(text, 64)
+}
+
+func ParseDecimal(text string) (*big.Rat, error) {
+	if _, err := ParseNumber(text); err != nil {
+		return nil, err
+	}
+	if hasBasePrefix(text) {
+		value, ok := new(big.Int).SetString(text, 0)
+		if !ok {
+			return nil, strconv.ErrSyntax
+		}
+		return new(big.Rat).SetInt(value), nil
+	}
+	value, ok := new(big.Rat).SetString(strings.ReplaceAll(text, "_", ""))
+	if !ok {
+		return nil, strconv.ErrSyntax
+	}
+	return value, nil
+}
+
+func FormatDecimal(value *big.Rat) string {
+	if value.IsInt() {
+		return value.Num().String()
+	}
+	denom := new(big.Int).Set(value.Denom())
+	digits := 0
+	for _, factor := range []int64{2, 5} {
+		count := 0
+		divisor := big.NewInt(factor)
+		remainder := new(big.Int)
+		for {
+			quotient, mod := new(big.Int).QuoRem(denom, divisor, remainder)
+			if mod.Sign() != 0 {
+				break
+			}
+			denom = quotient
+			count++
+		}
+		digits = max(digits, count)
+	}
+	return value.FloatString(digits)
+}
+
+func scanNumber(input string, start int) int {
+	i := start
+	if hasBasePrefix(input[i:]) {
+		i += 2
+		for i < len(input) && (isLetter(input[i]) || isDigit(input[i])) {
+			i++
+		}
+		return i
+	}
+
+	for i < len(input) && (isDigit(input[i]) || input[i] == '.' || input[i] == '_') {
+		i++
+	}
+	if i < len(input) && (input[i] == 'e' || input[i] == 'E') {
+		j := i + 1
+		if j < len(input) && (input[j] == '+' || input[j] == '-') {
+			j++
+		}
+		if j < len(input) && isDigit(input[j]) {
+			for j < len(input) && (isDigit(input[j]) || input[j] == '_') {
+				j++
+			}
+			i = j
+		}
+	}
+	return i
+}
+
+func hasBasePrefix(text string) bool {
+	if len(text) < 2 || text[0] != '0' {
+		return false
+	}
+	switch text[1] {
+	case 'x', 'X', 'b', 'B', 'o', 'O':
+		return true
+	}
+	return false
+}
+
+func isValidMantissa(text string) bool {
+	if text == "" || text[0] == '.' || text[len(text)-1] == '.' {
+		return false
+	}
+	return !(len(text) > 1 && text[0] == '0' && text[1] != '.')
+}
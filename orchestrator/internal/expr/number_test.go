@@ -0,0 +1,77 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected float64
+	}{
+		{"42", 42},
+		{"0", 0},
+		{"0.25", 0.25},
+		{"1e-3", 0.001},
+		{"6.02E23", 6.02e23},
+		{"2.5e+2", 250},
+		{"0e5", 0},
+		{"1_000_000", 1000000},
+		{"1_000.000_1", 1000.0001},
+		{"0x1F", 31},
+		{"0X_ff", 255},
+		{"0b1010", 10},
+		{"0B1_0", 2},
+		{"0o17", 15},
+		{"0x8000000000000000", 1 << 63},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			value, err := expr.ParseNumber(tt.text)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestParseNumber_Invalid(t *testing.T) {
+	for _, text := range []string{
+		"01", "0_1", ".5", "5.", "1.2.3", "1__0", "1_", "1_.5",
+		"1e999", "0x", "0x1G", "0b102", "0o8", "0x1FFFFFFFFFFFFF1",
+		"0x10000000000000000",
+	} {
+		t.Run(text, func(t *testing.T) {
+			_, err := expr.ParseNumber(text)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"0.1", "0.1"},
+		{"1_000.25", "1000.25"},
+		{"1e-3", "0.001"},
+		{"6.02E23", "602000000000000000000000"},
+		{"0x1F", "31"},
+		{"0b1010", "10"},
+		{"0x1FFFFFFFFFFFFF", "9007199254740991"},
+		{"12.50", "12.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			value, err := expr.ParseDecimal(tt.text)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, expr.FormatDecimal(value))
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package expr
+
+const (
+	PrecedenceConditional    = 1
+	PrecedenceOr             = 2
+	PrecedenceAnd            = 3
+	PrecedenceEquality       = 4
+	PrecedenceComparison     = 5
+	PrecedenceBitwiseOr      = 6
+	PrecedenceBitwiseXor     = 7
+	PrecedenceBitwiseAnd     = 8
+	PrecedenceShift          = 9
+	PrecedenceAdditive       = 10
+	PrecedenceMultiplicative = 11
+	PrecedenceUnary          = 12
+	PrecedencePower          = 13
+)
+
+// MaxNestingDepth bounds parser recursion so deeply nested input is rejected
+// with an error instead of exhausting the goroutine stack.
+const MaxNestingDepth = 256
+
+var operandStart = []string{"number", "identifier", "("}
+
+func Precedence(op string) int {
+	switch op {
+	case "?":
+		return PrecedenceConditional
+	case "||":
+		return PrecedenceOr
+	case "&&":
+		return PrecedenceAnd
+	case "==", "!=":
+		return PrecedenceEquality
+	case "<", "<=", ">", ">=":
+		return PrecedenceComparison
+	case "|":
+		return PrecedenceBitwiseOr
+	case "xor":
+		return PrecedenceBitwiseXor
+	case "&":
+		return PrecedenceBitwiseAnd
+	case "<<", ">>":
+		return PrecedenceShift
+	case "+", "-":
+		return PrecedenceAdditive
+	case "*", "/", "//", "%":
+		return PrecedenceMultiplicative
+	case "^":
+		return PrecedencePower
+	}
+	return 0
+}
+
+func IsRightAssociative(op string) bool {
+	return op == "^"
+}
+
+type parser struct {
+	tokens  []Token
+	pos     int
+	depth   int
+	nesting int
+}
+
+func Parse(input string) (Node, error) {
+	tokens, err := Lex(input)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTokens(tokens)
+}
+
+func ParseTokens(tokens []Token) (Node, error) {
+	if tokens[0].Kind == EOF {
+		return nil, NewError(ErrEmptyExpression, 0, 0, "")
+	}
+
+	p := &parser{tokens: tokens}
+	node, err := p.parseExpr(PrecedenceConditional)
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := p.peek(); {
+	case tok.Kind == EOF:
+		return node, nil
+	case tok.IsOperator(":"):
+		return nil, NewError(ErrConditionalIssue, tok.Pos, tok.End(), tok.Text, "operator")
+	case tok.Kind == Comma:
+		return nil, NewError(ErrSeparatorIssue, tok.Pos, tok.End(), tok.Text, "operator")
+	default:
+		return nil, NewError(ErrParenthesisIssue, tok.Pos, tok.End(), tok.Text, "operator")
+	}
+}
+
+func (p *parser) peek() Token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) prev() (Token, bool) {
+	if p.pos == 0 {
+		return Token{}, false
+	}
+	return p.tokens[p.pos-1], true
+}
+
+func (p *parser) next() Token {
+	tok := p.tokens[p.pos]
+	if tok.Kind != EOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseExpr(minPrecedence int) (Node, error) {
+	if p.nesting >= MaxNestingDepth {
+		tok := p.peek()
+		return nil, NewError(ErrNestingTooDeep, tok.Pos, tok.End(), tok.Text)
+	}
+	p.nesting++
+	defer func() { p.nesting-- }()
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok.Kind != Operator {
+			if tok.Kind == Number || tok.Kind == Identifier || tok.Kind == LeftParen {
+				return nil, p.missingOperator(tok)
+			}
+			return left, nil
+		}
+
+		op := tok.Operator()
+		precedence := Precedence(op)
+		if precedence < minPrecedence {
+			return left, nil
+		}
+		p.next()
+
+		if op == "?" {
+			left, err = p.parseConditional(left, tok)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		nextPrecedence := precedence + 1
+		if IsRightAssociative(op) {
+			nextPrecedence = precedence
+		}
+		right, err := p.parseExpr(nextPrecedence)
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: op, OpPos: tok.Pos, X: left, Y: right}
+	}
+}
+
+func (p *parser) parseOperand() (Node, error) {
+	tok := p.next()
+	switch tok.Kind {
+	case Number:
+		value, err := ParseNumber(tok.Text)
+		if err != nil {
+			return nil, NewError(ErrNumberFormatIssue, tok.Pos, tok.End(), tok.Text)
+		}
+		return &NumberLit{Value: value, Raw: tok.Text, ValuePos: tok.Pos}, nil
+	case Identifier:
+		if p.peek().Kind == LeftParen {
+			return p.parseCall(tok)
+		}
+		return &Ident{Name: tok.Text, NamePos: tok.Pos}, nil
+	case LeftParen:
+		if closing := p.peek(); closing.Kind == RightParen {
+			return nil, NewError(ErrParenthesisIssue, tok.Pos, closing.End(), "()", operandStart...)
+		}
+		p.depth++
+		x, err := p.parseExpr(PrecedenceConditional)
+		if err != nil {
+			return nil, err
+		}
+		closing, err := p.expectClosing(tok)
+		if err != nil {
+			return nil, err
+		}
+		p.depth--
+		return &ParenExpr{Lparen: tok.Pos, X: x, Rparen: closing.Pos}, nil
+	case Operator:
+		if tok.IsOperator("+", "-", "!") {
+			x, err := p.parseExpr(PrecedenceUnary + 1)
+			if err != nil {
+				return nil, err
+			}
+			return &UnaryExpr{Op: tok.Text, OpPos: tok.Pos, X: x}, nil
+		}
+		if p.pos == 1 {
+			return nil, NewError(ErrInvalidExpressionStartEnd, tok.Pos, tok.End(), tok.Text, operandStart...)
+		}
+		return nil, NewError(ErrOperatorIssue, tok.Pos, tok.End(), tok.Text, operandStart...)
+	case Comma:
+		return nil, NewError(ErrSeparatorIssue, tok.Pos, tok.End(), tok.Text, operandStart...)
+	case RightParen:
+		if p.depth == 0 {
+			return nil, NewError(ErrParenthesisIssue, tok.Pos, tok.End(), tok.Text, operandStart...)
+		}
+		return nil, p.missingOperand(tok)
+	}
+
+	if p.depth > 0 {
+		return nil, NewError(ErrParenthesisIssue, tok.Pos, tok.Pos, "", append(operandStart, ")")...)
+	}
+	return nil, p.missingOperand(tok)
+}
+
+func (p *parser) parseConditional(cond Node, question Token) (Node, error) {
+	x, err := p.parseExpr(PrecedenceConditional)
+	if err != nil {
+		return nil, err
+	}
+	colon := p.peek()
+	if !colon.IsOperator(":") {
+		return nil, NewError(ErrConditionalIssue, question.Pos, question.End(), question.Text, ":")
+	}
+	p.next()
+	y, err := p.parseExpr(PrecedenceConditional)
+	if err != nil {
+		return nil, err
+	}
+	return &CondExpr{Cond: cond, Question: question.Pos, X: x, Colon: colon.Pos, Y: y}, nil
+}
+
+func (p *parser) parseCall(name Token) (Node, error) {
+	lparen := p.next()
+	call := &CallExpr{Func: name.Text, NamePos: name.Pos}
+
+	p.depth++
+	if p.peek().Kind != RightParen {
+		for {
+			arg, err := p.parseExpr(PrecedenceConditional)
+			if err != nil {
+				return nil, err
+			}
+			call.Args = append(call.Args, arg)
+			if p.peek().Kind != Comma {
+				break
+			}
+			p.next()
+		}
+	}
+
+	closing, err := p.expectClosing(lparen)
+	if err != nil {
+		return nil, err
+	}
+	p.depth--
+	call.Rparen = closing.Pos
+	return call, nil
+}
+
+func (p *parser) expectClosing(lparen Token) (Token, error) {
+	tok := p.next()
+	switch tok.Kind {
+	case RightParen:
+		return tok, nil
+	case Comma:
+		return Token{}, NewError(ErrSeparatorIssue, tok.Pos, tok.End(), tok.Text, "operator", ")")
+	case EOF:
+		return Token{}, NewError(ErrParenthesisIssue, lparen.Pos, lparen.End(), lparen.Text, "operator", ")")
+	}
+	return Token{}, NewError(ErrUnexpectedToken, tok.Pos, tok.End(), tok.Text, "operator", ")")
+}
+
+func (p *parser) missingOperand(tok Token) error {
+	index := p.pos - 1
+	if tok.Kind != EOF {
+		index--
+	}
+	ok := index >= 0
+	var prev Token
+	if ok {
+		prev = p.tokens[index]
+	}
+	switch {
+	case ok && prev.Kind == Comma:
+		return NewError(ErrSeparatorIssue, prev.Pos, prev.End(), prev.Text, operandStart...)
+	case ok && prev.Kind == Operator && tok.Kind == EOF:
+		return NewError(ErrInvalidExpressionStartEnd, prev.Pos, prev.End(), prev.Text, operandStart...)
+	case ok && prev.Kind == Operator:
+		return NewError(ErrOperatorIssue, prev.Pos, prev.End(), prev.Text, operandStart...)
+	}
+	return NewError(ErrUnexpectedToken, tok.Pos, tok.End(), tok.Text, operandStart...)
+}
+
+func (p *parser) missingOperator(tok Token) error {
+	prev, _ := p.prev()
+	switch {
+	case prev.Kind == RightParen || tok.Kind == LeftParen:
+		return NewError(ErrParenthesisIssue, tok.Pos, tok.End(), tok.Text, "operator")
+	case tok.Kind == Identifier:
+		return NewError(ErrFunctionCallIssue, tok.Pos, tok.End(), tok.Text, "operator")
+	}
+	return NewError(ErrUnexpectedToken, tok.Pos, tok.End(), tok.Text, "operator")
+}
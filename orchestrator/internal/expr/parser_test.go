@@ -0,0 +1,240 @@
+package expr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected expr.Node
+	}{
+		{
+			name:  "precedence",
+			input: "1+2*3",
+			expected: &expr.BinaryExpr{
+				Op: "+", OpPos: 1,
+				X: &expr.NumberLit{Value: 1, Raw: "1", ValuePos: 0},
+				Y: &expr.BinaryExpr{
+					Op: "*", OpPos: 3,
+					X: &expr.NumberLit{Value: 2, Raw: "2", ValuePos: 2},
+					Y: &expr.NumberLit{Value: 3, Raw: "3", ValuePos: 4},
+				},
+			},
+		},
+		{
+			name:  "left associativity",
+			input: "1-2-3",
+			expected: &expr.BinaryExpr{
+				Op: "-", OpPos: 3,
+				X: &expr.BinaryExpr{
+					Op: "-", OpPos: 1,
+					X: &expr.NumberLit{Value: 1, Raw: "1", ValuePos: 0},
+					Y: &expr.NumberLit{Value: 2, Raw: "2", ValuePos: 2},
+				},
+				Y: &expr.NumberLit{Value: 3, Raw: "3", ValuePos: 4},
+			},
+		},
+		{
+			name:  "right associative power",
+			input: "2**3^2",
+			expected: &expr.BinaryExpr{
+				Op: "^", OpPos: 1,
+				X: &expr.NumberLit{Value: 2, Raw: "2", ValuePos: 0},
+				Y: &expr.BinaryExpr{
+					Op: "^", OpPos: 4,
+					X: &expr.NumberLit{Value: 3, Raw: "3", ValuePos: 3},
+					Y: &expr.NumberLit{Value: 2, Raw: "2", ValuePos: 5},
+				},
+			},
+		},
+		{
+			name:  "unary minus binds weaker than power",
+			input: "-2^2",
+			expected: &expr.UnaryExpr{
+				Op: "-", OpPos: 0,
+				X: &expr.BinaryExpr{
+					Op: "^", OpPos: 2,
+					X: &expr.NumberLit{Value: 2, Raw: "2", ValuePos: 1},
+					Y: &expr.NumberLit{Value: 2, Raw: "2", ValuePos: 3},
+				},
+			},
+		},
+		{
+			name:  "comparison binds weaker than arithmetic",
+			input: "1+2<3&&!x",
+			expected: &expr.BinaryExpr{
+				Op: "&&", OpPos: 5,
+				X: &expr.BinaryExpr{
+					Op: "<", OpPos: 3,
+					X: &expr.BinaryExpr{
+						Op: "+", OpPos: 1,
+						X: &expr.NumberLit{Value: 1, Raw: "1", ValuePos: 0},
+						Y: &expr.NumberLit{Value: 2, Raw: "2", ValuePos: 2},
+					},
+					Y: &expr.NumberLit{Value: 3, Raw: "3", ValuePos: 4},
+				},
+				Y: &expr.UnaryExpr{Op: "!", OpPos: 7, X: &expr.Ident{Name: "x", NamePos: 8}},
+			},
+		},
+		{
+			name:  "bitwise precedence",
+			input: "a|b xor c&d<<1",
+			expected: &expr.BinaryExpr{
+				Op: "|", OpPos: 1,
+				X: &expr.Ident{Name: "a", NamePos: 0},
+				Y: &expr.BinaryExpr{
+					Op: "xor", OpPos: 4,
+					X: &expr.Ident{Name: "b", NamePos: 2},
+					Y: &expr.BinaryExpr{
+						Op: "&", OpPos: 9,
+						X: &expr.Ident{Name: "c", NamePos: 8},
+						Y: &expr.BinaryExpr{
+							Op: "<<", OpPos: 11,
+							X: &expr.Ident{Name: "d", NamePos: 10},
+							Y: &expr.NumberLit{Value: 1, Raw: "1", ValuePos: 13},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "right associative conditional",
+			input: "a||b?1:c?2:3",
+			expected: &expr.CondExpr{
+				Cond: &expr.BinaryExpr{
+					Op: "||", OpPos: 1,
+					X: &expr.Ident{Name: "a", NamePos: 0},
+					Y: &expr.Ident{Name: "b", NamePos: 3},
+				},
+				Question: 4,
+				X:        &expr.NumberLit{Value: 1, Raw: "1", ValuePos: 5},
+				Colon:    6,
+				Y: &expr.CondExpr{
+					Cond:     &expr.Ident{Name: "c", NamePos: 7},
+					Question: 8,
+					X:        &expr.NumberLit{Value: 2, Raw: "2", ValuePos: 9},
+					Colon:    10,
+					Y:        &expr.NumberLit{Value: 3, Raw: "3", ValuePos: 11},
+				},
+			},
+		},
+		{
+			name:  "call with parenthesized argument",
+			input: "max(1,(x))",
+			expected: &expr.CallExpr{
+				Func: "max", NamePos: 0, Rparen: 9,
+				Args: []expr.Node{
+					&expr.NumberLit{Value: 1, Raw: "1", ValuePos: 4},
+					&expr.ParenExpr{Lparen: 6, Rparen: 8, X: &expr.Ident{Name: "x", NamePos: 7}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := expr.Parse(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, node)
+			assert.Equal(t, 0, node.Pos())
+			assert.Equal(t, len(tt.input), node.End())
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr error
+		pos         int
+		token       string
+	}{
+		{"empty", "", expr.ErrEmptyExpression, 0, ""},
+		{"operator at start", "*2", expr.ErrInvalidExpressionStartEnd, 0, "*"},
+		{"operator at end", "2+3+", expr.ErrInvalidExpressionStartEnd, 3, "+"},
+		{"consecutive operators", "2+*3", expr.ErrOperatorIssue, 2, "*"},
+		{"operator before closing parenthesis", "(2+)", expr.ErrOperatorIssue, 2, "+"},
+		{"empty parentheses", "2+()", expr.ErrParenthesisIssue, 2, "()"},
+		{"unclosed parenthesis", "(2+3", expr.ErrParenthesisIssue, 0, "("},
+		{"unopened parenthesis", "2+3)", expr.ErrParenthesisIssue, 3, ")"},
+		{"number after parenthesis", "(2+3)4", expr.ErrParenthesisIssue, 5, "4"},
+		{"identifier after number", "2x", expr.ErrFunctionCallIssue, 1, "x"},
+		{"number after number", "2 3", expr.ErrUnexpectedToken, 2, "3"},
+		{"separator outside call", "(1,2)", expr.ErrSeparatorIssue, 2, ","},
+		{"trailing separator", "max(1,)", expr.ErrSeparatorIssue, 5, ","},
+		{"conditional without alternative", "x?1", expr.ErrConditionalIssue, 1, "?"},
+		{"colon without condition", "1:2", expr.ErrConditionalIssue, 1, ":"},
+		{"word operator at start", "xor 1", expr.ErrInvalidExpressionStartEnd, 0, "xor"},
+		{"conditional missing colon in parentheses", "(x?1)", expr.ErrConditionalIssue, 2, "?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := expr.Parse(tt.input)
+			assert.ErrorIs(t, err, tt.expectedErr)
+
+			var exprErr *expr.Error
+			if assert.ErrorAs(t, err, &exprErr) {
+				assert.Equal(t, tt.pos, exprErr.Pos)
+				assert.Equal(t, tt.token, exprErr.Token)
+			}
+		})
+	}
+}
+
+func TestParse_NestingLimit(t *testing.T) {
+	depth := expr.MaxNestingDepth / 2
+	_, err := expr.Parse(strings.Repeat("(", depth) + "1" + strings.Repeat(")", depth))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"parentheses", strings.Repeat("(", 10_000) + "1"},
+		{"unary operators", strings.Repeat("-", 10_000) + "1"},
+		{"right associative power", strings.Repeat("2^", 10_000) + "2"},
+		{"function calls", strings.Repeat("abs(", 10_000) + "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := expr.Parse(tt.input)
+			assert.ErrorIs(t, err, expr.ErrNestingTooDeep)
+
+			var exprErr *expr.Error
+			if assert.ErrorAs(t, err, &exprErr) {
+				assert.Positive(t, exprErr.Pos)
+			}
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	node, err := expr.Parse("sin(x)+2")
+	assert.NoError(t, err)
+
+	var visited []string
+	expr.Walk(node, func(n expr.Node) bool {
+		switch n := n.(type) {
+		case *expr.BinaryExpr:
+			visited = append(visited, n.Op)
+		case *expr.CallExpr:
+			visited = append(visited, n.Func)
+		case *expr.Ident:
+			visited = append(visited, n.Name)
+		case *expr.NumberLit:
+			visited = append(visited, n.Raw)
+		}
+		return true
+	})
+	assert.Equal(t, []string{"+", "sin", "x", "2"}, visited)
+}
@@ -0,0 +1,75 @@
+package expr
+
+func String(node Node) string {
+	p := &printer{}
+	p.print(node)
+	return Format(p.tokens)
+}
+
+type printer struct {
+	tokens []Token
+}
+
+func (p *printer) emit(kind TokenKind, text string) {
+	p.tokens = append(p.tokens, Token{Kind: kind, Text: text})
+}
+
+func (p *printer) print(node Node) {
+	switch n := node.(type) {
+	case *NumberLit:
+		p.emit(Number, n.Raw)
+	case *Ident:
+		p.emit(Identifier, n.Name)
+	case *ParenExpr:
+		p.print(n.X)
+	case *UnaryExpr:
+		p.emit(Operator, n.Op)
+		_, unary := Unparen(n.X).(*UnaryExpr)
+		p.group(n.X, !unary && nodePrecedence(n.X) < PrecedencePower)
+	case *BinaryExpr:
+		precedence := Precedence(n.Op)
+		left, right := nodePrecedence(n.X), nodePrecedence(n.Y)
+		p.group(n.X, left < precedence || left == precedence && IsRightAssociative(n.Op))
+		p.emit(Operator, n.Op)
+		_, unary := Unparen(n.Y).(*UnaryExpr)
+		p.group(n.Y, !unary && (right < precedence || right == precedence && !IsRightAssociative(n.Op)))
+	case *CondExpr:
+		p.group(n.Cond, nodePrecedence(n.Cond) == PrecedenceConditional)
+		p.emit(Operator, "?")
+		p.print(n.X)
+		p.emit(Operator, ":")
+		p.print(n.Y)
+	case *CallExpr:
+		p.emit(Identifier, n.Func)
+		p.emit(LeftParen, "(")
+		for i, arg := range n.Args {
+			if i > 0 {
+				p.emit(Comma, ",")
+			}
+			p.print(arg)
+		}
+		p.emit(RightParen, ")")
+	}
+}
+
+func (p *printer) group(node Node, parens bool) {
+	if !parens {
+		p.print(node)
+		return
+	}
+	p.emit(LeftParen, "(")
+	p.print(node)
+	p.emit(RightParen, ")")
+}
+
+func nodePrecedence(node Node) int {
+	switch n := Unparen(node).(type) {
+	case *BinaryExpr:
+		return Precedence(n.Op)
+	case *CondExpr:
+		return PrecedenceConditional
+	case *UnaryExpr:
+		return PrecedenceUnary
+	}
+	return PrecedencePower + 1
+}
@@ -0,0 +1,41 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"redundant parentheses", "((1+2))*(3)", "(1+2)*3"},
+		{"left associativity", "(1-2)-(3-4)", "1-2-(3-4)"},
+		{"right associative power", "(2^3)^2 + 2^(3^2)", "(2^3)^2+2^3^2"},
+		{"unary operand", "-(x^2) + (-x)^2", "-x^2+(-x)^2"},
+		{"unary after operator", "x*(-y) - (-z)", "x* -y- -z"},
+		{"negated sum", "-(a+b)", "-(a+b)"},
+		{"word operator", "a xor (b & c)", "a xor b&c"},
+		{"conditional", "(a ? b : c) ? d : (e ? f : g)", "(a?b:c)?d:e?f:g"},
+		{"call arguments", "max( (1), x+1 , sin(y) )", "max(1,x+1,sin(y))"},
+		{"logical", "!(a && b) || c < d", "!(a&&b)||c<d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := expr.Parse(tt.input)
+			assert.NoError(t, err)
+			output := expr.String(node)
+			assert.Equal(t, tt.expected, output)
+
+			reparsed, err := expr.Parse(output)
+			assert.NoError(t, err)
+			assert.Equal(t, output, expr.String(reparsed))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package expr
+
+type Statement struct {
+	Name    string
+	NamePos int
+	Value   Node
+}
+
+func (s *Statement) Pos() int {
+	if s.Name != "" {
+		return s.NamePos
+	}
+	return s.Value.Pos()
+}
+
+func (s *Statement) End() int {
+	return s.Value.End()
+}
+
+type Script struct {
+	Statements []*Statement
+}
+
+func ParseScript(input string) (*Script, error) {
+	tokens, err := LexScript(input)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScriptTokens(tokens)
+}
+
+func ParseScriptTokens(tokens []Token) (*Script, error) {
+	if tokens[0].Kind == EOF {
+		return nil, NewError(ErrEmptyExpression, 0, 0, "")
+	}
+
+	script := &Script{}
+	start := 0
+	for i, tok := range tokens {
+		if tok.Kind != Semicolon && tok.Kind != EOF {
+			continue
+		}
+		if i == start {
+			if tok.Kind == EOF && len(script.Statements) > 0 {
+				break
+			}
+			return nil, NewError(ErrStatementIssue, tok.Pos, tok.End(), tok.Text, operandStart...)
+		}
+		stmt, err := parseStatement(tokens[start:i], tok)
+		if err != nil {
+			return nil, err
+		}
+		script.Statements = append(script.Statements, stmt)
+		start = i + 1
+	}
+	return script, nil
+}
+
+func parseStatement(tokens []Token, end Token) (*Statement, error) {
+	stmt := &Statement{}
+	if len(tokens) > 1 && tokens[1].Kind == Assign {
+		if tokens[0].Kind != Identifier {
+			return nil, NewError(ErrAssignmentIssue, tokens[0].Pos, tokens[0].End(), tokens[0].Text, "identifier")
+		}
+		stmt.Name, stmt.NamePos = tokens[0].Text, tokens[0].Pos
+		if len(tokens) == 2 {
+			return nil, NewError(ErrStatementIssue, tokens[1].Pos, tokens[1].End(), tokens[1].Text, operandStart...)
+		}
+		tokens = tokens[2:]
+	}
+	for _, tok := range tokens {
+		if tok.Kind == Assign {
+			return nil, NewError(ErrAssignmentIssue, tok.Pos, tok.End(), tok.Text, "operator")
+		}
+	}
+
+	value, err := ParseTokens(append(tokens[:len(tokens):len(tokens)], Token{Kind: EOF, Pos: end.Pos}))
+	if err != nil {
+		return nil, err
+	}
+	stmt.Value = value
+	return stmt, nil
+}
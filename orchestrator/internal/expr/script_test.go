@@ -0,0 +1,79 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScript(t *testing.T) {
+	script, err := expr.ParseScript("a = 3*4; b = a + 2; b / 7;")
+	assert.NoError(t, err)
+	assert.Equal(t, &expr.Script{Statements: []*expr.Statement{
+		{
+			Name: "a", NamePos: 0,
+			Value: &expr.BinaryExpr{
+				Op: "*", OpPos: 5,
+				X: &expr.NumberLit{Value: 3, Raw: "3", ValuePos: 4},
+				Y: &expr.NumberLit{Value: 4, Raw: "4", ValuePos: 6},
+			},
+		},
+		{
+			Name: "b", NamePos: 9,
+			Value: &expr.BinaryExpr{
+				Op: "+", OpPos: 15,
+				X: &expr.Ident{Name: "a", NamePos: 13},
+				Y: &expr.NumberLit{Value: 2, Raw: "2", ValuePos: 17},
+			},
+		},
+		{
+			Value: &expr.BinaryExpr{
+				Op: "/", OpPos: 22,
+				X: &expr.Ident{Name: "b", NamePos: 20},
+				Y: &expr.NumberLit{Value: 7, Raw: "7", ValuePos: 24},
+			},
+		},
+	}}, script)
+	assert.Equal(t, 0, script.Statements[0].Pos())
+	assert.Equal(t, 25, script.Statements[2].End())
+
+	tokens, err := expr.LexScript("a = 3*4; b = a == 2 ? 1 : 0")
+	assert.NoError(t, err)
+	assert.Equal(t, "a=3*4; b=a==2?1:0", expr.Format(tokens))
+}
+
+func TestParseScript_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr error
+		pos         int
+		token       string
+	}{
+		{"empty", "", expr.ErrEmptyExpression, 0, ""},
+		{"leading separator", "; 1+2", expr.ErrStatementIssue, 0, ";"},
+		{"empty statement", "a = 1;; a+1", expr.ErrStatementIssue, 6, ";"},
+		{"missing value", "a = ; a+1", expr.ErrStatementIssue, 2, "="},
+		{"number as target", "1 = 2", expr.ErrAssignmentIssue, 0, "1"},
+		{"assignment inside expression", "a + b = 2", expr.ErrAssignmentIssue, 6, "="},
+		{"chained assignment", "a = b = 2", expr.ErrAssignmentIssue, 6, "="},
+		{"invalid statement value", "a = 2+; a", expr.ErrInvalidExpressionStartEnd, 5, "+"},
+		{"comparison is not assignment", "a == ; 1", expr.ErrInvalidExpressionStartEnd, 2, "=="},
+		{"invalid character", "a = 1 # b", expr.ErrInvalidCharacter, 6, "#"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := expr.ParseScript(tt.input)
+			assert.ErrorIs(t, err, tt.expectedErr)
+
+			var exprErr *expr.Error
+			if assert.ErrorAs(t, err, &exprErr) {
+				assert.Equal(t, tt.pos, exprErr.Pos)
+				assert.Equal(t, tt.token, exprErr.Token)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package expr
+
+import "strings"
+
+type TokenKind int
+
+const (
+	EOF TokenKind = iota
+	Number
+	Identifier
+	Operator
+	LeftParen
+	RightParen
+	Comma
+	Assign
+	Semicolon
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case EOF:
+		return "end of input"
+	case Number:
+		return "number"
+	case Identifier:
+		return "identifier"
+	case Operator:
+		return "operator"
+	case LeftParen:
+		return "("
+	case RightParen:
+		return ")"
+	case Comma:
+		return ","
+	case Assign:
+		return "="
+	case Semicolon:
+		return ";"
+	}
+	return "unknown"
+}
+
+func (k TokenKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+type Token struct {
+	Kind TokenKind `json:"kind"`
+	Text string    `json:"text"`
+	Pos  int       `json:"pos"`
+}
+
+func (t Token) End() int {
+	return t.Pos + len(t.Text)
+}
+
+func (t Token) Operator() string {
+	if t.Text == "**" {
+		return "^"
+	}
+	return t.Text
+}
+
+func (t Token) IsOperator(ops ...string) bool {
+	if t.Kind != Operator {
+		return false
+	}
+	for _, op := range ops {
+		if t.Operator() == op {
+			return true
+		}
+	}
+	return false
+}
+
+func Format(tokens []Token) string {
+	var sb strings.Builder
+	var prev Token
+	for i, tok := range tokens {
+		if tok.Kind == EOF {
+			break
+		}
+		if i > 0 && (isWord(prev) && isWord(tok) || prev.Kind == Operator && tok.Kind == Operator || prev.Kind == Semicolon) {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(tok.Text)
+		prev = tok
+	}
+	return sb.String()
+}
+
+func isWord(tok Token) bool {
+	return tok.Kind == Number || tok.Kind == Identifier || tok.Kind == Operator && isLetter(tok.Text[0])
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,27 +14,145 @@ const (
 	Pending    Status = "pending"
 	InProgress Status = "in progress"
 	Done       Status = "done"
+	Failed     Status = "failed"
+	Skipped    Status = "skipped"
+	Cancelled  Status = "cancelled"
 )
 
+type Precision string
+
+const (
+	PrecisionFloat   Precision = "float"
+	PrecisionDecimal Precision = "decimal"
+)
+
+type NumericPolicy string
+
+const (
+	PolicyStrict   NumericPolicy = "strict"
+	PolicyIEEE     NumericPolicy = "ieee"
+	PolicySaturate NumericPolicy = "saturate"
+)
+
+const ConditionalOperator = "if"
+
 type Expression struct {
-	ID         uuid.UUID `json:"id"`
-	UserID     uuid.UUID `json:"user_id,omitempty"`
-	Expression string    `json:"expression"`
-	Status     Status    `json:"status"`
-	Result     float64   `json:"result,omitempty"`
+	ID              uuid.UUID         `json:"id"`
+	UserID          uuid.UUID         `json:"user_id,omitempty"`
+	Expression      string            `json:"expression"`
+	Status          Status            `json:"status"`
+	Result          float64           `json:"result,omitempty"`
+	Precision       Precision         `json:"precision,omitempty"`
+	Scale           int               `json:"scale,omitempty"`
+	DecimalResult   string            `json:"decimal_result,omitempty"`
+	Variables       map[string]string `json:"variables,omitempty"`
+	Policy          NumericPolicy     `json:"policy,omitempty"`
+	Values          []*NamedValue     `json:"values,omitempty"`
+	PercentComplete float64           `json:"percent_complete"`
+	Error           string            `json:"error,omitempty"`
+}
+
+type NamedValue struct {
+	Name    string     `json:"name"`
+	Value   *Number    `json:"value,omitempty"`
+	Decimal string     `json:"decimal_value,omitempty"`
+	TaskID  *uuid.UUID `json:"-"`
+}
+
+func (e Expression) MarshalJSON() ([]byte, error) {
+	type expression Expression
+	var result *Number
+	if e.Status == Done {
+		value := Number(e.Result)
+		result = &value
+	}
+	return json.Marshal(struct {
+		expression
+		Result *Number `json:"result,omitempty"`
+	}{expression(e), result})
+}
+
+type Number float64
+
+func (n Number) MarshalJSON() ([]byte, error) {
+	value := float64(n)
+	switch {
+	case math.IsNaN(value):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(value, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(value, -1):
+		return []byte(`"-Infinity"`), nil
+	}
+	return json.Marshal(value)
+}
+
+type Variable struct {
+	UserID  uuid.UUID `json:"-"`
+	Name    string    `json:"name"`
+	Value   float64   `json:"value"`
+	Decimal string    `json:"decimal"`
+}
+
+type UserFunction struct {
+	UserID uuid.UUID `json:"-"`
+	Name   string    `json:"name"`
+	Params []string  `json:"params"`
+	Body   string    `json:"body"`
+}
+
+type Agent struct {
+	ID             string      `json:"id"`
+	Hostname       string      `json:"hostname"`
+	Version        string      `json:"version"`
+	Capacity       int         `json:"capacity"`
+	Operators      []string    `json:"operators"`
+	RegisteredAt   time.Time   `json:"registered_at"`
+	LastSeen       time.Time   `json:"last_seen"`
+	InFlightTasks  []uuid.UUID `json:"in_flight_tasks"`
+	TasksPerMinute int         `json:"tasks_per_minute"`
 }
 
 type Task struct {
-	ID            uuid.UUID `json:"id"`
-	ExpressionID  uuid.UUID `json:"expression_id"`
-	Arg1          Operand   `json:"arg1"`
-	Arg2          Operand   `json:"arg2"`
-	Operator      string    `json:"operator"`
-	OperationTime time.Time `json:"operation_time"`
-	FinalTask     bool      `json:"final_task"`
+	ID            uuid.UUID  `json:"id"`
+	ExpressionID  uuid.UUID  `json:"expression_id"`
+	Args          []Operand  `json:"args"`
+	Operator      string     `json:"operator"`
+	OperationTime time.Time  `json:"operation_time"`
+	FinalTask     bool       `json:"final_task"`
+	BranchOf      *uuid.UUID `json:"branch_of,omitempty"`
+	ThenBranch    bool       `json:"then_branch,omitempty"`
+}
+
+type TaskRecord struct {
+	ID            uuid.UUID      `json:"id"`
+	Operator      string         `json:"operator"`
+	Args          []TaskArgument `json:"args"`
+	Status        Status         `json:"status"`
+	FinalTask     bool           `json:"final_task,omitempty"`
+	BranchOf      *uuid.UUID     `json:"branch_of,omitempty"`
+	Agent         string         `json:"agent,omitempty"`
+	StartedAt     *time.Time     `json:"started_at,omitempty"`
+	FinishedAt    *time.Time     `json:"finished_at,omitempty"`
+	Result        *Number        `json:"result,omitempty"`
+	DecimalResult string         `json:"decimal_result,omitempty"`
+	Error         string         `json:"error,omitempty"`
+}
+
+type TaskArgument struct {
+	Value   *Number    `json:"value,omitempty"`
+	Decimal string     `json:"decimal,omitempty"`
+	TaskID  *uuid.UUID `json:"task_id,omitempty"`
 }
 
 type Operand struct {
-	Value  float64    `json:"value"`
-	TaskID *uuid.UUID `json:"task_id"`
+	Value   float64    `json:"value"`
+	Decimal string     `json:"decimal,omitempty"`
+	TaskID  *uuid.UUID `json:"task_id"`
+}
+
+type TaskResult struct {
+	Value   float64
+	Decimal string
+	Error   string
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/database/postgres"
@@ -12,6 +13,7 @@ import (
 	pb "github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/grpc/proto"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -26,6 +28,12 @@ var (
 	ErrDatabaseNotAvailable         = errors.New("database is not available")
 	ErrInvalidTask                  = errors.New("invalid task")
 	ErrUnknownIDTasksWithDependency = errors.New("unknown ID tasks with the dependency")
+	ErrVariableAlreadyExists        = errors.New("variable already exists")
+	ErrUnknownVariable              = errors.New("unknown variable")
+	ErrUserFunctionAlreadyExists    = errors.New("function already exists")
+	ErrUnknownUserFunction          = errors.New("unknown function")
+	ErrLeaseNotHeld                 = errors.New("task lease is not held")
+	ErrUnknownAgent                 = errors.New("unknown agent")
 )
 
 type Repository interface {
@@ -34,12 +42,29 @@ type Repository interface {
 	GetAllExpressions(ctx context.Context, userID uuid.UUID) ([]*models.Expression, error)
 	GetExpressionByID(ctx context.Context, id uuid.UUID) (*models.Expression, error)
 	CreateExpressionTask(ctx context.Context, expression *models.Expression, tasks []*models.Task) error
-	GetTask(ctx context.Context, getEndTime func(string) *timestamppb.Timestamp) (*pb.Task, error)
-	SetTaskResult(ctx context.Context, task *pb.Task, result float64) error
+	CreateExpressionTasks(ctx context.Context, expressions []*models.Expression, tasks []*models.Task) error
+	GetExpressionTasks(ctx context.Context, expressionID uuid.UUID) ([]*models.TaskRecord, error)
+	GetTask(ctx context.Context, agent string, lease time.Duration, getEndTime func(string) *timestamppb.Timestamp) (*pb.Task, error)
+	RenewLeases(ctx context.Context, agent string, taskIDs []uuid.UUID, lease time.Duration) ([]uuid.UUID, error)
+	SetTaskResult(ctx context.Context, taskID, token uuid.UUID, result models.TaskResult, normalize NormalizeResultFunc) error
 	WithTransaction(ctx context.Context, fn func(ctx context.Context, tx postgres.Tx) error) error
-	ResetExpiredTasks(ctx context.Context, delay time.Duration) error
+	ResetExpiredTasks(ctx context.Context) error
+	ReleaseTask(ctx context.Context, taskID, token uuid.UUID) error
+	CreateVariable(ctx context.Context, variable *models.Variable) error
+	UpdateVariable(ctx context.Context, variable *models.Variable) error
+	GetVariables(ctx context.Context, userID uuid.UUID) ([]*models.Variable, error)
+	GetVariable(ctx context.Context, userID uuid.UUID, name string) (*models.Variable, error)
+	DeleteVariable(ctx context.Context, userID uuid.UUID, name string) error
+	CreateUserFunction(ctx context.Context, function *models.UserFunction) error
+	GetUserFunctions(ctx context.Context, userID uuid.UUID) ([]*models.UserFunction, error)
+	DeleteUserFunction(ctx context.Context, userID uuid.UUID, name string) error
+	RegisterAgent(ctx context.Context, agent *models.Agent) error
+	TouchAgent(ctx context.Context, id string) error
+	GetLiveAgents(ctx context.Context, window time.Duration) ([]*models.Agent, error)
 }
 
+type NormalizeResultFunc func(precision models.Precision, policy models.NumericPolicy, result models.TaskResult) models.TaskResult
+
 type repository struct {
 	db postgres.DatabaseConnection
 }
@@ -93,36 +118,83 @@ func (r *repository) AuthUser(ctx context.Context, login, password string) (uuid
 }
 
 func (r *repository) CreateExpressionTask(ctx context.Context, expression *models.Expression, tasks []*models.Task) error {
+	return r.CreateExpressionTasks(ctx, []*models.Expression{expression}, tasks)
+}
+
+func (r *repository) CreateExpressionTasks(ctx context.Context, expressions []*models.Expression, tasks []*models.Task) error {
+	expressionRows := make([][]any, 0, len(expressions))
+	var valueRows [][]any
+	for _, expression := range expressions {
+		expressionRows = append(expressionRows, []any{
+			expression.ID, expression.UserID, expression.Expression, string(expression.Status), expression.Result,
+			string(expression.Precision), expression.Scale, expression.Variables, nullIfEmpty(expression.DecimalResult),
+			string(expression.Policy),
+		})
+		for position, value := range expression.Values {
+			valueRows = append(valueRows, []any{
+				expression.ID, position, value.Name, (*float64)(value.Value), nullIfEmpty(value.Decimal), value.TaskID,
+			})
+		}
+	}
+	taskRows := make([][]any, 0, len(tasks))
+	positions := make(map[uuid.UUID]int, len(expressions))
+	var argRows [][]any
+	for _, task := range tasks {
+		taskRows = append(taskRows, []any{
+			task.ID, task.ExpressionID, task.Operator, task.OperationTime, task.FinalTask, task.BranchOf, task.ThenBranch,
+			positions[task.ExpressionID],
+		})
+		positions[task.ExpressionID]++
+		for position, arg := range task.Args {
+			argRows = append(argRows, []any{task.ID, position, arg.Value, nullIfEmpty(arg.Decimal), arg.TaskID, arg.TaskID})
+		}
+	}
+
 	return r.WithTransaction(ctx, func(ctx context.Context, tx postgres.Tx) error {
-		row := tx.QueryRow(ctx,
-			`INSERT INTO expressions (id, user_id, expression, status, result) 
-VALUES ($1, $2, $3, $4, $5) RETURNING id`,
-			expression.ID, expression.UserID, expression.Expression, expression.Status, expression.Result)
-		if err := row.Scan(&expression.ID); err != nil {
+		if err := r.copyRows(ctx, tx, "expressions", []string{
+			"id", "user_id", "expression", "status", "result", "precision", "scale", "variables", "decimal_result", "numeric_policy",
+		}, expressionRows); err != nil {
 			if r.db.IsForeignKeyErr(err) {
 				return ErrUnknownUserID
 			}
-			if r.db.IsDatabaseUnavailableErr(err) {
-				return ErrDatabaseNotAvailable
-			}
-			return fmt.Errorf("failed to insert new expression: %w", err)
-		}
-
-		query := `INSERT INTO tasks (id, expression_id, arg1_value, arg1_task_id, arg2_value, arg2_task_id, operator, operation_time, final_task)
-				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
-		for _, task := range tasks {
-			if _, err := tx.Exec(ctx, query, task.ID, task.ExpressionID, task.Arg1.Value, task.Arg1.TaskID,
-				task.Arg2.Value, task.Arg2.TaskID, task.Operator, task.OperationTime, task.FinalTask); err != nil {
-				if r.db.IsDatabaseUnavailableErr(err) {
-					return ErrDatabaseNotAvailable
-				}
-				return fmt.Errorf("failed to insert new task: %w", err)
-			}
+			return err
 		}
-		return nil
+		if err := r.copyRows(ctx, tx, "tasks", []string{
+			"id", "expression_id", "operator", "operation_time", "final_task", "branch_of", "then_branch", "position",
+		}, taskRows); err != nil {
+			return err
+		}
+		if err := r.copyRows(ctx, tx, "task_arguments", []string{
+			"task_id", "position", "value", "decimal_value", "source_task_id", "origin_task_id",
+		}, argRows); err != nil {
+			return err
+		}
+		return r.copyRows(ctx, tx, "expression_values", []string{
+			"expression_id", "position", "name", "value", "decimal_value", "source_task_id",
+		}, valueRows)
 	})
 }
 
+func (r *repository) copyRows(ctx context.Context, tx postgres.Tx, table string, columns []string, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows)); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to copy rows into %s: %w", table, err)
+	}
+	return nil
+}
+
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
 func (r *repository) GetAllExpressions(ctx context.Context, userID uuid.UUID) ([]*models.Expression, error) {
 	var userExists bool
 	if err := r.db.QueryRow(
@@ -137,7 +209,10 @@ func (r *repository) GetAllExpressions(ctx context.Context, userID uuid.UUID) ([
 		return nil, ErrUnknownUserID
 	}
 
-	rows, err := r.db.Query(ctx, "SELECT * FROM expressions WHERE user_id = $1", userID)
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, expression, status, result, precision, scale, COALESCE(decimal_result, ''), variables, numeric_policy,
+		       COALESCE(error_message, ''), `+percentCompleteColumn+`
+		FROM expressions e WHERE user_id = $1`, userID)
 	if err != nil {
 		if r.db.IsDatabaseUnavailableErr(err) {
 			return nil, ErrDatabaseNotAvailable
@@ -149,7 +224,9 @@ func (r *repository) GetAllExpressions(ctx context.Context, userID uuid.UUID) ([
 	expressions := make([]*models.Expression, 0)
 	for rows.Next() {
 		var expression models.Expression
-		if err := rows.Scan(&expression.ID, &expression.UserID, &expression.Expression, &expression.Status, &expression.Result); err != nil {
+		if err := rows.Scan(&expression.ID, &expression.UserID, &expression.Expression, &expression.Status, &expression.Result,
+			&expression.Precision, &expression.Scale, &expression.DecimalResult, &expression.Variables, &expression.Policy, &expression.Error,
+			&expression.PercentComplete); err != nil {
 			if r.db.IsDatabaseUnavailableErr(err) {
 				return nil, ErrDatabaseNotAvailable
 			}
@@ -164,14 +241,27 @@ func (r *repository) GetAllExpressions(ctx context.Context, userID uuid.UUID) ([
 		}
 		return nil, fmt.Errorf("rows iteration error: %v", err)
 	}
+
+	if err := r.loadValues(ctx, expressions, `
+		SELECT v.expression_id, v.name, v.value, COALESCE(v.decimal_value, '')
+		FROM expression_values v
+		JOIN expressions e ON e.id = v.expression_id
+		WHERE e.user_id = $1
+		ORDER BY v.expression_id, v.position`, userID); err != nil {
+		return nil, err
+	}
 	return expressions, nil
 }
 
 func (r *repository) GetExpressionByID(ctx context.Context, expressionID uuid.UUID) (*models.Expression, error) {
 	var expression models.Expression
 	row := r.db.QueryRow(ctx,
-		"SELECT id, user_id, expression, status, result FROM expressions WHERE id = $1", expressionID)
-	if err := row.Scan(&expression.ID, &expression.UserID, &expression.Expression, &expression.Status, &expression.Result); err != nil {
+		`SELECT id, user_id, expression, status, result, precision, scale, COALESCE(decimal_result, ''), variables, numeric_policy,
+		        COALESCE(error_message, ''), `+percentCompleteColumn+`
+		FROM expressions e WHERE id = $1`, expressionID)
+	if err := row.Scan(&expression.ID, &expression.UserID, &expression.Expression, &expression.Status, &expression.Result,
+		&expression.Precision, &expression.Scale, &expression.DecimalResult, &expression.Variables, &expression.Policy, &expression.Error,
+		&expression.PercentComplete); err != nil {
 		if r.db.IsNoRowsErr(err) {
 			return nil, ErrUnknownExpressionID
 		}
@@ -180,32 +270,181 @@ func (r *repository) GetExpressionByID(ctx context.Context, expressionID uuid.UU
 		}
 		return &models.Expression{}, fmt.Errorf("failed to execute query: %v", err)
 	}
+
+	if err := r.loadValues(ctx, []*models.Expression{&expression}, `
+		SELECT expression_id, name, value, COALESCE(decimal_value, '')
+		FROM expression_values
+		WHERE expression_id = $1
+		ORDER BY position`, expressionID); err != nil {
+		return nil, err
+	}
 	return &expression, nil
 }
 
-func (r *repository) GetTask(ctx context.Context, getEndTime func(string) *timestamppb.Timestamp) (*pb.Task, error) {
+const percentCompleteColumn = `(
+	SELECT COALESCE(ROUND(100.0 * COUNT(*) FILTER (WHERE t.status IN ('done', 'skipped')) / NULLIF(COUNT(*), 0), 2), 100)::float8
+	FROM tasks t WHERE t.expression_id = e.id)`
+
+func (r *repository) loadValues(ctx context.Context, expressions []*models.Expression, query string, args ...any) error {
+	byID := make(map[uuid.UUID]*models.Expression, len(expressions))
+	for _, expression := range expressions {
+		byID[expression.ID] = expression
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to query expression values: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			expressionID uuid.UUID
+			value        models.NamedValue
+			number       *float64
+		)
+		if err := rows.Scan(&expressionID, &value.Name, &number, &value.Decimal); err != nil {
+			if r.db.IsDatabaseUnavailableErr(err) {
+				return ErrDatabaseNotAvailable
+			}
+			return fmt.Errorf("failed to scan expression value: %v", err)
+		}
+		value.Value = (*models.Number)(number)
+		if expression, ok := byID[expressionID]; ok {
+			expression.Values = append(expression.Values, &value)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("rows iteration error: %v", err)
+	}
+	return nil
+}
+
+func (r *repository) GetExpressionTasks(ctx context.Context, expressionID uuid.UUID) ([]*models.TaskRecord, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, operator, status, final_task, branch_of, COALESCE(agent, ''), started_at, finished_at,
+		       result, COALESCE(decimal_result, ''), COALESCE(error_message, '')
+		FROM tasks
+		WHERE expression_id = $1
+		ORDER BY position`, expressionID)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("failed to select tasks: %v", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]*models.TaskRecord, 0)
+	byID := make(map[uuid.UUID]*models.TaskRecord)
+	for rows.Next() {
+		var (
+			task   models.TaskRecord
+			result *float64
+		)
+		if err := rows.Scan(&task.ID, &task.Operator, &task.Status, &task.FinalTask, &task.BranchOf, &task.Agent,
+			&task.StartedAt, &task.FinishedAt, &result, &task.DecimalResult, &task.Error); err != nil {
+			if r.db.IsDatabaseUnavailableErr(err) {
+				return nil, ErrDatabaseNotAvailable
+			}
+			return nil, fmt.Errorf("failed to scan task: %v", err)
+		}
+		if task.Status == models.Done {
+			task.Result = (*models.Number)(result)
+		}
+		task.Args = make([]models.TaskArgument, 0)
+		tasks = append(tasks, &task)
+		byID[task.ID] = &task
+	}
+	if err := rows.Err(); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("rows iteration error: %v", err)
+	}
+
+	argRows, err := r.db.Query(ctx, `
+		SELECT a.task_id, a.value, COALESCE(a.decimal_value, ''), a.source_task_id, a.origin_task_id
+		FROM task_arguments a
+		JOIN tasks t ON t.id = a.task_id
+		WHERE t.expression_id = $1
+		ORDER BY a.task_id, a.position`, expressionID)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("failed to select task arguments: %v", err)
+	}
+	defer argRows.Close()
+
+	for argRows.Next() {
+		var (
+			taskID         uuid.UUID
+			arg            models.TaskArgument
+			value          *float64
+			source, origin *uuid.UUID
+		)
+		if err := argRows.Scan(&taskID, &value, &arg.Decimal, &source, &origin); err != nil {
+			if r.db.IsDatabaseUnavailableErr(err) {
+				return nil, ErrDatabaseNotAvailable
+			}
+			return nil, fmt.Errorf("failed to scan task argument: %v", err)
+		}
+		arg.TaskID = origin
+		if source == nil {
+			arg.Value = (*models.Number)(value)
+		} else {
+			arg.Decimal = ""
+		}
+		if task, ok := byID[taskID]; ok {
+			task.Args = append(task.Args, arg)
+		}
+	}
+	if err := argRows.Err(); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("rows iteration error: %v", err)
+	}
+	return tasks, nil
+}
+
+func (r *repository) GetTask(ctx context.Context, agent string, lease time.Duration, getEndTime func(string) *timestamppb.Timestamp) (*pb.Task, error) {
 	var resultTask *pb.Task
 	err := r.WithTransaction(ctx, func(ctx context.Context, tx postgres.Tx) error {
 		query := `
-			SELECT id, expression_id, arg1_value, arg2_value, operator, final_task
-			FROM tasks
-			WHERE status = 'pending'
-				AND arg1_task_id IS NULL
-				AND arg2_task_id IS NULL
-			ORDER BY created_at
-			FOR UPDATE SKIP LOCKED
+			SELECT t.id, t.expression_id, t.operator, t.final_task, e.precision, e.scale, e.numeric_policy
+			FROM tasks t
+			JOIN expressions e ON e.id = t.expression_id
+			WHERE t.status = 'pending'
+				AND t.operator != $1
+				AND t.branch_of IS NULL
+				AND NOT EXISTS (
+					SELECT 1 FROM task_arguments a
+					WHERE a.task_id = t.id AND a.source_task_id IS NOT NULL
+				)
+			ORDER BY t.created_at
+			FOR UPDATE OF t SKIP LOCKED
 			LIMIT 1
 		`
 
 		var (
-			id, expressionID     uuid.UUID
-			arg1Value, arg2Value sql.NullFloat64
-			operator             string
-			finalTask            bool
+			id, expressionID uuid.UUID
+			operator         string
+			finalTask        bool
+			precision        string
+			scale            int32
+			policy           string
 		)
 
-		if err := tx.QueryRow(ctx, query).Scan(
-			&id, &expressionID, &arg1Value, &arg2Value, &operator, &finalTask,
+		if err := tx.QueryRow(ctx, query, models.ConditionalOperator).Scan(
+			&id, &expressionID, &operator, &finalTask, &precision, &scale, &policy,
 		); err != nil {
 			if r.db.IsNoRowsErr(err) {
 				return nil
@@ -216,6 +455,14 @@ func (r *repository) GetTask(ctx context.Context, getEndTime func(string) *times
 			return fmt.Errorf("failed to select task: %w", err)
 		}
 
+		args, decimalArgs, err := r.getTaskArguments(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if precision != string(models.PrecisionDecimal) {
+			decimalArgs = nil
+		}
+
 		if _, err := tx.Exec(ctx, `
 			UPDATE expressions
 			SET status = 'in progress'
@@ -232,9 +479,13 @@ func (r *repository) GetTask(ctx context.Context, getEndTime func(string) *times
 		}
 
 		endTime := endTimeProto.AsTime()
+		token := uuid.New()
 		if _, err := tx.Exec(ctx,
-			`UPDATE tasks SET status = $1, operation_time = $2 WHERE id = $3`,
-			models.InProgress, endTime, id,
+			`UPDATE tasks
+			SET status = $1, operation_time = $2, agent = $3, started_at = now(),
+			    lease_expires_at = now() + $5::interval, assignment_token = $6
+			WHERE id = $4`,
+			models.InProgress, endTime, agent, id, lease, token,
 		); err != nil {
 			if r.db.IsDatabaseUnavailableErr(err) {
 				return ErrDatabaseNotAvailable
@@ -243,13 +494,17 @@ func (r *repository) GetTask(ctx context.Context, getEndTime func(string) *times
 		}
 
 		resultTask = &pb.Task{
-			Id:            id.String(),
-			ExpressionId:  expressionID.String(),
-			Arg1Num:       arg1Value.Float64,
-			Arg2Num:       arg2Value.Float64,
-			Operator:      operator,
-			OperationTime: endTimeProto,
-			FinalTask:     finalTask,
+			Id:              id.String(),
+			ExpressionId:    expressionID.String(),
+			Args:            args,
+			Operator:        operator,
+			OperationTime:   endTimeProto,
+			FinalTask:       finalTask,
+			Precision:       precision,
+			Scale:           scale,
+			DecimalArgs:     decimalArgs,
+			Policy:          policy,
+			AssignmentToken: token.String(),
 		}
 		return nil
 	})
@@ -260,73 +515,356 @@ func (r *repository) GetTask(ctx context.Context, getEndTime func(string) *times
 	return resultTask, nil
 }
 
-func (r *repository) SetTaskResult(ctx context.Context, task *pb.Task, result float64) error {
+func (r *repository) RenewLeases(ctx context.Context, agent string, taskIDs []uuid.UUID, lease time.Duration) ([]uuid.UUID, error) {
+	var renewed []uuid.UUID
+	err := r.WithTransaction(ctx, func(ctx context.Context, tx postgres.Tx) error {
+		ids, err := r.queryTaskIDs(ctx, tx, `
+			UPDATE tasks
+			SET lease_expires_at = now() + $3::interval
+			WHERE id = ANY($2)
+			  AND agent = $1
+			  AND status = $4
+			  AND lease_expires_at > now()
+			RETURNING id
+		`, agent, taskIDs, lease, models.InProgress)
+		renewed = ids
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return renewed, nil
+}
+
+func (r *repository) SetTaskResult(ctx context.Context, taskID, token uuid.UUID, result models.TaskResult, normalize NormalizeResultFunc) error {
 	return r.WithTransaction(ctx, func(ctx context.Context, tx postgres.Tx) error {
-		if task.FinalTask {
-			if res, err := tx.Exec(ctx, `DELETE FROM tasks WHERE id = $1`, task.Id); err != nil {
-				if r.db.IsDatabaseUnavailableErr(err) {
-					return ErrDatabaseNotAvailable
-				}
-				if res.RowsAffected() == 0 {
-					return ErrUnknownTaskID
-				}
-				return err
+		var (
+			expressionID      uuid.UUID
+			final             bool
+			status            models.Status
+			assigned, leased  bool
+			precision, policy string
+		)
+		if err := tx.QueryRow(ctx, `
+			SELECT t.expression_id, t.final_task, t.status,
+			       COALESCE(t.assignment_token = $2, false),
+			       COALESCE(t.lease_expires_at > now(), false),
+			       e.precision, e.numeric_policy
+			FROM tasks t
+			JOIN expressions e ON e.id = t.expression_id
+			WHERE t.id = $1
+			FOR UPDATE OF t
+		`, taskID, token).Scan(&expressionID, &final, &status, &assigned, &leased, &precision, &policy); err != nil {
+			if r.db.IsNoRowsErr(err) {
+				return ErrUnknownTaskID
 			}
-
-			if res, err := tx.Exec(ctx, `
-			UPDATE expressions
-			SET result = $1, status = $2
-			WHERE id = $3
-		`, result, models.Done, task.ExpressionId); err != nil {
-				if r.db.IsDatabaseUnavailableErr(err) {
-					return ErrDatabaseNotAvailable
-				}
-				if res.RowsAffected() == 0 {
-					return ErrUnknownExpressionID
-				}
-				return err
+			if r.db.IsDatabaseUnavailableErr(err) {
+				return ErrDatabaseNotAvailable
 			}
-		} else {
-			if res, err := tx.Exec(ctx, `
-			UPDATE tasks
-			SET arg1_value = CASE WHEN arg1_task_id = $1 THEN $2 ELSE arg1_value END,
-			    arg1_task_id = CASE WHEN arg1_task_id = $1 THEN NULL ELSE arg1_task_id END,
-			    arg2_value = CASE WHEN arg2_task_id = $1 THEN $2 ELSE arg2_value END,
-			    arg2_task_id = CASE WHEN arg2_task_id = $1 THEN NULL ELSE arg2_task_id END
-			WHERE arg1_task_id = $1 OR arg2_task_id = $1
-		`, task.Id, result); err != nil {
-				if r.db.IsDatabaseUnavailableErr(err) {
-					return ErrDatabaseNotAvailable
-				}
-				if res.RowsAffected() == 0 {
-					return ErrUnknownIDTasksWithDependency
-				}
-				return err
+			return fmt.Errorf("failed to select task: %w", err)
+		}
+
+		if !assigned {
+			return ErrLeaseNotHeld
+		}
+		switch status {
+		case models.Done, models.Failed:
+			return nil
+		case models.InProgress:
+			if !leased {
+				return ErrLeaseNotHeld
 			}
+		default:
+			return ErrUnknownTaskID
+		}
+
+		result = normalize(models.Precision(precision), models.NumericPolicy(policy), result)
+		if result.Error != "" {
+			return r.failExpression(ctx, tx, taskID.String(), expressionID.String(), result.Error)
+		}
+		return r.completeTask(ctx, tx, taskID.String(), expressionID.String(), final, result)
+	})
+}
+
+func (r *repository) failExpression(ctx context.Context, tx postgres.Tx, taskID, expressionID, message string) error {
+	res, err := tx.Exec(ctx, `
+		UPDATE tasks
+		SET status = $2, error_message = $3, finished_at = now()
+		WHERE id = $1 AND status IN ($4, $5)
+	`, taskID, models.Failed, message, models.Pending, models.InProgress)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrUnknownTaskID
+	}
 
-			if res, err := tx.Exec(ctx, `DELETE FROM tasks WHERE id = $1`, task.Id); err != nil {
-				if r.db.IsDatabaseUnavailableErr(err) {
-					return ErrDatabaseNotAvailable
-				}
-				if res.RowsAffected() == 0 {
-					return ErrUnknownTaskID
-				}
-				return err
+	if _, err := tx.Exec(ctx, `
+		UPDATE expressions
+		SET status = $1, error_message = $2
+		WHERE id = $3
+	`, models.Failed, message, expressionID); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE tasks
+		SET status = $2
+		WHERE expression_id = $1 AND status IN ($3, $4)
+	`, expressionID, models.Cancelled, models.Pending, models.InProgress); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *repository) completeTask(ctx context.Context, tx postgres.Tx, taskID, expressionID string, final bool, result models.TaskResult) error {
+	consumers, err := r.queryTaskIDs(ctx, tx, `
+		UPDATE task_arguments
+		SET value = $2,
+		    decimal_value = NULLIF($3, ''),
+		    source_task_id = NULL
+		WHERE source_task_id = $1
+		RETURNING task_id
+	`, taskID, result.Value, result.Decimal)
+	if err != nil {
+		return err
+	}
+
+	values, err := tx.Exec(ctx, `
+		UPDATE expression_values
+		SET value = $2,
+		    decimal_value = NULLIF($3, ''),
+		    source_task_id = NULL
+		WHERE source_task_id = $1
+	`, taskID, result.Value, result.Decimal)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to update expression values: %w", err)
+	}
+	if !final && len(consumers) == 0 && values.RowsAffected() == 0 {
+		return ErrUnknownIDTasksWithDependency
+	}
+
+	res, err := tx.Exec(ctx, `
+		UPDATE tasks
+		SET status = $2, result = $3, decimal_result = NULLIF($4, ''), finished_at = now()
+		WHERE id = $1 AND status IN ($5, $6)
+	`, taskID, models.Done, result.Value, result.Decimal, models.Pending, models.InProgress)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrUnknownTaskID
+	}
+
+	if final {
+		if _, err := tx.Exec(ctx, `
+			UPDATE expressions
+			SET result = $1, decimal_result = NULLIF($3, '')
+			WHERE id = $2
+		`, result.Value, expressionID, result.Decimal); err != nil {
+			if r.db.IsDatabaseUnavailableErr(err) {
+				return ErrDatabaseNotAvailable
 			}
+			return err
+		}
+	}
+
+	for _, consumer := range consumers {
+		if err := r.resolveConditional(ctx, tx, consumer); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE expressions
+		SET status = $2
+		WHERE id = $1
+		  AND status != $3
+		  AND NOT EXISTS (SELECT 1 FROM tasks WHERE expression_id = $1 AND status IN ($4, $5))
+	`, expressionID, models.Done, models.Failed, models.Pending, models.InProgress); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to update expression status: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) resolveConditional(ctx context.Context, tx postgres.Tx, taskID uuid.UUID) error {
+	var (
+		expressionID uuid.UUID
+		operator     string
+		final        bool
+		branchOf     *uuid.UUID
+		taskStatus   models.Status
+	)
+	if err := tx.QueryRow(ctx,
+		`SELECT expression_id, operator, final_task, branch_of, status FROM tasks WHERE id = $1`, taskID,
+	).Scan(&expressionID, &operator, &final, &branchOf, &taskStatus); err != nil {
+		if r.db.IsNoRowsErr(err) {
+			return nil
+		}
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
 		}
+		return fmt.Errorf("failed to select task: %w", err)
+	}
+	if operator != models.ConditionalOperator || branchOf != nil || taskStatus != models.Pending {
 		return nil
-	})
+	}
+
+	args, err := r.getConditionalArguments(ctx, tx, taskID)
+	if err != nil {
+		return err
+	}
+	if len(args) != 3 {
+		return ErrInvalidTask
+	}
+	if args[0].source != nil {
+		return nil
+	}
+
+	then := args[0].value != 0
+	chosen := args[2]
+	if then {
+		chosen = args[1]
+	}
+
+	activated, err := r.queryTaskIDs(ctx, tx, `
+		UPDATE tasks
+		SET branch_of = NULL
+		WHERE branch_of = $1 AND then_branch = $2
+		RETURNING id
+	`, taskID, then)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		WITH RECURSIVE discarded AS (
+			SELECT id FROM tasks WHERE branch_of = $1
+			UNION
+			SELECT t.id FROM tasks t JOIN discarded d ON t.branch_of = d.id
+		)
+		UPDATE tasks
+		SET status = $2
+		WHERE id IN (SELECT id FROM discarded)
+	`, taskID, models.Skipped); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to discard branch: %w", err)
+	}
+	for _, id := range activated {
+		if err := r.resolveConditional(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	if chosen.source != nil {
+		return nil
+	}
+	return r.completeTask(ctx, tx, taskID.String(), expressionID.String(), final,
+		models.TaskResult{Value: chosen.value, Decimal: chosen.decimal})
+}
+
+type conditionalArgument struct {
+	value   float64
+	decimal string
+	source  *uuid.UUID
 }
 
-func (r *repository) ResetExpiredTasks(ctx context.Context, delay time.Duration) error {
+func (r *repository) getConditionalArguments(ctx context.Context, tx postgres.Tx, taskID uuid.UUID) ([]conditionalArgument, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT a.value, COALESCE(a.decimal_value, ''), a.source_task_id
+		FROM task_arguments a
+		WHERE a.task_id = $1
+		ORDER BY a.position`, taskID)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("failed to select task arguments: %w", err)
+	}
+	defer rows.Close()
+
+	var args []conditionalArgument
+	for rows.Next() {
+		var (
+			arg   conditionalArgument
+			value sql.NullFloat64
+		)
+		if err := rows.Scan(&value, &arg.decimal, &arg.source); err != nil {
+			return nil, fmt.Errorf("failed to scan task argument: %w", err)
+		}
+		arg.value = value.Float64
+		args = append(args, arg)
+	}
+	if err := rows.Err(); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("rows iteration error: %v", err)
+	}
+	return args, nil
+}
+
+func (r *repository) queryTaskIDs(ctx context.Context, tx postgres.Tx, query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan task id: %w", err)
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *repository) ResetExpiredTasks(ctx context.Context) error {
 	query := `
         UPDATE tasks
-        SET status = 'pending',
-            result = NULL
-        WHERE status != 'pending'
-          AND operation_time + $1 < now()
+        SET status = $1,
+            result = NULL,
+            agent = NULL,
+            started_at = NULL,
+            lease_expires_at = NULL,
+            assignment_token = NULL
+        WHERE status = $2
+          AND lease_expires_at < now()
     `
-	_, err := r.db.Exec(ctx, query, delay)
+	_, err := r.db.Exec(ctx, query, models.Pending, models.InProgress)
 	if err != nil {
 		return fmt.Errorf("failed to reset expired tasks: %w", err)
 	}
@@ -334,7 +872,212 @@ func (r *repository) ResetExpiredTasks(ctx context.Context, delay time.Duration)
 	return nil
 }
 
-func (r *repository) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx postgres.Tx) error) error {
+func (r *repository) ReleaseTask(ctx context.Context, taskID, token uuid.UUID) error {
+	query := `
+        UPDATE tasks
+        SET status = $1,
+            agent = NULL,
+            started_at = NULL,
+            lease_expires_at = NULL,
+            assignment_token = NULL
+        WHERE id = $2
+          AND assignment_token = $3
+          AND status = $4
+    `
+	_, err := r.db.Exec(ctx, query, models.Pending, taskID, token, models.InProgress)
+	if err != nil {
+		return fmt.Errorf("failed to release task: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) CreateVariable(ctx context.Context, variable *models.Variable) error {
+	if _, err := r.db.Exec(ctx,
+		"INSERT INTO variables (user_id, name, value, decimal_value) VALUES ($1, $2, $3, $4)",
+		variable.UserID, variable.Name, variable.Value, variable.Decimal); err != nil {
+		if r.db.IsUniqueViolationErr(err) {
+			return ErrVariableAlreadyExists
+		}
+		if r.db.IsForeignKeyErr(err) {
+			return ErrUnknownUserID
+		}
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to insert variable: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) UpdateVariable(ctx context.Context, variable *models.Variable) error {
+	res, err := r.db.Exec(ctx,
+		"UPDATE variables SET value = $3, decimal_value = $4 WHERE user_id = $1 AND name = $2",
+		variable.UserID, variable.Name, variable.Value, variable.Decimal)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to update variable: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrUnknownVariable
+	}
+	return nil
+}
+
+func (r *repository) GetVariables(ctx context.Context, userID uuid.UUID) ([]*models.Variable, error) {
+	rows, err := r.db.Query(ctx,
+		"SELECT user_id, name, value, decimal_value FROM variables WHERE user_id = $1 ORDER BY name", userID)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("failed to select variables: %w", err)
+	}
+	defer rows.Close()
+
+	variables := make([]*models.Variable, 0)
+	for rows.Next() {
+		var variable models.Variable
+		if err := rows.Scan(&variable.UserID, &variable.Name, &variable.Value, &variable.Decimal); err != nil {
+			return nil, fmt.Errorf("failed to scan variable: %w", err)
+		}
+		variables = append(variables, &variable)
+	}
+	if err := rows.Err(); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("rows iteration error: %v", err)
+	}
+	return variables, nil
+}
+
+func (r *repository) GetVariable(ctx context.Context, userID uuid.UUID, name string) (*models.Variable, error) {
+	var variable models.Variable
+	row := r.db.QueryRow(ctx,
+		"SELECT user_id, name, value, decimal_value FROM variables WHERE user_id = $1 AND name = $2", userID, name)
+	if err := row.Scan(&variable.UserID, &variable.Name, &variable.Value, &variable.Decimal); err != nil {
+		if r.db.IsNoRowsErr(err) {
+			return nil, ErrUnknownVariable
+		}
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("failed to select variable: %w", err)
+	}
+	return &variable, nil
+}
+
+func (r *repository) DeleteVariable(ctx context.Context, userID uuid.UUID, name string) error {
+	res, err := r.db.Exec(ctx, "DELETE FROM variables WHERE user_id = $1 AND name = $2", userID, name)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to delete variable: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrUnknownVariable
+	}
+	return nil
+}
+
+func (r *repository) CreateUserFunction(ctx context.Context, function *models.UserFunction) error {
+	if _, err := r.db.Exec(ctx,
+		"INSERT INTO user_functions (user_id, name, params, body) VALUES ($1, $2, $3, $4)",
+		function.UserID, function.Name, function.Params, function.Body); err != nil {
+		if r.db.IsUniqueViolationErr(err) {
+			return ErrUserFunctionAlreadyExists
+		}
+		if r.db.IsForeignKeyErr(err) {
+			return ErrUnknownUserID
+		}
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to insert function: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) GetUserFunctions(ctx context.Context, userID uuid.UUID) ([]*models.UserFunction, error) {
+	rows, err := r.db.Query(ctx,
+		"SELECT user_id, name, params, body FROM user_functions WHERE user_id = $1 ORDER BY name", userID)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("failed to select functions: %w", err)
+	}
+	defer rows.Close()
+
+	functions := make([]*models.UserFunction, 0)
+	for rows.Next() {
+		var function models.UserFunction
+		if err := rows.Scan(&function.UserID, &function.Name, &function.Params, &function.Body); err != nil {
+			return nil, fmt.Errorf("failed to scan function: %w", err)
+		}
+		functions = append(functions, &function)
+	}
+	if err := rows.Err(); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("rows iteration error: %v", err)
+	}
+	return functions, nil
+}
+
+func (r *repository) DeleteUserFunction(ctx context.Context, userID uuid.UUID, name string) error {
+	res, err := r.db.Exec(ctx, "DELETE FROM user_functions WHERE user_id = $1 AND name = $2", userID, name)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to delete function: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrUnknownUserFunction
+	}
+	return nil
+}
+
+func (r *repository) getTaskArguments(ctx context.Context, tx postgres.Tx, taskID uuid.UUID) ([]float64, []string, error) {
+	rows, err := tx.Query(ctx,
+		"SELECT value, COALESCE(decimal_value, '') FROM task_arguments WHERE task_id = $1 ORDER BY position", taskID)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, nil, ErrDatabaseNotAvailable
+		}
+		return nil, nil, fmt.Errorf("failed to select task arguments: %w", err)
+	}
+	defer rows.Close()
+
+	args := make([]float64, 0)
+	decimalArgs := make([]string, 0)
+	for rows.Next() {
+		var (
+			value        sql.NullFloat64
+			decimalValue string
+		)
+		if err := rows.Scan(&value, &decimalValue); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan task argument: %w", err)
+		}
+		args = append(args, value.Float64)
+		decimalArgs = append(decimalArgs, decimalValue)
+	}
+	if err := rows.Err(); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, nil, ErrDatabaseNotAvailable
+		}
+		return nil, nil, fmt.Errorf("rows iteration error: %v", err)
+	}
+	return args, decimalArgs, nil
+}
+
+func (r *repository) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx postgres.Tx) error) (err error) {
 	tx, err := r.db.BeginTx(ctx)
 	if err != nil {
 		if r.db.IsDatabaseUnavailableErr(err) {
@@ -354,7 +1097,8 @@ func (r *repository) WithTransaction(ctx context.Context, fn func(ctx context.Co
 		}
 	}()
 
-	return fn(ctx, tx)
+	err = fn(ctx, tx)
+	return err
 }
 
 func HashPassword(password string) (string, error) {
@@ -368,3 +1112,79 @@ func comparePasswords(hashedPassword, password string) error {
 	}
 	return nil
 }
+
+func (r *repository) RegisterAgent(ctx context.Context, agent *models.Agent) error {
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO agents (id, hostname, version, capacity, operators)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE
+		SET hostname = EXCLUDED.hostname,
+		    version = EXCLUDED.version,
+		    capacity = EXCLUDED.capacity,
+		    operators = EXCLUDED.operators,
+		    registered_at = CURRENT_TIMESTAMP,
+		    last_seen = CURRENT_TIMESTAMP
+	`, agent.ID, agent.Hostname, agent.Version, agent.Capacity, agent.Operators); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to register agent: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) TouchAgent(ctx context.Context, id string) error {
+	res, err := r.db.Exec(ctx, "UPDATE agents SET last_seen = CURRENT_TIMESTAMP WHERE id = $1", id)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return ErrDatabaseNotAvailable
+		}
+		return fmt.Errorf("failed to update agent: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrUnknownAgent
+	}
+	return nil
+}
+
+func (r *repository) GetLiveAgents(ctx context.Context, window time.Duration) ([]*models.Agent, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.hostname, a.version, a.capacity, a.operators, a.registered_at, a.last_seen,
+		       ARRAY(
+		           SELECT t.id FROM tasks t
+		           WHERE t.agent = a.id AND t.status = $2
+		           ORDER BY t.started_at
+		       ),
+		       (
+		           SELECT count(*) FROM tasks t
+		           WHERE t.agent = a.id AND t.finished_at > now() - interval '1 minute'
+		       )
+		FROM agents a
+		WHERE a.last_seen > now() - $1::interval
+		ORDER BY a.id
+	`, window, models.InProgress)
+	if err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("failed to select agents: %w", err)
+	}
+	defer rows.Close()
+
+	agents := make([]*models.Agent, 0)
+	for rows.Next() {
+		var agent models.Agent
+		if err := rows.Scan(&agent.ID, &agent.Hostname, &agent.Version, &agent.Capacity, &agent.Operators,
+			&agent.RegisteredAt, &agent.LastSeen, &agent.InFlightTasks, &agent.TasksPerMinute); err != nil {
+			return nil, fmt.Errorf("failed to scan agent: %w", err)
+		}
+		agents = append(agents, &agent)
+	}
+	if err := rows.Err(); err != nil {
+		if r.db.IsDatabaseUnavailableErr(err) {
+			return nil, ErrDatabaseNotAvailable
+		}
+		return nil, fmt.Errorf("rows iteration error: %v", err)
+	}
+	return agents, nil
+}
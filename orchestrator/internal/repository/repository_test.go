@@ -0,0 +1,77 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/database/postgres"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/repository"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRepository_WithTransaction(t *testing.T) {
+	errFn := errors.New("fn failed")
+	errCommit := errors.New("commit failed")
+
+	tests := []struct {
+		name        string
+		fnErr       error
+		mockSetup   func(db *mocks.MockDatabaseConnection, tx *mocks.MockTx)
+		expectedErr error
+	}{
+		{
+			name: "commit on success",
+			mockSetup: func(db *mocks.MockDatabaseConnection, tx *mocks.MockTx) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				tx.EXPECT().Commit(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:  "rollback when fn fails",
+			fnErr: errFn,
+			mockSetup: func(db *mocks.MockDatabaseConnection, tx *mocks.MockTx) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				tx.EXPECT().Rollback(gomock.Any()).Return(nil)
+			},
+			expectedErr: errFn,
+		},
+		{
+			name: "commit error is returned",
+			mockSetup: func(db *mocks.MockDatabaseConnection, tx *mocks.MockTx) {
+				db.EXPECT().BeginTx(gomock.Any()).Return(tx, nil)
+				tx.EXPECT().Commit(gomock.Any()).Return(errCommit)
+			},
+			expectedErr: errCommit,
+		},
+		{
+			name: "database unavailable",
+			mockSetup: func(db *mocks.MockDatabaseConnection, tx *mocks.MockTx) {
+				err := errors.New("connection refused")
+				db.EXPECT().BeginTx(gomock.Any()).Return(nil, err)
+				db.EXPECT().IsDatabaseUnavailableErr(err).Return(true)
+			},
+			expectedErr: repository.ErrDatabaseNotAvailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			db := mocks.NewMockDatabaseConnection(ctrl)
+			tx := mocks.NewMockTx(ctrl)
+			tt.mockSetup(db, tx)
+
+			repo := repository.NewRepositoryImpl(db)
+			err := repo.WithTransaction(context.Background(), func(context.Context, postgres.Tx) error {
+				return tt.fnErr
+			})
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
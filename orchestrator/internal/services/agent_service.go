@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/repository"
+)
+
+type AgentService interface {
+	RegisterAgent(ctx context.Context, agent *models.Agent) error
+	TouchAgent(ctx context.Context, id string) error
+	GetLiveAgents(ctx context.Context) ([]*models.Agent, error)
+}
+
+type agentService struct {
+	repo   repository.Repository
+	window time.Duration
+}
+
+// NewAgentService returns a service that treats an agent as live while it has
+// been seen within window.
+func NewAgentService(repo repository.Repository, window time.Duration) AgentService {
+	return &agentService{
+		repo:   repo,
+		window: window,
+	}
+}
+
+func (s *agentService) RegisterAgent(ctx context.Context, agent *models.Agent) error {
+	if agent.ID == "" {
+		return ErrInvalidAgentID
+	}
+	if agent.Capacity <= 0 {
+		return ErrInvalidAgentCapacity
+	}
+	operators := append(make([]string, 0, len(agent.Operators)), agent.Operators...)
+	slices.Sort(operators)
+	registered := *agent
+	registered.Operators = slices.Compact(operators)
+	if err := s.repo.RegisterAgent(ctx, &registered); err != nil {
+		return mapAgentError(err)
+	}
+	return nil
+}
+
+func (s *agentService) TouchAgent(ctx context.Context, id string) error {
+	if err := s.repo.TouchAgent(ctx, id); err != nil {
+		return mapAgentError(err)
+	}
+	return nil
+}
+
+func (s *agentService) GetLiveAgents(ctx context.Context) ([]*models.Agent, error) {
+	agents, err := s.repo.GetLiveAgents(ctx, s.window)
+	if err != nil {
+		return nil, mapAgentError(err)
+	}
+	return agents, nil
+}
+
+func mapAgentError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrUnknownAgent):
+		return ErrUnknownAgent
+	case errors.Is(err, repository.ErrDatabaseNotAvailable):
+		return ErrDatabaseUnavailable
+	}
+	return err
+}
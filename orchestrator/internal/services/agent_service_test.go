@@ -0,0 +1,120 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/repository"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/services"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAgentService_RegisterAgent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewAgentService(mockRepo, time.Minute)
+
+	tests := []struct {
+		name        string
+		agent       *models.Agent
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name:        "missing id",
+			agent:       &models.Agent{Capacity: 1},
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidAgentID,
+		},
+		{
+			name:        "invalid capacity",
+			agent:       &models.Agent{ID: "agent-1"},
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidAgentCapacity,
+		},
+		{
+			name:  "operators are sorted and deduplicated",
+			agent: &models.Agent{ID: "agent-1", Hostname: "host", Version: "1.0.0", Capacity: 2, Operators: []string{"-", "+", "-"}},
+			mockSetup: func() {
+				mockRepo.EXPECT().RegisterAgent(gomock.Any(), &models.Agent{
+					ID: "agent-1", Hostname: "host", Version: "1.0.0", Capacity: 2, Operators: []string{"+", "-"},
+				}).Return(nil)
+			},
+		},
+		{
+			name:  "no operators",
+			agent: &models.Agent{ID: "agent-1", Capacity: 1},
+			mockSetup: func() {
+				mockRepo.EXPECT().RegisterAgent(gomock.Any(), &models.Agent{ID: "agent-1", Capacity: 1, Operators: []string{}}).Return(nil)
+			},
+		},
+		{
+			name:  "database unavailable",
+			agent: &models.Agent{ID: "agent-1", Capacity: 1},
+			mockSetup: func() {
+				mockRepo.EXPECT().RegisterAgent(gomock.Any(), gomock.Any()).Return(repository.ErrDatabaseNotAvailable)
+			},
+			expectedErr: services.ErrDatabaseUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			err := service.RegisterAgent(context.Background(), tt.agent)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestAgentService_TouchAgent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewAgentService(mockRepo, time.Minute)
+
+	tests := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "success"},
+		{name: "unknown agent", repoErr: repository.ErrUnknownAgent, expectedErr: services.ErrUnknownAgent},
+		{name: "database unavailable", repoErr: repository.ErrDatabaseNotAvailable, expectedErr: services.ErrDatabaseUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.EXPECT().TouchAgent(gomock.Any(), "agent-1").Return(tt.repoErr)
+			err := service.TouchAgent(context.Background(), "agent-1")
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestAgentService_GetLiveAgents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewAgentService(mockRepo, 30*time.Second)
+
+	agents := []*models.Agent{{ID: "agent-1", Capacity: 2, TasksPerMinute: 5}}
+	mockRepo.EXPECT().GetLiveAgents(gomock.Any(), 30*time.Second).Return(agents, nil)
+	result, err := service.GetLiveAgents(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, agents, result)
+
+	mockRepo.EXPECT().GetLiveAgents(gomock.Any(), 30*time.Second).Return(nil, errors.New("boom"))
+	_, err = service.GetLiveAgents(context.Background())
+	assert.EqualError(t, err, "boom")
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/grpc/proto"
+)
+
+const DispatchRetryDelay = time.Second
+
+type fetchFunc func(ctx context.Context, agent string) (*pb.Task, error)
+
+type releaseFunc func(ctx context.Context, task *pb.Task) error
+
+// dispatcher hands ready tasks to waiting agent streams. The database is only
+// queried when something may have become ready and at least one agent is idle,
+// so its load follows the amount of work rather than the number of agents.
+type dispatcher struct {
+	fetch   fetchFunc
+	release releaseFunc
+	wake    chan struct{}
+	mu      sync.Mutex
+	waiters []*waiter
+}
+
+type waiter struct {
+	agent string
+	tasks chan *pb.Task
+	gone  bool
+}
+
+func newDispatcher(fetch fetchFunc, release releaseFunc) *dispatcher {
+	return &dispatcher{
+		fetch:   fetch,
+		release: release,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+func (d *dispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *dispatcher) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-d.wake:
+				d.dispatch(ctx)
+			}
+		}
+	}()
+}
+
+func (d *dispatcher) Wait(ctx context.Context, agent string) (*pb.Task, error) {
+	w := &waiter{agent: agent, tasks: make(chan *pb.Task, 1)}
+	d.mu.Lock()
+	d.waiters = append(d.waiters, w)
+	d.mu.Unlock()
+	d.Notify()
+
+	select {
+	case task := <-w.tasks:
+		return task, nil
+	case <-ctx.Done():
+		d.remove(w)
+		select {
+		case task := <-w.tasks:
+			_ = d.release(context.WithoutCancel(ctx), task)
+		default:
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (d *dispatcher) dispatch(ctx context.Context) {
+	for {
+		w := d.pop()
+		if w == nil {
+			return
+		}
+		task, err := d.fetch(ctx, w.agent)
+		if err != nil || task == nil {
+			d.pushFront(w)
+			if err != nil && ctx.Err() == nil {
+				time.AfterFunc(DispatchRetryDelay, d.Notify)
+			}
+			return
+		}
+		if !d.deliver(w, task) {
+			_ = d.release(ctx, task)
+		}
+	}
+}
+
+// deliver hands the task to the waiter unless its agent stopped waiting while
+// the task was being fetched; such a task has to be released by the caller.
+func (d *dispatcher) deliver(w *waiter, task *pb.Task) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if w.gone {
+		return false
+	}
+	w.tasks <- task
+	return true
+}
+
+func (d *dispatcher) pop() *waiter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.waiters) == 0 {
+		return nil
+	}
+	w := d.waiters[0]
+	d.waiters = d.waiters[1:]
+	return w
+}
+
+func (d *dispatcher) pushFront(w *waiter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !w.gone {
+		d.waiters = append([]*waiter{w}, d.waiters...)
+	}
+}
+
+func (d *dispatcher) remove(w *waiter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	w.gone = true
+	for i, other := range d.waiters {
+		if other == w {
+			d.waiters = append(d.waiters[:i], d.waiters[i+1:]...)
+			return
+		}
+	}
+}
@@ -1,22 +1,66 @@
 package services
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+)
 
 var (
-	ErrEmptyExpression           = errors.New("empty input")
+	ErrEmptyExpression           = expr.ErrEmptyExpression
 	ErrMissingOperator           = errors.New("must contain at least one operator")
-	ErrInvalidCharacter          = errors.New("invalid character")
-	ErrParenthesisIssue          = errors.New("mismatched or improperly placed parentheses")
-	ErrNumberFormatIssue         = errors.New("incorrect number format")
-	ErrOperatorIssue             = errors.New("consecutive or misplaced operators")
+	ErrInvalidCharacter          = expr.ErrInvalidCharacter
+	ErrParenthesisIssue          = expr.ErrParenthesisIssue
+	ErrNumberFormatIssue         = expr.ErrNumberFormatIssue
+	ErrOperatorIssue             = expr.ErrOperatorIssue
 	ErrDivisionByZero            = errors.New("division by zero is not allowed")
-	ErrInvalidExpressionStartEnd = errors.New("expression cannot start or end with an operator")
-	ErrUnaryOperatorNotSupported = errors.New("unary operators are not supported")
+	ErrIntegerOperandRequired    = errors.New("operator requires integer operands")
+	ErrInvalidExpressionStartEnd = expr.ErrInvalidExpressionStartEnd
+	ErrUnknownFunction           = errors.New("unknown function")
+	ErrFunctionCallIssue         = expr.ErrFunctionCallIssue
+	ErrFunctionArgumentCount     = errors.New("wrong number of function arguments")
+	ErrSeparatorIssue            = expr.ErrSeparatorIssue
+	ErrUnexpectedToken           = expr.ErrUnexpectedToken
+	ErrConditionalIssue          = expr.ErrConditionalIssue
+	ErrStatementIssue            = expr.ErrStatementIssue
+	ErrAssignmentIssue           = expr.ErrAssignmentIssue
+	ErrNestingTooDeep            = expr.ErrNestingTooDeep
+	ErrExpressionTooLong         = errors.New("expression is too long")
+	ErrNameReassigned            = errors.New("name is already assigned earlier in the script")
 	ErrInvalidExpression         = errors.New("invalid expression")
+	ErrDecimalUnsupported        = errors.New("operation is not supported in decimal precision mode")
+	ErrDecimalTooLarge           = errors.New("decimal value is too large")
+	ErrUnknownVariable           = errors.New("unknown variable")
+	ErrRecursiveFunction         = errors.New("function calls itself directly or indirectly")
+	ErrFunctionDepthExceeded     = errors.New("function calls are nested too deeply")
+	ErrTooManyTasks              = errors.New("expression expands to too many operations")
+	ErrNotDifferentiable         = errors.New("operation is not differentiable")
+
+	ErrUnknownPrecision = errors.New("precision must be either \"float\" or \"decimal\"")
+	ErrInvalidScale     = errors.New("scale must be between 0 and 100")
+
+	ErrUnknownNumericPolicy = errors.New("policy must be one of \"strict\", \"ieee\" or \"saturate\"")
+	ErrNonFiniteResult      = errors.New("result is not a finite number")
+
+	ErrUnknownScriptOutput = errors.New("output must be either \"last\" or \"all\"")
+	ErrInvalidBatchSize    = errors.New("batch must contain between 1 and 1000 expressions")
+
+	ErrInvalidVariableName   = errors.New("variable name must start with a letter or underscore, contain only letters, digits and underscores, be at most 64 characters long and not match a function name")
+	ErrInvalidVariableValue  = errors.New("variable value must be a finite number")
+	ErrVariableAlreadyExists = errors.New("variable already exists")
+
+	ErrInvalidFunctionName       = errors.New("function name must start with a letter or underscore, contain only letters, digits and underscores, be at most 64 characters long and not match a built-in function")
+	ErrInvalidFunctionParameters = errors.New("function parameters must be unique valid names, at most 8")
+	ErrFunctionAlreadyExists     = errors.New("function already exists")
+
+	ErrInvalidAgentID       = errors.New("agent id must not be empty")
+	ErrInvalidAgentCapacity = errors.New("agent capacity must be greater than 0")
+	ErrUnknownAgent         = errors.New("agent is not registered")
 
 	ErrUnknownUserID        = errors.New("unknown user id")
 	ErrUnknownExpressionsID = errors.New("unknown expressions id")
 	ErrUnknownTaskID        = errors.New("unknown task id")
+	ErrLeaseNotHeld         = errors.New("task lease is not held")
 	ErrForbidden            = errors.New("you do not have access to this resource")
 
 	ErrUserWithLoginAlreadyExists = errors.New("user with this login already exists")
@@ -26,3 +70,81 @@ var (
 	ErrWeakPassword               = errors.New("password must contain upper and lower case letters, a digit, a special character, and be 8-20 characters long")
 	ErrDatabaseUnavailable        = errors.New("database is unavailable")
 )
+
+var expressionErrorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrEmptyExpression, "EMPTY_EXPRESSION"},
+	{ErrMissingOperator, "MISSING_OPERATOR"},
+	{ErrInvalidCharacter, "INVALID_CHARACTER"},
+	{ErrParenthesisIssue, "PARENTHESIS_MISMATCH"},
+	{ErrNumberFormatIssue, "INVALID_NUMBER_FORMAT"},
+	{ErrOperatorIssue, "MISPLACED_OPERATOR"},
+	{ErrDivisionByZero, "DIVISION_BY_ZERO"},
+	{ErrIntegerOperandRequired, "INTEGER_OPERAND_REQUIRED"},
+	{ErrInvalidExpressionStartEnd, "OPERATOR_AT_BOUNDARY"},
+	{ErrUnknownFunction, "UNKNOWN_FUNCTION"},
+	{ErrFunctionCallIssue, "MISSING_FUNCTION_ARGUMENTS"},
+	{ErrFunctionArgumentCount, "FUNCTION_ARGUMENT_COUNT"},
+	{ErrSeparatorIssue, "MISPLACED_SEPARATOR"},
+	{ErrUnexpectedToken, "UNEXPECTED_TOKEN"},
+	{ErrConditionalIssue, "INCOMPLETE_CONDITIONAL"},
+	{ErrInvalidExpression, "INVALID_EXPRESSION"},
+	{ErrDecimalUnsupported, "UNSUPPORTED_IN_DECIMAL_MODE"},
+	{ErrDecimalTooLarge, "DECIMAL_TOO_LARGE"},
+	{ErrUnknownVariable, "UNKNOWN_VARIABLE"},
+	{ErrRecursiveFunction, "RECURSIVE_FUNCTION"},
+	{ErrFunctionDepthExceeded, "FUNCTION_DEPTH_EXCEEDED"},
+	{ErrTooManyTasks, "TOO_MANY_OPERATIONS"},
+	{ErrStatementIssue, "INVALID_STATEMENT"},
+	{ErrAssignmentIssue, "INVALID_ASSIGNMENT"},
+	{ErrNameReassigned, "NAME_REASSIGNED"},
+	{ErrNotDifferentiable, "NOT_DIFFERENTIABLE"},
+	{ErrNestingTooDeep, "NESTING_TOO_DEEP"},
+	{ErrExpressionTooLong, "EXPRESSION_TOO_LONG"},
+}
+
+type ExpressionErrorDetails struct {
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	Start    int      `json:"start"`
+	End      int      `json:"end"`
+	Token    string   `json:"token,omitempty"`
+	Expected []string `json:"expected,omitempty"`
+}
+
+func ExpressionErrorCode(err error) string {
+	for _, entry := range expressionErrorCodes {
+		if errors.Is(err, entry.err) {
+			return entry.code
+		}
+	}
+	return ""
+}
+
+func IsExpressionError(err error) bool {
+	return ExpressionErrorCode(err) != ""
+}
+
+func DescribeExpressionError(err error, expression string) *ExpressionErrorDetails {
+	for _, entry := range expressionErrorCodes {
+		if !errors.Is(err, entry.err) {
+			continue
+		}
+		details := &ExpressionErrorDetails{
+			Code:    entry.code,
+			Message: entry.err.Error(),
+			End:     len(expression),
+		}
+		var exprErr *expr.Error
+		if errors.As(err, &exprErr) {
+			details.Start = exprErr.Pos
+			details.End = exprErr.End
+			details.Token = exprErr.Token
+			details.Expected = exprErr.Expected
+		}
+		return details
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type Explanation struct {
+	Expression          string         `json:"expression"`
+	Tokens              []expr.Token   `json:"tokens"`
+	Postfix             []string       `json:"postfix"`
+	Tasks               []*PlannedTask `json:"tasks"`
+	Result              *models.Number `json:"result,omitempty"`
+	CriticalPath        []uuid.UUID    `json:"critical_path"`
+	CriticalPathLength  int            `json:"critical_path_length"`
+	EstimatedDurationMS int64          `json:"estimated_duration_ms"`
+	EstimatedCompletion time.Time      `json:"estimated_completion"`
+}
+
+type PlannedTask struct {
+	ID         uuid.UUID         `json:"id"`
+	Operator   string            `json:"operator"`
+	Args       []PlannedArgument `json:"args"`
+	DependsOn  []uuid.UUID       `json:"depends_on"`
+	BranchOf   *uuid.UUID        `json:"branch_of,omitempty"`
+	ThenBranch bool              `json:"then_branch,omitempty"`
+	Final      bool              `json:"final,omitempty"`
+	DurationMS int64             `json:"duration_ms"`
+	StartMS    int64             `json:"start_ms"`
+	FinishMS   int64             `json:"finish_ms"`
+}
+
+type PlannedArgument struct {
+	Value   *models.Number `json:"value,omitempty"`
+	Decimal string         `json:"decimal,omitempty"`
+	TaskID  *uuid.UUID     `json:"task_id,omitempty"`
+}
+
+func (s *expressionTaskService) ExplainExpression(ctx context.Context, userID uuid.UUID, expression string, options CalculationOptions) (*Explanation, error) {
+	if err := validateOptions(&options); err != nil {
+		return nil, err
+	}
+	prepared, err := s.prepareExpression(ctx, &userCatalog{userID: userID}, expression, options)
+	if err != nil {
+		return nil, err
+	}
+	tasks := prepared.tasks
+
+	explanation := &Explanation{
+		Expression:   prepared.expression.Expression,
+		Tokens:       prepared.tokens[:len(prepared.tokens)-1],
+		Postfix:      Postfix(prepared.root),
+		Tasks:        make([]*PlannedTask, len(tasks)),
+		CriticalPath: []uuid.UUID{},
+	}
+	if prepared.expression.Status == models.Done {
+		result := models.Number(prepared.expression.Result)
+		explanation.Result = &result
+	}
+
+	planned := make(map[uuid.UUID]*PlannedTask, len(tasks))
+	for i, task := range tasks {
+		plan := &PlannedTask{
+			ID:         task.ID,
+			Operator:   task.Operator,
+			Args:       make([]PlannedArgument, len(task.Args)),
+			DependsOn:  []uuid.UUID{},
+			BranchOf:   task.BranchOf,
+			ThenBranch: task.ThenBranch,
+			Final:      task.FinalTask,
+		}
+		for j, arg := range task.Args {
+			if arg.TaskID != nil {
+				plan.Args[j] = PlannedArgument{TaskID: arg.TaskID}
+				plan.DependsOn = append(plan.DependsOn, *arg.TaskID)
+				continue
+			}
+			value := models.Number(arg.Value)
+			plan.Args[j] = PlannedArgument{Value: &value, Decimal: arg.Decimal}
+		}
+		if duration, ok := s.operationDuration(task.Operator); ok {
+			plan.DurationMS = duration.Milliseconds()
+		}
+		explanation.Tasks[i] = plan
+		planned[task.ID] = plan
+	}
+	for _, plan := range explanation.Tasks {
+		if plan.BranchOf == nil {
+			continue
+		}
+		if condition := planned[*plan.BranchOf].Args[0]; condition.TaskID != nil {
+			plan.DependsOn = append(plan.DependsOn, *condition.TaskID)
+		}
+	}
+
+	previous := make(map[uuid.UUID]uuid.UUID, len(tasks))
+	scheduled := make(map[uuid.UUID]bool, len(tasks))
+	var schedule func(plan *PlannedTask)
+	schedule = func(plan *PlannedTask) {
+		if scheduled[plan.ID] {
+			return
+		}
+		scheduled[plan.ID] = true
+		for _, id := range plan.DependsOn {
+			dependency := planned[id]
+			schedule(dependency)
+			if critical, ok := previous[plan.ID]; !ok || dependency.FinishMS > planned[critical].FinishMS {
+				previous[plan.ID] = id
+				plan.StartMS = dependency.FinishMS
+			}
+		}
+		plan.FinishMS = plan.StartMS + plan.DurationMS
+	}
+
+	var last *PlannedTask
+	for _, plan := range explanation.Tasks {
+		schedule(plan)
+		if last == nil || plan.FinishMS > last.FinishMS || plan.FinishMS == last.FinishMS && plan.Final {
+			last = plan
+		}
+	}
+	for plan := last; plan != nil; plan = planned[previous[plan.ID]] {
+		explanation.CriticalPath = append([]uuid.UUID{plan.ID}, explanation.CriticalPath...)
+	}
+	explanation.CriticalPathLength = len(explanation.CriticalPath)
+	if last != nil {
+		explanation.EstimatedDurationMS = last.FinishMS
+	}
+	explanation.EstimatedCompletion = time.Now().Add(time.Duration(explanation.EstimatedDurationMS) * time.Millisecond).UTC()
+	return explanation, nil
+}
@@ -0,0 +1,108 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/services"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExpressionTaskService_ExplainExpression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewExpressionTaskService(mockRepo, &services.OperationTimesMS{
+		Addition:       100 * time.Millisecond,
+		Subtraction:    150 * time.Millisecond,
+		Multiplication: 200 * time.Millisecond,
+		Division:       250 * time.Millisecond,
+		Comparison:     50 * time.Millisecond,
+	}, services.FoldableOperators, 30*time.Second)
+	userID := uuid.New()
+
+	t.Run("plans tasks and the critical path", func(t *testing.T) {
+		explanation, err := service.ExplainExpression(context.Background(), userID, "2 + 3*4 - 10/5", services.CalculationOptions{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "2+3*4-10/5", explanation.Expression)
+		assert.Equal(t, []expr.Token{
+			{Kind: expr.Number, Text: "2", Pos: 0},
+			{Kind: expr.Operator, Text: "+", Pos: 2},
+			{Kind: expr.Number, Text: "3", Pos: 4},
+			{Kind: expr.Operator, Text: "*", Pos: 5},
+			{Kind: expr.Number, Text: "4", Pos: 6},
+			{Kind: expr.Operator, Text: "-", Pos: 8},
+			{Kind: expr.Number, Text: "10", Pos: 10},
+			{Kind: expr.Operator, Text: "/", Pos: 12},
+			{Kind: expr.Number, Text: "5", Pos: 13},
+		}, explanation.Tokens)
+		assert.Equal(t, []string{"2", "3", "4", "*", "+", "10", "5", "/", "-"}, explanation.Postfix)
+
+		require.Len(t, explanation.Tasks, 4)
+		mul, add, div, sub := explanation.Tasks[0], explanation.Tasks[1], explanation.Tasks[2], explanation.Tasks[3]
+		assert.Equal(t, []string{"*", "+", "/", "-"}, []string{mul.Operator, add.Operator, div.Operator, sub.Operator})
+		assert.Equal(t, []uuid.UUID{mul.ID}, add.DependsOn)
+		assert.Equal(t, []uuid.UUID{add.ID, div.ID}, sub.DependsOn)
+		assert.Empty(t, mul.DependsOn)
+		assert.True(t, sub.Final)
+		assert.Equal(t, int64(300), add.FinishMS)
+		assert.Equal(t, int64(300), sub.StartMS)
+
+		assert.Equal(t, []uuid.UUID{mul.ID, add.ID, sub.ID}, explanation.CriticalPath)
+		assert.Equal(t, 3, explanation.CriticalPathLength)
+		assert.Equal(t, int64(450), explanation.EstimatedDurationMS)
+		assert.WithinDuration(t, time.Now().Add(450*time.Millisecond), explanation.EstimatedCompletion, time.Second)
+		assert.Nil(t, explanation.Result)
+	})
+
+	t.Run("conditional branches wait for the condition", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).
+			Return([]*models.Variable{{UserID: userID, Name: "x", Value: 3, Decimal: "3"}}, nil)
+
+		explanation, err := service.ExplainExpression(context.Background(), userID, "x*2 > 5 ? x*x : x/2", services.CalculationOptions{})
+		require.NoError(t, err)
+
+		operators := make(map[string]*services.PlannedTask)
+		for _, task := range explanation.Tasks {
+			if _, ok := operators[task.Operator]; !ok {
+				operators[task.Operator] = task
+			}
+		}
+		assert.Contains(t, operators[">"].DependsOn, operators["*"].ID)
+		assert.Equal(t, &operators[models.ConditionalOperator].ID, operators["/"].BranchOf)
+		assert.Equal(t, []uuid.UUID{operators[">"].ID}, operators["/"].DependsOn)
+		assert.Equal(t, int64(0), operators[models.ConditionalOperator].DurationMS)
+		assert.Equal(t, int64(200+50+250), explanation.EstimatedDurationMS)
+	})
+
+	t.Run("fully folded expression", func(t *testing.T) {
+		explanation, err := service.ExplainExpression(context.Background(), userID, "2(3+4)",
+			services.CalculationOptions{Optimize: true, Lenient: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, "2*(3+4)", explanation.Expression)
+		assert.Equal(t, []string{"2", "3", "4", "+", "*"}, explanation.Postfix)
+		assert.Empty(t, explanation.Tasks)
+		assert.Empty(t, explanation.CriticalPath)
+		assert.Equal(t, int64(0), explanation.EstimatedDurationMS)
+		assert.Equal(t, models.Number(14), *explanation.Result)
+	})
+
+	t.Run("invalid expression", func(t *testing.T) {
+		_, err := service.ExplainExpression(context.Background(), userID, "2**", services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrInvalidExpressionStartEnd)
+
+		_, err = service.ExplainExpression(context.Background(), userID, "2+2", services.CalculationOptions{Precision: "exact"})
+		assert.ErrorIs(t, err, services.ErrUnknownPrecision)
+	})
+}
@@ -3,14 +3,15 @@ package services
 import (
 	"context"
 	"errors"
+	"maps"
 	"math"
-	"regexp"
+	"math/big"
+	"slices"
 	"strconv"
-	"strings"
 	"time"
-	"unicode"
 
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/database/postgres"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/repository"
 	pb "github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/grpc/proto"
@@ -20,35 +21,121 @@ import (
 )
 
 type ExpressionTaskService interface {
-	CreateExpressionTask(ctx context.Context, userID uuid.UUID, expression string) (uuid.UUID, error)
+	CreateExpressionTask(ctx context.Context, userID uuid.UUID, expression string, options CalculationOptions) (uuid.UUID, error)
+	CreateExpressionTasks(ctx context.Context, userID uuid.UUID, expressions []string, options CalculationOptions) ([]BatchItem, error)
+	CreateScriptTask(ctx context.Context, userID uuid.UUID, script string, output ScriptOutput, options CalculationOptions) (uuid.UUID, error)
+	ExplainExpression(ctx context.Context, userID uuid.UUID, expression string, options CalculationOptions) (*Explanation, error)
+	DeriveExpression(ctx context.Context, userID uuid.UUID, expression, variable string, options SymbolicOptions) (*SymbolicResult, error)
+	SimplifyExpression(ctx context.Context, userID uuid.UUID, expression string, options SymbolicOptions) (*SymbolicResult, error)
 	GetAllExpressions(ctx context.Context, userID uuid.UUID) ([]*models.Expression, error)
 	GetExpressionById(ctx context.Context, expression uuid.UUID) (*models.Expression, error)
-	GetTask(ctx context.Context) (*pb.Task, error)
-	SetTaskResult(ctx context.Context, task *pb.Task, result float64) error
-	StartExpiredTaskReset(ctx context.Context, interval, delay time.Duration)
+	GetExpressionTasks(ctx context.Context, expressionID uuid.UUID) ([]*models.TaskRecord, error)
+	GetTask(ctx context.Context, agent string) (*pb.Task, error)
+	WaitTask(ctx context.Context, agent string) (*pb.Task, error)
+	StartTaskDispatch(ctx context.Context)
+	RenewLeases(ctx context.Context, agent string, taskIDs []uuid.UUID) ([]uuid.UUID, error)
+	ReleaseTask(ctx context.Context, task *pb.Task) error
+	SetTaskResult(ctx context.Context, taskID, token uuid.UUID, result models.TaskResult) error
+	StartExpiredTaskReset(ctx context.Context, interval time.Duration)
 	GetOperationEndTime(operator string) *timestamppb.Timestamp
 }
 
+const NegationOperator = "neg"
+
+const (
+	DefaultDecimalScale     = 16
+	MaxDecimalScale         = 100
+	MaxDecimalShift         = 10000
+	MaxDecimalBits          = 1 << 16
+	MaxDecimalLiteralLength = 1000
+	MaxDecimalResultLength  = 20000
+)
+
+const (
+	MaxFunctionDepth    = 16
+	MaxExpressionTasks  = 10000
+	MaxBatchExpressions = 1000
+	MaxExpressionLength = 10000
+)
+
+type ScriptOutput string
+
+const (
+	ScriptOutputLast ScriptOutput = "last"
+	ScriptOutputAll  ScriptOutput = "all"
+)
+
+type CalculationOptions struct {
+	Precision models.Precision
+	Scale     int
+	Policy    models.NumericPolicy
+	Optimize  bool
+	Lenient   bool
+}
+
+type BuildOptions struct {
+	CalculationOptions
+	Variables     map[string]*models.Variable
+	Functions     map[string]*models.UserFunction
+	FoldOperators map[string]bool
+}
+
 type OperationTimesMS struct {
-	Addition       time.Duration
-	Subtraction    time.Duration
-	Multiplication time.Duration
-	Division       time.Duration
+	Addition        time.Duration
+	Subtraction     time.Duration
+	Multiplication  time.Duration
+	Division        time.Duration
+	Exponentiation  time.Duration
+	Function        time.Duration
+	Comparison      time.Duration
+	Modulo          time.Duration
+	IntegerDivision time.Duration
+	BitwiseAnd      time.Duration
+	BitwiseOr       time.Duration
+	BitwiseXor      time.Duration
+	ShiftLeft       time.Duration
+	ShiftRight      time.Duration
+}
+
+type BatchItem struct {
+	ID  uuid.UUID
+	Err error
+}
+
+type userCatalog struct {
+	userID    uuid.UUID
+	variables map[string]*models.Variable
+	functions map[string]*models.UserFunction
 }
 
 type expressionTaskService struct {
-	cfg  *OperationTimesMS
-	repo repository.Repository
+	cfg           *OperationTimesMS
+	repo          repository.Repository
+	foldOperators map[string]bool
+	lease         time.Duration
+	dispatcher    *dispatcher
 }
 
-func NewExpressionTaskService(repo repository.Repository, cfg *OperationTimesMS) ExpressionTaskService {
-	return &expressionTaskService{
-		repo: repo,
-		cfg:  cfg,
+func NewExpressionTaskService(repo repository.Repository, cfg *OperationTimesMS, foldOperators []string, lease time.Duration) ExpressionTaskService {
+	fold := make(map[string]bool, len(foldOperators))
+	for _, operator := range foldOperators {
+		fold[operator] = true
+	}
+	s := &expressionTaskService{
+		repo:          repo,
+		cfg:           cfg,
+		foldOperators: fold,
+		lease:         lease,
 	}
+	s.dispatcher = newDispatcher(s.GetTask, s.ReleaseTask)
+	return s
 }
 
-func (s *expressionTaskService) StartExpiredTaskReset(ctx context.Context, interval, delay time.Duration) {
+func (s *expressionTaskService) StartTaskDispatch(ctx context.Context) {
+	s.dispatcher.Start(ctx)
+}
+
+func (s *expressionTaskService) StartExpiredTaskReset(ctx context.Context, interval time.Duration) {
 	go func() {
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
@@ -58,73 +145,280 @@ func (s *expressionTaskService) StartExpiredTaskReset(ctx context.Context, inter
 				return
 			case <-ticker.C:
 				if err := s.repo.WithTransaction(ctx, func(txCtx context.Context, tx postgres.Tx) error {
-					return s.repo.ResetExpiredTasks(txCtx, delay)
+					return s.repo.ResetExpiredTasks(txCtx)
 				}); err != nil {
-					if errors.Is(err, repository.ErrDatabaseNotAvailable) {
-						continue
-					}
+					continue
 				}
+				s.dispatcher.Notify()
 			}
 		}
 	}()
 }
 
-func (s *expressionTaskService) CreateExpressionTask(ctx context.Context, userID uuid.UUID, expression string) (uuid.UUID, error) {
-	if err := ValidateExpression(expression); err != nil {
+func (s *expressionTaskService) CreateExpressionTask(ctx context.Context, userID uuid.UUID, expression string, options CalculationOptions) (uuid.UUID, error) {
+	if err := validateOptions(&options); err != nil {
 		return uuid.Nil, err
 	}
+	prepared, err := s.prepareExpression(ctx, &userCatalog{userID: userID}, expression, options)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return s.saveExpression(ctx, prepared.expression, prepared.tasks)
+}
+
+func (s *expressionTaskService) CreateExpressionTasks(ctx context.Context, userID uuid.UUID, expressions []string, options CalculationOptions) ([]BatchItem, error) {
+	if len(expressions) == 0 || len(expressions) > MaxBatchExpressions {
+		return nil, ErrInvalidBatchSize
+	}
+	if err := validateOptions(&options); err != nil {
+		return nil, err
+	}
 
-	exprID := uuid.New()
-	expression = strings.ReplaceAll(expression, " ", "")
+	catalog := &userCatalog{userID: userID}
+	items := make([]BatchItem, len(expressions))
+	var (
+		expressionsToSave []*models.Expression
+		tasks             []*models.Task
+	)
+	for i, expression := range expressions {
+		prepared, err := s.prepareExpression(ctx, catalog, expression, options)
+		if err != nil {
+			if !IsExpressionError(err) {
+				return nil, err
+			}
+			items[i].Err = err
+			continue
+		}
+		items[i].ID = prepared.expression.ID
+		expressionsToSave = append(expressionsToSave, prepared.expression)
+		tasks = append(tasks, prepared.tasks...)
+	}
+	if len(expressionsToSave) == 0 {
+		return items, nil
+	}
 
-	var tasks []*models.Task
-	expressionToSave := &models.Expression{
-		ID:         exprID,
-		UserID:     userID,
-		Expression: expression,
-		Status:     models.Pending,
+	if err := s.repo.CreateExpressionTasks(ctx, expressionsToSave, tasks); err != nil {
+		return nil, mapCreateError(err)
 	}
+	s.dispatcher.Notify()
+	return items, nil
+}
 
-	postfix := InfixToPostfix(expression)
-	var stack []*models.Operand
+// preparedExpression keeps the tokens and syntax tree an expression was built
+// from alongside the expression and its tasks.
+type preparedExpression struct {
+	expression *models.Expression
+	tasks      []*models.Task
+	tokens     []expr.Token
+	root       expr.Node
+}
 
-	for i, token := range postfix {
-		if isNumber(token) {
-			val, err := strconv.ParseFloat(token, 64)
-			if err != nil {
-				return uuid.Nil, ErrInvalidExpression
+func (s *expressionTaskService) prepareExpression(ctx context.Context, catalog *userCatalog, expression string, options CalculationOptions) (*preparedExpression, error) {
+	if err := checkLength(expression); err != nil {
+		return nil, err
+	}
+	tokens, err := expr.Lex(expression)
+	if err != nil {
+		return nil, err
+	}
+	if options.Lenient {
+		tokens = expr.Normalize(tokens)
+	}
+	root, err := expr.ParseTokens(tokens)
+	if err != nil {
+		return nil, err
+	}
+	functions, err := s.resolveFunctions(ctx, catalog, root)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateExpression(root, expression, functions); err != nil {
+		return nil, err
+	}
+	expressionToSave, tasks, err := s.buildExpression(ctx, catalog, root, expr.Format(tokens), functions, nil, options)
+	if err != nil {
+		return nil, err
+	}
+	return &preparedExpression{expression: expressionToSave, tasks: tasks, tokens: tokens, root: root}, nil
+}
+
+func (s *expressionTaskService) buildExpression(ctx context.Context, catalog *userCatalog, root expr.Node, text string,
+	functions map[string]*models.UserFunction, bound map[string]*models.Variable, options CalculationOptions) (*models.Expression, []*models.Task, error) {
+	if options.Precision == models.PrecisionDecimal {
+		if err := validateDecimalNode(root); err != nil {
+			return nil, nil, err
+		}
+	}
+	var free []*expr.Ident
+	for _, ident := range identsOf(root) {
+		if _, ok := bound[ident.Name]; !ok {
+			free = append(free, ident)
+		}
+	}
+	variables, err := s.resolveVariables(ctx, catalog, free)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ident := range identsOf(root) {
+		if variable, ok := bound[ident.Name]; ok {
+			if variables == nil {
+				variables = make(map[string]*models.Variable)
 			}
-			stack = append(stack, &models.Operand{Value: val})
-		} else if isOperator(token) {
-			right := stack[len(stack)-1]
-			left := stack[len(stack)-2]
-			stack = stack[:len(stack)-2]
-			isFinalTask := (i == len(postfix)-1)
-			task := models.Task{
-				ID:            uuid.New(),
-				ExpressionID:  exprID,
-				Arg1:          *left,
-				Arg2:          *right,
-				Operator:      token,
-				OperationTime: time.Time{},
-				FinalTask:     isFinalTask,
+			variables[ident.Name] = variable
+		}
+	}
+
+	expressionToSave := newExpression(catalog.userID, text, options, variables)
+	tasks, result, err := BuildTasks(expressionToSave.ID, root, BuildOptions{
+		CalculationOptions: options,
+		Variables:          variables,
+		Functions:          functions,
+		FoldOperators:      s.foldOperators,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	setResult(expressionToSave, tasks, result)
+	return expressionToSave, tasks, nil
+}
+
+func (s *expressionTaskService) CreateScriptTask(ctx context.Context, userID uuid.UUID, script string, output ScriptOutput, options CalculationOptions) (uuid.UUID, error) {
+	switch output {
+	case "":
+		output = ScriptOutputLast
+	case ScriptOutputLast, ScriptOutputAll:
+	default:
+		return uuid.Nil, ErrUnknownScriptOutput
+	}
+	if err := validateOptions(&options); err != nil {
+		return uuid.Nil, err
+	}
+	if err := checkLength(script); err != nil {
+		return uuid.Nil, err
+	}
+	catalog := &userCatalog{userID: userID}
+	tokens, err := expr.LexScript(script)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if options.Lenient {
+		tokens = expr.Normalize(tokens)
+	}
+	parsed, err := expr.ParseScriptTokens(tokens)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	roots := make([]expr.Node, len(parsed.Statements))
+	assigned := make(map[string]bool)
+	var free []*expr.Ident
+	for i, statement := range parsed.Statements {
+		roots[i] = statement.Value
+		for _, ident := range identsOf(statement.Value) {
+			if !assigned[ident.Name] {
+				free = append(free, ident)
 			}
-			tasks = append(tasks, &task)
-			stack = append(stack, &models.Operand{Value: math.NaN(), TaskID: &task.ID})
 		}
+		if statement.Name == "" {
+			continue
+		}
+		if !IsValidName(statement.Name) {
+			return uuid.Nil, expr.NewError(ErrAssignmentIssue, statement.NamePos, statement.NamePos+len(statement.Name), statement.Name)
+		}
+		if assigned[statement.Name] {
+			return uuid.Nil, expr.NewError(ErrNameReassigned, statement.NamePos, statement.NamePos+len(statement.Name), statement.Name)
+		}
+		assigned[statement.Name] = true
 	}
 
-	if err := s.repo.CreateExpressionTask(ctx, expressionToSave, tasks); err != nil {
-		if errors.Is(err, repository.ErrUnknownUserID) {
-			return uuid.Nil, ErrUnknownUserID
+	functions, err := s.resolveFunctions(ctx, catalog, roots...)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	computes := false
+	for _, root := range roots {
+		if err := validateNode(root, functions); err != nil {
+			return uuid.Nil, err
 		}
-		if errors.Is(err, repository.ErrDatabaseNotAvailable) {
-			return uuid.Nil, ErrDatabaseUnavailable
+		if options.Precision == models.PrecisionDecimal {
+			if err := validateDecimalNode(root); err != nil {
+				return uuid.Nil, err
+			}
 		}
+		computes = computes || requiresComputation(root)
+	}
+	if !computes {
+		last := parsed.Statements[len(parsed.Statements)-1]
+		return uuid.Nil, expr.NewError(ErrMissingOperator, last.Pos(), last.End(), script[last.Pos():last.End()], "operator")
+	}
+	variables, err := s.resolveVariables(ctx, catalog, free)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	expressionToSave := newExpression(userID, expr.Format(tokens), options, variables)
+	tasks, values, result, err := BuildScriptTasks(expressionToSave.ID, parsed, output, BuildOptions{
+		CalculationOptions: options,
+		Variables:          variables,
+		Functions:          functions,
+		FoldOperators:      s.foldOperators,
+	})
+	if err != nil {
 		return uuid.Nil, err
 	}
+	expressionToSave.Values = values
+	setResult(expressionToSave, tasks, result)
+	return s.saveExpression(ctx, expressionToSave, tasks)
+}
+
+func newExpression(userID uuid.UUID, text string, options CalculationOptions, variables map[string]*models.Variable) *models.Expression {
+	expression := &models.Expression{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Expression: text,
+		Status:     models.Pending,
+		Precision:  options.Precision,
+		Scale:      options.Scale,
+		Policy:     options.Policy,
+	}
+	if len(variables) > 0 {
+		expression.Variables = make(map[string]string, len(variables))
+		for name, variable := range variables {
+			expression.Variables[name] = variable.Decimal
+		}
+	}
+	return expression
+}
+
+func setResult(expression *models.Expression, tasks []*models.Task, result models.Operand) {
+	if result.TaskID != nil {
+		return
+	}
+	expression.Result = result.Value
+	expression.DecimalResult = result.Decimal
+	if len(tasks) == 0 {
+		expression.Status = models.Done
+	}
+}
+
+func (s *expressionTaskService) saveExpression(ctx context.Context, expression *models.Expression, tasks []*models.Task) (uuid.UUID, error) {
+	if err := s.repo.CreateExpressionTask(ctx, expression, tasks); err != nil {
+		return uuid.Nil, mapCreateError(err)
+	}
+	if len(tasks) > 0 {
+		s.dispatcher.Notify()
+	}
+	return expression.ID, nil
+}
 
-	return exprID, nil
+func mapCreateError(err error) error {
+	if errors.Is(err, repository.ErrUnknownUserID) {
+		return ErrUnknownUserID
+	}
+	if errors.Is(err, repository.ErrDatabaseNotAvailable) {
+		return ErrDatabaseUnavailable
+	}
+	return err
 }
 
 func (s *expressionTaskService) GetAllExpressions(ctx context.Context, userID uuid.UUID) ([]*models.Expression, error) {
@@ -163,8 +457,22 @@ func (s *expressionTaskService) GetExpressionById(ctx context.Context, expressio
 	return expression, nil
 }
 
-func (s *expressionTaskService) GetTask(ctx context.Context) (*pb.Task, error) {
-	task, err := s.repo.GetTask(ctx, s.GetOperationEndTime)
+func (s *expressionTaskService) GetExpressionTasks(ctx context.Context, expressionID uuid.UUID) ([]*models.TaskRecord, error) {
+	if _, err := s.GetExpressionById(ctx, expressionID); err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.GetExpressionTasks(ctx, expressionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDatabaseNotAvailable) {
+			return nil, ErrDatabaseUnavailable
+		}
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (s *expressionTaskService) GetTask(ctx context.Context, agent string) (*pb.Task, error) {
+	task, err := s.repo.GetTask(ctx, agent, s.lease, s.GetOperationEndTime)
 	if err != nil {
 		if errors.Is(err, repository.ErrDatabaseNotAvailable) {
 			return nil, ErrDatabaseUnavailable
@@ -178,241 +486,836 @@ func (s *expressionTaskService) GetTask(ctx context.Context) (*pb.Task, error) {
 	return task, nil
 }
 
-func (s *expressionTaskService) SetTaskResult(ctx context.Context, task *pb.Task, result float64) error {
-	if err := s.repo.SetTaskResult(ctx, task, result); err != nil {
+func (s *expressionTaskService) RenewLeases(ctx context.Context, agent string, taskIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+	renewed, err := s.repo.RenewLeases(ctx, agent, taskIDs, s.lease)
+	if err != nil {
+		if errors.Is(err, repository.ErrDatabaseNotAvailable) {
+			return nil, ErrDatabaseUnavailable
+		}
+		return nil, err
+	}
+	var lost []uuid.UUID
+	for _, id := range taskIDs {
+		if !slices.Contains(renewed, id) {
+			lost = append(lost, id)
+		}
+	}
+	return lost, nil
+}
+
+func (s *expressionTaskService) ReleaseTask(ctx context.Context, task *pb.Task) error {
+	taskID, err := uuid.Parse(task.Id)
+	if err != nil {
+		return ErrUnknownTaskID
+	}
+	token, err := uuid.Parse(task.AssignmentToken)
+	if err != nil {
+		return ErrLeaseNotHeld
+	}
+	if err := s.repo.ReleaseTask(ctx, taskID, token); err != nil {
 		if errors.Is(err, repository.ErrDatabaseNotAvailable) {
 			return ErrDatabaseUnavailable
 		}
-		if errors.Is(err, repository.ErrUnknownTaskID) {
+		return err
+	}
+	s.dispatcher.Notify()
+	return nil
+}
+
+func (s *expressionTaskService) SetTaskResult(ctx context.Context, taskID, token uuid.UUID, result models.TaskResult) error {
+	if err := s.repo.SetTaskResult(ctx, taskID, token, result, normalizeResult); err != nil {
+		if errors.Is(err, repository.ErrDatabaseNotAvailable) {
+			return ErrDatabaseUnavailable
+		}
+		if errors.Is(err, repository.ErrLeaseNotHeld) {
+			return ErrLeaseNotHeld
+		}
+		if errors.Is(err, repository.ErrUnknownTaskID) || errors.Is(err, repository.ErrUnknownIDTasksWithDependency) {
 			return ErrUnknownTaskID
 		}
 		return err
 	}
+	s.dispatcher.Notify()
 	return nil
 }
 
-func InfixToPostfix(expression string) []string {
-	var stack []string
-	var output []string
-	for i := 0; i < len(expression); i++ {
-		ch := string(expression[i])
-		if isNumber(ch) {
-			num := ch
-			for i+1 < len(expression) && (isNumber(string(expression[i+1])) || string(expression[i+1]) == ".") {
-				i++
-				num += string(expression[i])
-			}
-			output = append(output, num)
-		} else if ch == "(" {
-			stack = append(stack, ch)
-		} else if ch == ")" {
-			for len(stack) > 0 && stack[len(stack)-1] != "(" {
-				output = append(output, stack[len(stack)-1])
-				stack = stack[:len(stack)-1]
-			}
-			stack = stack[:len(stack)-1]
-		} else if isOperator(ch) {
-			for len(stack) > 0 && precedence(stack[len(stack)-1]) >= precedence(ch) {
-				output = append(output, stack[len(stack)-1])
-				stack = stack[:len(stack)-1]
-			}
-			stack = append(stack, ch)
+func normalizeResult(precision models.Precision, policy models.NumericPolicy, result models.TaskResult) models.TaskResult {
+	if result.Error != "" {
+		return result
+	}
+	if precision == models.PrecisionDecimal {
+		if len(result.Decimal) > MaxDecimalResultLength {
+			result.Decimal = ""
+			result.Error = ErrDecimalTooLarge.Error()
 		}
+		return result
 	}
-	for len(stack) > 0 {
-		output = append(output, stack[len(stack)-1])
-		stack = stack[:len(stack)-1]
+	value, err := ApplyNumericPolicy(policy, result.Value)
+	if err != nil {
+		result.Error = err.Error()
 	}
-	return output
+	result.Value = value
+	return result
+}
+
+func (s *expressionTaskService) WaitTask(ctx context.Context, agent string) (*pb.Task, error) {
+	return s.dispatcher.Wait(ctx, agent)
 }
 
 func (s *expressionTaskService) GetOperationEndTime(operator string) *timestamppb.Timestamp {
-	var endTime time.Time
+	duration, ok := s.operationDuration(operator)
+	if !ok {
+		return nil
+	}
+	return timestamppb.New(time.Now().Add(duration))
+}
+
+func (s *expressionTaskService) operationDuration(operator string) (time.Duration, bool) {
 	switch operator {
 	case "+":
-		endTime = time.Now().Add(s.cfg.Addition)
-	case "-":
-		endTime = time.Now().Add(s.cfg.Subtraction)
+		return s.cfg.Addition, true
+	case "-", NegationOperator:
+		return s.cfg.Subtraction, true
 	case "*":
-		endTime = time.Now().Add(s.cfg.Multiplication)
+		return s.cfg.Multiplication, true
 	case "/":
-		endTime = time.Now().Add(s.cfg.Division)
-	default:
-		return nil
+		return s.cfg.Division, true
+	case "^":
+		return s.cfg.Exponentiation, true
+	case "<", "<=", ">", ">=", "==", "!=", "&&", "||", "!":
+		return s.cfg.Comparison, true
+	case "%":
+		return s.cfg.Modulo, true
+	case "//":
+		return s.cfg.IntegerDivision, true
+	case "&":
+		return s.cfg.BitwiseAnd, true
+	case "|":
+		return s.cfg.BitwiseOr, true
+	case "xor":
+		return s.cfg.BitwiseXor, true
+	case "<<":
+		return s.cfg.ShiftLeft, true
+	case ">>":
+		return s.cfg.ShiftRight, true
+	case models.ConditionalOperator:
+		return 0, false
+	}
+	if _, ok := LookupFunction(operator); !ok {
+		return 0, false
 	}
-	return timestamppb.New(endTime)
+	return s.cfg.Function, true
 }
 
-func precedence(op string) int {
-	switch op {
-	case "+", "-":
-		return 1
-	case "*", "/":
-		return 2
-	}
-	return 0
+type taskBuilder struct {
+	exprID    uuid.UUID
+	decimal   bool
+	scale     int
+	policy    models.NumericPolicy
+	optimize  bool
+	fold      map[string]bool
+	variables map[string]*models.Variable
+	functions map[string]*models.UserFunction
+	locals    map[string]models.Operand
+	bodies    map[string]expr.Node
+	scope     *callScope
+	branch    *branch
+	seen      map[string]models.Operand
+	tasks     []*models.Task
 }
 
-func isNumber(s string) bool {
-	_, err := strconv.ParseFloat(s, 64)
-	return err == nil
+type branch struct {
+	conditional uuid.UUID
+	then        bool
+	parent      *branch
 }
 
-func isOperator(s string) bool {
-	return s == "+" || s == "-" || s == "*" || s == "/"
+type callScope struct {
+	function string
+	depth    int
+	caller   *callScope
+	params   map[string]*paramBinding
 }
 
-func ValidateExpression(expression string) error {
-	expression = strings.ReplaceAll(expression, " ", "")
-	if len(expression) == 0 {
-		return ErrEmptyExpression
+type paramBinding struct {
+	node    expr.Node
+	operand *models.Operand
+	branch  *branch
+}
+
+func BuildTasks(exprID uuid.UUID, root expr.Node, options BuildOptions) ([]*models.Task, models.Operand, error) {
+	b := newTaskBuilder(exprID, options)
+	result, err := b.build(root, true)
+	if err != nil {
+		return nil, models.Operand{}, err
 	}
+	return b.tasks, result, nil
+}
 
-	if err := checkDivisionByZero(expression); err != nil {
-		return err
+func BuildScriptTasks(exprID uuid.UUID, script *expr.Script, output ScriptOutput, options BuildOptions) ([]*models.Task, []*models.NamedValue, models.Operand, error) {
+	statements := script.Statements
+	needed := make([]bool, len(statements))
+	wanted := make(map[string]bool)
+	for i := len(statements) - 1; i >= 0; i-- {
+		name := statements[i].Name
+		if i != len(statements)-1 && !wanted[name] && (output != ScriptOutputAll || name == "") {
+			continue
+		}
+		needed[i] = true
+		for _, ident := range identsOf(statements[i].Value) {
+			wanted[ident.Name] = true
+		}
+	}
+
+	b := newTaskBuilder(exprID, options)
+	b.locals = make(map[string]models.Operand)
+	var (
+		values []*models.NamedValue
+		result models.Operand
+	)
+	for i, statement := range statements {
+		if !needed[i] {
+			continue
+		}
+		operand, err := b.build(statement.Value, false)
+		if err != nil {
+			return nil, nil, models.Operand{}, err
+		}
+		result = operand
+		if statement.Name == "" {
+			continue
+		}
+		b.locals[statement.Name] = operand
+		if output == ScriptOutputAll {
+			values = append(values, namedValue(statement.Name, operand))
+		}
+	}
+	if result.TaskID != nil {
+		for _, task := range b.tasks {
+			if task.ID == *result.TaskID {
+				task.FinalTask = true
+			}
+		}
 	}
+	return b.tasks, values, result, nil
+}
 
-	if !containsOperator(expression) {
-		return ErrMissingOperator
+func namedValue(name string, operand models.Operand) *models.NamedValue {
+	value := &models.NamedValue{Name: name, TaskID: operand.TaskID}
+	if operand.TaskID == nil {
+		number := models.Number(operand.Value)
+		value.Value = &number
+		value.Decimal = operand.Decimal
 	}
+	return value
+}
 
-	if err := validateCharacters(expression); err != nil {
-		return err
+func newTaskBuilder(exprID uuid.UUID, options BuildOptions) *taskBuilder {
+	return &taskBuilder{
+		exprID:    exprID,
+		decimal:   options.Precision == models.PrecisionDecimal,
+		scale:     options.Scale,
+		policy:    options.Policy,
+		optimize:  options.Optimize,
+		fold:      options.FoldOperators,
+		variables: options.Variables,
+		functions: options.Functions,
+		bodies:    make(map[string]expr.Node),
+		seen:      make(map[string]models.Operand),
 	}
+}
 
-	if err := validateParentheses(expression); err != nil {
-		return err
+func (b *taskBuilder) build(node expr.Node, final bool) (models.Operand, error) {
+	switch n := node.(type) {
+	case *expr.NumberLit:
+		operand := models.Operand{Value: n.Value}
+		if b.decimal {
+			value, err := expr.ParseDecimal(n.Raw)
+			if err != nil {
+				return models.Operand{}, expr.NewError(ErrNumberFormatIssue, n.Pos(), n.End(), n.Raw)
+			}
+			operand.Decimal = expr.FormatDecimal(value)
+		}
+		return operand, nil
+	case *expr.Ident:
+		if b.scope != nil {
+			return b.buildParam(n, final)
+		}
+		if operand, ok := b.locals[n.Name]; ok {
+			return operand, nil
+		}
+		variable, ok := b.variables[n.Name]
+		if !ok {
+			return models.Operand{}, expr.NewError(ErrUnknownVariable, n.Pos(), n.End(), n.Name)
+		}
+		operand := models.Operand{Value: variable.Value}
+		if b.decimal {
+			operand.Decimal = variable.Decimal
+		}
+		return operand, nil
+	case *expr.ParenExpr:
+		return b.build(n.X, final)
+	case *expr.UnaryExpr:
+		if n.Op == "+" {
+			return b.build(n.X, final)
+		}
+		operand, err := b.build(n.X, false)
+		if err != nil {
+			return models.Operand{}, err
+		}
+		if n.Op == "!" {
+			return b.addTask(n.Op, final, operand)
+		}
+		if operand.TaskID == nil && !final {
+			operand.Value = -operand.Value
+			if b.decimal {
+				value, _ := new(big.Rat).SetString(operand.Decimal)
+				operand.Decimal = expr.FormatDecimal(value.Neg(value))
+			}
+			return operand, nil
+		}
+		return b.addTask(NegationOperator, final, operand)
+	case *expr.BinaryExpr:
+		x, err := b.build(n.X, false)
+		if err != nil {
+			return models.Operand{}, err
+		}
+		y, err := b.build(n.Y, false)
+		if err != nil {
+			return models.Operand{}, err
+		}
+		if IsIntegerOperator(n.Op) {
+			if err := b.checkInteger(n.X, x); err != nil {
+				return models.Operand{}, err
+			}
+			if err := b.checkInteger(n.Y, y); err != nil {
+				return models.Operand{}, err
+			}
+		}
+		return b.addTask(n.Op, final, x, y)
+	case *expr.CondExpr:
+		return b.conditional(n.Cond, n.X, n.Y, final)
+	case *expr.CallExpr:
+		if _, ok := LookupFunction(n.Func); !ok {
+			return b.expand(n, final)
+		}
+		if n.Func == models.ConditionalOperator {
+			return b.conditional(n.Args[0], n.Args[1], n.Args[2], final)
+		}
+		args := make([]models.Operand, len(n.Args))
+		for i, arg := range n.Args {
+			operand, err := b.build(arg, false)
+			if err != nil {
+				return models.Operand{}, err
+			}
+			args[i] = operand
+		}
+		return b.addTask(n.Func, final, args...)
 	}
+	return models.Operand{}, ErrInvalidExpression
+}
 
-	if err := validateNumbers(expression); err != nil {
-		return err
+func (b *taskBuilder) checkInteger(node expr.Node, operand models.Operand) error {
+	if operand.TaskID != nil {
+		return nil
 	}
+	integer := operand.Value == math.Trunc(operand.Value)
+	if b.decimal {
+		value, ok := new(big.Rat).SetString(operand.Decimal)
+		integer = ok && value.IsInt()
+	}
+	if integer {
+		return nil
+	}
+	var token string
+	switch n := expr.Unparen(node).(type) {
+	case *expr.NumberLit:
+		token = n.Raw
+	case *expr.Ident:
+		token = n.Name
+	}
+	return expr.NewError(ErrIntegerOperandRequired, node.Pos(), node.End(), token)
+}
 
-	if err := validateOperators(expression); err != nil {
-		return err
+func (b *taskBuilder) conditional(cond, then, otherwise expr.Node, final bool) (models.Operand, error) {
+	condition, err := b.build(cond, false)
+	if err != nil {
+		return models.Operand{}, err
+	}
+	if condition.TaskID == nil {
+		if condition.Value != 0 {
+			return b.build(then, final)
+		}
+		return b.build(otherwise, final)
 	}
 
-	if isOperator(string(expression[0])) || isOperator(string(expression[len(expression)-1])) {
-		return ErrInvalidExpressionStartEnd
+	task, err := b.newTask(models.ConditionalOperator, final)
+	if err != nil {
+		return models.Operand{}, err
 	}
+	x, err := b.buildBranch(task.ID, true, then)
+	if err != nil {
+		return models.Operand{}, err
+	}
+	y, err := b.buildBranch(task.ID, false, otherwise)
+	if err != nil {
+		return models.Operand{}, err
+	}
+	task.Args = []models.Operand{condition, x, y}
+	return models.Operand{Value: math.NaN(), TaskID: &task.ID}, nil
+}
 
-	return nil
+func (b *taskBuilder) buildBranch(conditional uuid.UUID, then bool, node expr.Node) (models.Operand, error) {
+	parent, seen := b.branch, b.seen
+	b.branch = &branch{conditional: conditional, then: then, parent: parent}
+	b.seen = maps.Clone(seen)
+	operand, err := b.build(node, false)
+	b.branch, b.seen = parent, seen
+	return operand, err
 }
 
-func containsOperator(expression string) bool {
-	for _, ch := range expression {
-		if isOperator(string(ch)) {
+func (b *taskBuilder) within(target *branch) bool {
+	for current := b.branch; current != nil; current = current.parent {
+		if current == target {
 			return true
 		}
 	}
-	return false
+	return target == nil
 }
 
-func validateCharacters(expression string) error {
-	for _, ch := range expression {
-		if !(unicode.IsDigit(ch) || ch == '.' || isOperator(string(ch)) || ch == '(' || ch == ')') {
-			return ErrInvalidCharacter
+func (b *taskBuilder) expand(call *expr.CallExpr, final bool) (models.Operand, error) {
+	fn, ok := b.functions[call.Func]
+	if !ok {
+		return models.Operand{}, expr.NewError(ErrUnknownFunction, call.NamePos, call.NamePos+len(call.Func), call.Func)
+	}
+	for scope := b.scope; scope != nil; scope = scope.caller {
+		if scope.function == fn.Name {
+			return models.Operand{}, callSiteError(ErrRecursiveFunction, call)
 		}
 	}
-	return nil
+	depth := 1
+	if b.scope != nil {
+		depth = b.scope.depth + 1
+	}
+	if depth > MaxFunctionDepth {
+		return models.Operand{}, callSiteError(ErrFunctionDepthExceeded, call)
+	}
+	body, err := b.body(fn)
+	if err != nil {
+		return models.Operand{}, callSiteError(err, call)
+	}
+
+	scope := &callScope{
+		function: fn.Name,
+		depth:    depth,
+		caller:   b.scope,
+		params:   make(map[string]*paramBinding, len(fn.Params)),
+	}
+	for i, param := range fn.Params {
+		scope.params[param] = &paramBinding{node: call.Args[i]}
+	}
+
+	b.scope = scope
+	operand, err := b.build(body, final)
+	b.scope = scope.caller
+	if err != nil {
+		return models.Operand{}, callSiteError(err, call)
+	}
+	return operand, nil
 }
 
-func validateParentheses(expression string) error {
-	balance := 0
-	lastChar := ' '
+func (b *taskBuilder) body(fn *models.UserFunction) (expr.Node, error) {
+	if body, ok := b.bodies[fn.Name]; ok {
+		return body, nil
+	}
+	body, err := expr.Parse(fn.Body)
+	if err == nil {
+		err = validateNode(body, b.functions)
+	}
+	if err == nil && b.decimal {
+		err = validateDecimalNode(body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.bodies[fn.Name] = body
+	return body, nil
+}
 
-	for i, ch := range expression {
-		switch ch {
-		case '(':
-			balance++
-			if i > 0 && !isOperator(string(lastChar)) && lastChar != '(' {
-				return ErrParenthesisIssue
-			}
-		case ')':
-			balance--
-			if balance < 0 {
-				return ErrParenthesisIssue
-			}
-			if i+1 < len(expression) && !isOperator(string(expression[i+1])) && expression[i+1] != ')' {
-				return ErrParenthesisIssue
-			}
+func (b *taskBuilder) buildParam(ident *expr.Ident, final bool) (models.Operand, error) {
+	binding, ok := b.scope.params[ident.Name]
+	if !ok {
+		return models.Operand{}, expr.NewError(ErrUnknownVariable, ident.Pos(), ident.End(), ident.Name)
+	}
+	if binding.operand == nil || !b.within(binding.branch) {
+		scope := b.scope
+		b.scope = scope.caller
+		operand, err := b.build(binding.node, final)
+		b.scope = scope
+		if err != nil {
+			return models.Operand{}, err
+		}
+		binding.operand = &operand
+		binding.branch = b.branch
+	}
+	return *binding.operand, nil
+}
+
+func callSiteError(err error, call *expr.CallExpr) error {
+	var exprErr *expr.Error
+	if errors.As(err, &exprErr) {
+		err = exprErr.Err
+	}
+	return expr.NewError(err, call.Pos(), call.End(), call.Func)
+}
+
+func (b *taskBuilder) addTask(operator string, final bool, args ...models.Operand) (models.Operand, error) {
+	var key string
+	if b.optimize {
+		if operand, ok := b.foldTask(operator, args); ok {
+			return operand, nil
+		}
+		key = taskKey(operator, args)
+		if operand, ok := b.seen[key]; ok && !final {
+			return operand, nil
+		}
+	}
+	task, err := b.newTask(operator, final)
+	if err != nil {
+		return models.Operand{}, err
+	}
+	task.Args = args
+	operand := models.Operand{Value: math.NaN(), TaskID: &task.ID}
+	if b.optimize && !final {
+		b.seen[key] = operand
+	}
+	return operand, nil
+}
+
+func (b *taskBuilder) newTask(operator string, final bool) (*models.Task, error) {
+	if len(b.tasks) >= MaxExpressionTasks {
+		return nil, ErrTooManyTasks
+	}
+	task := &models.Task{
+		ID:            uuid.New(),
+		ExpressionID:  b.exprID,
+		Operator:      operator,
+		OperationTime: time.Time{},
+		FinalTask:     final,
+	}
+	if b.branch != nil {
+		task.BranchOf = &b.branch.conditional
+		task.ThenBranch = b.branch.then
+	}
+	b.tasks = append(b.tasks, task)
+	return task, nil
+}
+
+func (b *taskBuilder) foldTask(operator string, args []models.Operand) (models.Operand, bool) {
+	if !b.fold[operator] {
+		return models.Operand{}, false
+	}
+	for _, arg := range args {
+		if arg.TaskID != nil {
+			return models.Operand{}, false
+		}
+	}
+	if b.decimal {
+		decimal, ok := foldDecimal(operator, args, b.scale)
+		if !ok {
+			return models.Operand{}, false
 		}
-		lastChar = ch
+		value, _ := strconv.ParseFloat(decimal, 64)
+		return models.Operand{Value: value, Decimal: decimal}, true
 	}
+	value, ok := foldFloat(operator, args)
+	if !ok {
+		return models.Operand{}, false
+	}
+	value, err := ApplyNumericPolicy(b.policy, value)
+	return models.Operand{Value: value}, err == nil
+}
 
-	if balance != 0 {
-		return ErrParenthesisIssue
+func checkLength(input string) error {
+	if len(input) > MaxExpressionLength {
+		return expr.NewError(ErrExpressionTooLong, MaxExpressionLength, len(input), "")
 	}
 	return nil
 }
 
-func validateNumbers(expression string) error {
-	parts := strings.FieldsFunc(expression, func(c rune) bool {
-		return isOperator(string(c)) || c == '(' || c == ')'
-	})
+func InfixToPostfix(expression string) []string {
+	root, err := expr.Parse(expression)
+	if err != nil {
+		return nil
+	}
+	return Postfix(root)
+}
 
-	for _, part := range parts {
-		if strings.Count(part, ".") > 1 {
-			return ErrNumberFormatIssue
+func Postfix(node expr.Node) []string {
+	switch n := node.(type) {
+	case *expr.NumberLit:
+		return []string{n.Raw}
+	case *expr.Ident:
+		return []string{n.Name}
+	case *expr.ParenExpr:
+		return Postfix(n.X)
+	case *expr.UnaryExpr:
+		switch n.Op {
+		case "+":
+			return Postfix(n.X)
+		case "-":
+			return append(Postfix(n.X), NegationOperator)
 		}
-		if strings.HasPrefix(part, ".") || strings.HasSuffix(part, ".") {
-			return ErrNumberFormatIssue
+		return append(Postfix(n.X), n.Op)
+	case *expr.BinaryExpr:
+		return append(append(Postfix(n.X), Postfix(n.Y)...), n.Op)
+	case *expr.CondExpr:
+		output := append(Postfix(n.Cond), Postfix(n.X)...)
+		return append(append(output, Postfix(n.Y)...), "?:")
+	case *expr.CallExpr:
+		var output []string
+		for _, arg := range n.Args {
+			output = append(output, Postfix(arg)...)
 		}
-		if len(part) > 1 && part[0] == '0' && part[1] != '.' {
-			return ErrNumberFormatIssue
+		return append(output, n.Func+":"+strconv.Itoa(len(n.Args)))
+	}
+	return nil
+}
+
+func ParseExpression(expression string) (expr.Node, error) {
+	root, err := expr.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateExpression(root, expression, nil); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func validateExpression(root expr.Node, expression string, functions map[string]*models.UserFunction) error {
+	if err := validateNode(root, functions); err != nil {
+		return err
+	}
+	if !requiresComputation(root) {
+		return expr.NewError(ErrMissingOperator, root.Pos(), root.End(), expression[root.Pos():root.End()], "operator")
+	}
+	return nil
+}
+
+func validateOptions(options *CalculationOptions) error {
+	switch options.Precision {
+	case "", models.PrecisionFloat:
+		options.Precision = models.PrecisionFloat
+		options.Scale = 0
+	case models.PrecisionDecimal:
+		if options.Scale < 0 || options.Scale > MaxDecimalScale {
+			return ErrInvalidScale
 		}
+	default:
+		return ErrUnknownPrecision
+	}
+	switch options.Policy {
+	case "":
+		options.Policy = models.PolicyStrict
+	case models.PolicyStrict, models.PolicyIEEE, models.PolicySaturate:
+	default:
+		return ErrUnknownNumericPolicy
 	}
 	return nil
 }
 
-func validateOperators(expression string) error {
-	lastWasOperator := false
+func ApplyNumericPolicy(policy models.NumericPolicy, value float64) (float64, error) {
+	switch {
+	case !math.IsNaN(value) && !math.IsInf(value, 0):
+		return value, nil
+	case policy == models.PolicyIEEE:
+		return value, nil
+	case policy == models.PolicySaturate && math.IsInf(value, 0):
+		return math.Copysign(math.MaxFloat64, value), nil
+	}
+	return value, ErrNonFiniteResult
+}
 
-	for i, ch := range expression {
-		if isOperator(string(ch)) {
-			if lastWasOperator {
-				return ErrOperatorIssue
+func validateDecimalNode(root expr.Node) error {
+	var err error
+	expr.Walk(root, func(node expr.Node) bool {
+		if err != nil {
+			return false
+		}
+		switch n := node.(type) {
+		case *expr.NumberLit:
+			if len(n.Raw) > MaxDecimalLiteralLength {
+				err = expr.NewError(ErrDecimalTooLarge, n.Pos(), n.End(), n.Raw)
 			}
-			if i+1 < len(expression) && (expression[i+1] == ')' || isOperator(string(expression[i+1]))) {
-				return ErrOperatorIssue
+		case *expr.CallExpr:
+			if fn, ok := LookupFunction(n.Func); ok && !fn.Decimal {
+				err = expr.NewError(ErrDecimalUnsupported, n.NamePos, n.NamePos+len(n.Func), n.Func)
+			}
+		case *expr.BinaryExpr:
+			if lit, ok := unsignedLiteral(n.Y); ok && n.Op == "^" && lit.Value != math.Trunc(lit.Value) {
+				err = expr.NewError(ErrDecimalUnsupported, lit.Pos(), lit.End(), lit.Raw)
 			}
-			lastWasOperator = true
-		} else {
-			lastWasOperator = false
 		}
+		return true
+	})
+	return err
+}
 
-		if ch == '(' {
-			if i+1 < len(expression) && expression[i+1] == ')' {
-				return ErrParenthesisIssue
+func unsignedLiteral(node expr.Node) (*expr.NumberLit, bool) {
+	switch n := expr.Unparen(node).(type) {
+	case *expr.NumberLit:
+		return n, true
+	case *expr.UnaryExpr:
+		if n.Op == "!" {
+			return nil, false
+		}
+		return unsignedLiteral(n.X)
+	}
+	return nil, false
+}
+
+func ValidateExpression(expression string) error {
+	_, err := ParseExpression(expression)
+	return err
+}
+
+func validateNode(root expr.Node, functions map[string]*models.UserFunction) error {
+	var err error
+	expr.Walk(root, func(node expr.Node) bool {
+		if err != nil {
+			return false
+		}
+		switch n := node.(type) {
+		case *expr.Ident:
+			if _, ok := LookupFunction(n.Name); ok {
+				err = expr.NewError(ErrFunctionCallIssue, n.Pos(), n.End(), n.Name, "(")
+			}
+		case *expr.CallExpr:
+			if fn, ok := LookupFunction(n.Func); ok {
+				if !fn.AcceptsArgs(len(n.Args)) {
+					err = expr.NewError(ErrFunctionArgumentCount, n.Pos(), n.End(), n.Func)
+				}
+			} else if fn, ok := functions[n.Func]; ok {
+				if len(n.Args) != len(fn.Params) {
+					err = expr.NewError(ErrFunctionArgumentCount, n.Pos(), n.End(), n.Func)
+				}
+			} else {
+				err = expr.NewError(ErrUnknownFunction, n.NamePos, n.NamePos+len(n.Func), n.Func)
+			}
+		case *expr.BinaryExpr:
+			if (n.Op == "/" || n.Op == "//" || n.Op == "%") && isLiteralZero(n.Y) {
+				err = expr.NewError(ErrDivisionByZero, n.Y.Pos(), n.Y.End(), "0")
+				break
+			}
+			if !IsIntegerOperator(n.Op) {
+				break
+			}
+			for _, operand := range []expr.Node{n.X, n.Y} {
+				if lit, ok := unsignedLiteral(operand); ok && err == nil && lit.Value != math.Trunc(lit.Value) {
+					err = expr.NewError(ErrIntegerOperandRequired, lit.Pos(), lit.End(), lit.Raw)
+				}
 			}
 		}
+		return true
+	})
+	return err
+}
 
-		if ch == '(' && i+1 < len(expression) && isOperator(string(expression[i+1])) {
-			return ErrUnaryOperatorNotSupported
+func isLiteralZero(node expr.Node) bool {
+	lit, ok := unsignedLiteral(node)
+	return ok && lit.Value == 0
+}
+
+func requiresComputation(node expr.Node) bool {
+	switch n := expr.Unparen(node).(type) {
+	case *expr.NumberLit, *expr.Ident:
+		return false
+	case *expr.UnaryExpr:
+		return n.Op != "+" || requiresComputation(n.X)
+	}
+	return true
+}
+
+func identsOf(root expr.Node) []*expr.Ident {
+	var idents []*expr.Ident
+	expr.Walk(root, func(node expr.Node) bool {
+		if ident, ok := node.(*expr.Ident); ok {
+			idents = append(idents, ident)
 		}
+		return true
+	})
+	return idents
+}
+
+func (s *expressionTaskService) resolveVariables(ctx context.Context, catalog *userCatalog, idents []*expr.Ident) (map[string]*models.Variable, error) {
+	if len(idents) == 0 {
+		return nil, nil
 	}
 
-	return nil
+	if catalog.variables == nil {
+		variables, err := s.repo.GetVariables(ctx, catalog.userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDatabaseNotAvailable) {
+				return nil, ErrDatabaseUnavailable
+			}
+			return nil, err
+		}
+		catalog.variables = make(map[string]*models.Variable, len(variables))
+		for _, variable := range variables {
+			catalog.variables[variable.Name] = variable
+		}
+	}
+	available := catalog.variables
+
+	resolved := make(map[string]*models.Variable)
+	for _, ident := range idents {
+		variable, ok := available[ident.Name]
+		if !ok {
+			return nil, expr.NewError(ErrUnknownVariable, ident.Pos(), ident.End(), ident.Name)
+		}
+		resolved[ident.Name] = variable
+	}
+	return resolved, nil
 }
 
-var divisionByZeroRegex = regexp.MustCompile(`/\s*[+-]?\s*0+(\.0*)?([^0-9.]|$)`)
+func (s *expressionTaskService) resolveFunctions(ctx context.Context, catalog *userCatalog, roots ...expr.Node) (map[string]*models.UserFunction, error) {
+	userDefined := false
+	for _, root := range roots {
+		expr.Walk(root, func(node expr.Node) bool {
+			if call, ok := node.(*expr.CallExpr); ok {
+				if _, ok := LookupFunction(call.Func); !ok {
+					userDefined = true
+				}
+			}
+			return !userDefined
+		})
+	}
+	if !userDefined {
+		return nil, nil
+	}
+	if catalog.functions != nil {
+		return catalog.functions, nil
+	}
 
-func checkDivisionByZero(expression string) error {
-	if divisionByZeroRegex.MatchString(expression) {
-		return ErrDivisionByZero
+	functions, err := s.repo.GetUserFunctions(ctx, catalog.userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDatabaseNotAvailable) {
+			return nil, ErrDatabaseUnavailable
+		}
+		return nil, err
 	}
-	return nil
+	catalog.functions = userFunctionMap(functions)
+	return catalog.functions, nil
 }
 
-func IsExpressionError(err error) bool {
-	return errors.Is(err, ErrEmptyExpression) ||
-		errors.Is(err, ErrMissingOperator) ||
-		errors.Is(err, ErrInvalidCharacter) ||
-		errors.Is(err, ErrParenthesisIssue) ||
-		errors.Is(err, ErrNumberFormatIssue) ||
-		errors.Is(err, ErrOperatorIssue) ||
-		errors.Is(err, ErrDivisionByZero) ||
-		errors.Is(err, ErrInvalidExpressionStartEnd) ||
-		errors.Is(err, ErrUnaryOperatorNotSupported) ||
-		errors.Is(err, ErrInvalidExpression)
+func userFunctionMap(functions []*models.UserFunction) map[string]*models.UserFunction {
+	byName := make(map[string]*models.UserFunction, len(functions))
+	for _, function := range functions {
+		byName[function.Name] = function
+	}
+	return byName
 }
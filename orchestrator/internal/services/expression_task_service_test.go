@@ -3,10 +3,13 @@ package services_test
 import (
 	"context"
 	"errors"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/database/postgres"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/repository"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/services"
@@ -30,7 +33,8 @@ func TestExpressionTaskService_CreateExpressionTask(t *testing.T) {
 		Multiplication: 200 * time.Millisecond,
 		Division:       200 * time.Millisecond,
 	}
-	service := services.NewExpressionTaskService(mockRepo, opTimes)
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
+	unexpectedErr := errors.New("unexpected error")
 
 	userID := uuid.New()
 	expressionID := uuid.New()
@@ -58,13 +62,29 @@ func TestExpressionTaskService_CreateExpressionTask(t *testing.T) {
 			expectedID:  uuid.Nil,
 			expectedErr: services.ErrEmptyExpression,
 		},
+		{
+			name:        "expression too long",
+			expression:  strings.Repeat("1+", services.MaxExpressionLength/2) + "1",
+			mockSetup:   func() {},
+			expectedID:  uuid.Nil,
+			expectedErr: services.ErrExpressionTooLong,
+		},
 		{
 			name:        "invalid characters",
-			expression:  "2+a",
+			expression:  "2+$",
 			mockSetup:   func() {},
 			expectedID:  uuid.Nil,
 			expectedErr: services.ErrInvalidCharacter,
 		},
+		{
+			name:       "unknown function",
+			expression: "2+foo(1)",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return([]*models.UserFunction{}, nil)
+			},
+			expectedID:  uuid.Nil,
+			expectedErr: services.ErrUnknownFunction,
+		},
 		{
 			name:        "division by zero",
 			expression:  "2/0",
@@ -94,21 +114,21 @@ func TestExpressionTaskService_CreateExpressionTask(t *testing.T) {
 			name:       "unexpected error",
 			expression: "2+2",
 			mockSetup: func() {
-				mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("unexpected error"))
+				mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).Return(unexpectedErr)
 			},
 			expectedID:  uuid.Nil,
-			expectedErr: errors.New("unexpected error"),
+			expectedErr: unexpectedErr,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
-			id, err := service.CreateExpressionTask(context.Background(), userID, tt.expression)
+			id, err := service.CreateExpressionTask(context.Background(), userID, tt.expression, services.CalculationOptions{})
 
 			if tt.expectedErr != nil {
 				assert.Error(t, err)
-				assert.Equal(t, tt.expectedErr, err)
+				assert.ErrorIs(t, err, tt.expectedErr)
 			} else {
 				assert.NoError(t, err)
 				assert.NotEqual(t, uuid.Nil, id)
@@ -123,7 +143,7 @@ func TestExpressionTaskService_GetAllExpressions(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	opTimes := &services.OperationTimesMS{}
-	service := services.NewExpressionTaskService(mockRepo, opTimes)
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
 
 	userID := uuid.New()
 	expressions := []*models.Expression{
@@ -194,7 +214,7 @@ func TestExpressionTaskService_GetExpressionById(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	opTimes := &services.OperationTimesMS{}
-	service := services.NewExpressionTaskService(mockRepo, opTimes)
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
 
 	expressionID := uuid.New()
 	userID := uuid.New()
@@ -277,6 +297,56 @@ func TestExpressionTaskService_GetExpressionById(t *testing.T) {
 	}
 }
 
+func TestExpressionTaskService_GetExpressionTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewExpressionTaskService(mockRepo, &services.OperationTimesMS{}, nil, 30*time.Second)
+
+	expressionID := uuid.New()
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user_id", userID)
+	expression := &models.Expression{ID: expressionID, UserID: userID, Expression: "2+2*2", Status: models.Pending}
+	first, result := uuid.New(), models.Number(4)
+	tasks := []*models.TaskRecord{
+		{
+			ID:       first,
+			Operator: "*",
+			Args:     []models.TaskArgument{{Value: &result}, {Value: &result}},
+			Status:   models.Done,
+			Agent:    "10.0.0.7:5000",
+			Result:   &result,
+		},
+		{
+			ID:        uuid.New(),
+			Operator:  "+",
+			Args:      []models.TaskArgument{{Value: &result}, {TaskID: &first}},
+			Status:    models.Pending,
+			FinalTask: true,
+		},
+	}
+
+	mockRepo.EXPECT().GetExpressionByID(gomock.Any(), expressionID).Return(expression, nil)
+	mockRepo.EXPECT().GetExpressionTasks(gomock.Any(), expressionID).Return(tasks, nil)
+	records, err := service.GetExpressionTasks(ctx, expressionID)
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, records)
+
+	mockRepo.EXPECT().GetExpressionByID(gomock.Any(), expressionID).Return(expression, nil)
+	_, err = service.GetExpressionTasks(context.WithValue(context.Background(), "user_id", uuid.New()), expressionID)
+	assert.Equal(t, services.ErrForbidden, err)
+
+	mockRepo.EXPECT().GetExpressionByID(gomock.Any(), expressionID).Return(nil, repository.ErrUnknownExpressionID)
+	_, err = service.GetExpressionTasks(ctx, expressionID)
+	assert.Equal(t, services.ErrUnknownExpressionsID, err)
+
+	mockRepo.EXPECT().GetExpressionByID(gomock.Any(), expressionID).Return(expression, nil)
+	mockRepo.EXPECT().GetExpressionTasks(gomock.Any(), expressionID).Return(nil, repository.ErrDatabaseNotAvailable)
+	_, err = service.GetExpressionTasks(ctx, expressionID)
+	assert.Equal(t, services.ErrDatabaseUnavailable, err)
+}
+
 func TestExpressionTaskService_GetTask(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -288,15 +358,14 @@ func TestExpressionTaskService_GetTask(t *testing.T) {
 		Multiplication: 200 * time.Millisecond,
 		Division:       200 * time.Millisecond,
 	}
-	service := services.NewExpressionTaskService(mockRepo, opTimes)
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
 
 	expressionID := uuid.New()
 	taskID := uuid.New()
 	expectedTask := &pb.Task{
 		Id:            taskID.String(),
 		ExpressionId:  expressionID.String(),
-		Arg1Num:       2,
-		Arg2Num:       3,
+		Args:          []float64{2, 3},
 		Operator:      "+",
 		OperationTime: timestamppb.New(time.Now().Add(opTimes.Addition)),
 		FinalTask:     false,
@@ -311,7 +380,7 @@ func TestExpressionTaskService_GetTask(t *testing.T) {
 		{
 			name: "success",
 			mockSetup: func() {
-				mockRepo.EXPECT().GetTask(gomock.Any(), gomock.Any()).Return(expectedTask, nil)
+				mockRepo.EXPECT().GetTask(gomock.Any(), "10.0.0.7:5000", 30*time.Second, gomock.Any()).Return(expectedTask, nil)
 			},
 			expectedResult: expectedTask,
 			expectedErr:    nil,
@@ -319,7 +388,7 @@ func TestExpressionTaskService_GetTask(t *testing.T) {
 		{
 			name: "no tasks available",
 			mockSetup: func() {
-				mockRepo.EXPECT().GetTask(gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockRepo.EXPECT().GetTask(gomock.Any(), "10.0.0.7:5000", 30*time.Second, gomock.Any()).Return(nil, nil)
 			},
 			expectedResult: nil,
 			expectedErr:    nil,
@@ -327,7 +396,7 @@ func TestExpressionTaskService_GetTask(t *testing.T) {
 		{
 			name: "database unavailable",
 			mockSetup: func() {
-				mockRepo.EXPECT().GetTask(gomock.Any(), gomock.Any()).Return(nil, repository.ErrDatabaseNotAvailable)
+				mockRepo.EXPECT().GetTask(gomock.Any(), "10.0.0.7:5000", 30*time.Second, gomock.Any()).Return(nil, repository.ErrDatabaseNotAvailable)
 			},
 			expectedResult: nil,
 			expectedErr:    services.ErrDatabaseUnavailable,
@@ -335,7 +404,7 @@ func TestExpressionTaskService_GetTask(t *testing.T) {
 		{
 			name: "unexpected error",
 			mockSetup: func() {
-				mockRepo.EXPECT().GetTask(gomock.Any(), gomock.Any()).Return(nil, errors.New("unexpected error"))
+				mockRepo.EXPECT().GetTask(gomock.Any(), "10.0.0.7:5000", 30*time.Second, gomock.Any()).Return(nil, errors.New("unexpected error"))
 			},
 			expectedResult: nil,
 			expectedErr:    errors.New("unexpected error"),
@@ -345,7 +414,7 @@ func TestExpressionTaskService_GetTask(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
-			result, err := service.GetTask(context.Background())
+			result, err := service.GetTask(context.Background(), "10.0.0.7:5000")
 
 			if tt.expectedErr != nil {
 				assert.Error(t, err)
@@ -365,53 +434,70 @@ func TestExpressionTaskService_SetTaskResult(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	opTimes := &services.OperationTimesMS{}
-	service := services.NewExpressionTaskService(mockRepo, opTimes)
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
 
-	task := &pb.Task{
-		Id:           uuid.New().String(),
-		ExpressionId: uuid.New().String(),
-		Arg1Num:      2,
-		Arg2Num:      3,
-		Operator:     "+",
-		FinalTask:    false,
-	}
+	taskID, token := uuid.New(), uuid.New()
 
 	tests := []struct {
 		name        string
 		mockSetup   func()
-		result      float64
+		result      models.TaskResult
 		expectedErr error
 	}{
 		{
 			name: "success",
 			mockSetup: func() {
-				mockRepo.EXPECT().SetTaskResult(gomock.Any(), task, 5.0).Return(nil)
+				mockRepo.EXPECT().SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Value: 5.0}, gomock.Any()).Return(nil)
 			},
-			result:      5.0,
+			result:      models.TaskResult{Value: 5.0},
 			expectedErr: nil,
 		},
 		{
 			name: "task not found",
 			mockSetup: func() {
-				mockRepo.EXPECT().SetTaskResult(gomock.Any(), task, 5.0).Return(repository.ErrUnknownTaskID)
+				mockRepo.EXPECT().SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Value: 5.0}, gomock.Any()).Return(repository.ErrUnknownTaskID)
 			},
-			result:      5.0,
+			result:      models.TaskResult{Value: 5.0},
+			expectedErr: services.ErrUnknownTaskID,
+		},
+		{
+			name: "evaluation error",
+			mockSetup: func() {
+				mockRepo.EXPECT().SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Error: "division by zero"}, gomock.Any()).Return(nil)
+			},
+			result:      models.TaskResult{Error: "division by zero"},
+			expectedErr: nil,
+		},
+		{
+			name: "lease not held",
+			mockSetup: func() {
+				mockRepo.EXPECT().SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Value: 5.0}, gomock.Any()).Return(repository.ErrLeaseNotHeld)
+			},
+			result:      models.TaskResult{Value: 5.0},
+			expectedErr: services.ErrLeaseNotHeld,
+		},
+		{
+			name: "consumers cancelled",
+			mockSetup: func() {
+				mockRepo.EXPECT().SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Value: 5.0}, gomock.Any()).Return(repository.ErrUnknownIDTasksWithDependency)
+			},
+			result:      models.TaskResult{Value: 5.0},
 			expectedErr: services.ErrUnknownTaskID,
 		},
 		{
 			name: "database unavailable",
 			mockSetup: func() {
-				mockRepo.EXPECT().SetTaskResult(gomock.Any(), task, 5.0).Return(repository.ErrDatabaseNotAvailable)
+				mockRepo.EXPECT().SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Value: 5.0}, gomock.Any()).Return(repository.ErrDatabaseNotAvailable)
 			},
-			result:      5.0,
+			result:      models.TaskResult{Value: 5.0},
 			expectedErr: services.ErrDatabaseUnavailable,
 		},
 		{
 			name: "unexpected error",
 			mockSetup: func() {
-				mockRepo.EXPECT().SetTaskResult(gomock.Any(), task, 5.0).Return(errors.New("unexpected error"))
+				mockRepo.EXPECT().SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Value: 5.0}, gomock.Any()).Return(errors.New("unexpected error"))
 			},
-			result:      5.0,
+			result:      models.TaskResult{Value: 5.0},
 			expectedErr: errors.New("unexpected error"),
 		},
 	}
@@ -419,7 +505,7 @@ func TestExpressionTaskService_SetTaskResult(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
-			err := service.SetTaskResult(context.Background(), task, tt.result)
+			err := service.SetTaskResult(context.Background(), taskID, token, tt.result)
 
 			if tt.expectedErr != nil {
 				assert.Error(t, err)
@@ -431,39 +517,317 @@ func TestExpressionTaskService_SetTaskResult(t *testing.T) {
 	}
 }
 
+func TestExpressionTaskService_RenewLeases(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewExpressionTaskService(mockRepo, &services.OperationTimesMS{}, nil, time.Minute)
+
+	held, expired, stolen := uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("reports lost leases", func(t *testing.T) {
+		mockRepo.EXPECT().RenewLeases(gomock.Any(), "agent-1", []uuid.UUID{held, expired, stolen}, time.Minute).
+			Return([]uuid.UUID{held}, nil)
+
+		lost, err := service.RenewLeases(context.Background(), "agent-1", []uuid.UUID{held, expired, stolen})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{expired, stolen}, lost)
+	})
+
+	t.Run("nothing to renew", func(t *testing.T) {
+		lost, err := service.RenewLeases(context.Background(), "agent-1", nil)
+		assert.NoError(t, err)
+		assert.Empty(t, lost)
+	})
+
+	t.Run("database unavailable", func(t *testing.T) {
+		mockRepo.EXPECT().RenewLeases(gomock.Any(), "agent-1", []uuid.UUID{held}, time.Minute).
+			Return(nil, repository.ErrDatabaseNotAvailable)
+
+		_, err := service.RenewLeases(context.Background(), "agent-1", []uuid.UUID{held})
+		assert.Equal(t, services.ErrDatabaseUnavailable, err)
+	})
+}
+
+func TestBuildTasks_ParameterBody(t *testing.T) {
+	functions := map[string]*models.UserFunction{
+		"f": {Name: "f", Params: []string{"x"}, Body: "x"},
+		"p": {Name: "p", Params: []string{"x"}, Body: "(x)"},
+		"g": {Name: "g", Params: []string{"y"}, Body: "f(y)"},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		optimize   bool
+		tasks      int
+	}{
+		{"parameter body", "f(1+2)", false, 1},
+		{"parenthesized parameter body", "p(1+2)", false, 1},
+		{"nested parameter bodies", "g(1+2)", false, 1},
+		{"parameter body with optimization", "f(x*2)", true, 1},
+		{"argument used elsewhere", "f(x*2) + x*2", false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := expr.Parse(tt.expression)
+			assert.NoError(t, err)
+			tasks, result, err := services.BuildTasks(uuid.New(), root, services.BuildOptions{
+				CalculationOptions: services.CalculationOptions{Optimize: tt.optimize},
+				Variables:          map[string]*models.Variable{"x": {Name: "x", Value: 3}},
+				Functions:          functions,
+			})
+			assert.NoError(t, err)
+			assert.Len(t, tasks, tt.tasks)
+
+			var final []*models.Task
+			for _, task := range tasks {
+				if task.FinalTask {
+					final = append(final, task)
+				}
+			}
+			if assert.Len(t, final, 1) {
+				assert.Equal(t, final[0].ID, *result.TaskID)
+			}
+		})
+	}
+}
+
+func TestExpressionTaskService_ReleaseTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewExpressionTaskService(mockRepo, &services.OperationTimesMS{}, nil, time.Minute)
+
+	taskID, token := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name        string
+		task        *pb.Task
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "success",
+			task: &pb.Task{Id: taskID.String(), AssignmentToken: token.String()},
+			mockSetup: func() {
+				mockRepo.EXPECT().ReleaseTask(gomock.Any(), taskID, token).Return(nil)
+			},
+		},
+		{
+			name:        "invalid task id",
+			task:        &pb.Task{Id: "abc", AssignmentToken: token.String()},
+			mockSetup:   func() {},
+			expectedErr: services.ErrUnknownTaskID,
+		},
+		{
+			name:        "missing assignment token",
+			task:        &pb.Task{Id: taskID.String()},
+			mockSetup:   func() {},
+			expectedErr: services.ErrLeaseNotHeld,
+		},
+		{
+			name: "database unavailable",
+			task: &pb.Task{Id: taskID.String(), AssignmentToken: token.String()},
+			mockSetup: func() {
+				mockRepo.EXPECT().ReleaseTask(gomock.Any(), taskID, token).Return(repository.ErrDatabaseNotAvailable)
+			},
+			expectedErr: services.ErrDatabaseUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			err := service.ReleaseTask(context.Background(), tt.task)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestExpressionTaskService_SetTaskResult_NumericPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewExpressionTaskService(mockRepo, &services.OperationTimesMS{}, nil, 30*time.Second)
+
+	tests := []struct {
+		name      string
+		precision models.Precision
+		policy    models.NumericPolicy
+		result    models.TaskResult
+		expected  models.TaskResult
+	}{
+		{
+			name:     "strict rejects infinity",
+			policy:   models.PolicyStrict,
+			result:   models.TaskResult{Value: math.Inf(1)},
+			expected: models.TaskResult{Value: math.Inf(1), Error: services.ErrNonFiniteResult.Error()},
+		},
+		{
+			name:     "ieee keeps infinity",
+			policy:   models.PolicyIEEE,
+			result:   models.TaskResult{Value: math.Inf(-1)},
+			expected: models.TaskResult{Value: math.Inf(-1)},
+		},
+		{
+			name:     "saturate clamps infinity",
+			policy:   models.PolicySaturate,
+			result:   models.TaskResult{Value: math.Inf(1)},
+			expected: models.TaskResult{Value: math.MaxFloat64},
+		},
+		{
+			name:     "agent error is kept",
+			policy:   models.PolicySaturate,
+			result:   models.TaskResult{Value: math.Inf(1), Error: "division by zero"},
+			expected: models.TaskResult{Value: math.Inf(1), Error: "division by zero"},
+		},
+		{
+			name:      "decimal results are exact",
+			precision: models.PrecisionDecimal,
+			policy:    models.PolicyStrict,
+			result:    models.TaskResult{Value: math.Inf(1), Decimal: "1e400"},
+			expected:  models.TaskResult{Value: math.Inf(1), Decimal: "1e400"},
+		},
+		{
+			name:      "decimal result too large",
+			precision: models.PrecisionDecimal,
+			result:    models.TaskResult{Value: math.Inf(1), Decimal: strings.Repeat("9", services.MaxDecimalResultLength+1)},
+			expected:  models.TaskResult{Value: math.Inf(1), Error: services.ErrDecimalTooLarge.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.EXPECT().SetTaskResult(gomock.Any(), gomock.Any(), gomock.Any(), tt.result, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _, _ uuid.UUID, result models.TaskResult, normalize repository.NormalizeResultFunc) error {
+					assert.Equal(t, tt.expected, normalize(tt.precision, tt.policy, result))
+					return nil
+				})
+			assert.NoError(t, service.SetTaskResult(context.Background(), uuid.New(), uuid.New(), tt.result))
+		})
+	}
+}
+
 func TestExpressionTaskService_StartExpiredTaskReset(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	opTimes := &services.OperationTimesMS{}
-	service := services.NewExpressionTaskService(mockRepo, opTimes)
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	interval := 100 * time.Millisecond
-	delay := 10 * time.Second
 
 	t.Run("start and stop", func(t *testing.T) {
 		mockRepo.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-		mockRepo.EXPECT().ResetExpiredTasks(gomock.Any(), delay).Return(nil).AnyTimes()
+		mockRepo.EXPECT().ResetExpiredTasks(gomock.Any()).Return(nil).AnyTimes()
 
-		service.StartExpiredTaskReset(ctx, interval, delay)
+		service.StartExpiredTaskReset(ctx, interval)
 		time.Sleep(interval * 2)
 		cancel()
 	})
 
 	t.Run("database unavailable", func(t *testing.T) {
 		mockRepo.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).Return(repository.ErrDatabaseNotAvailable).AnyTimes()
-		mockRepo.EXPECT().ResetExpiredTasks(gomock.Any(), delay).Return(repository.ErrDatabaseNotAvailable).AnyTimes()
+		mockRepo.EXPECT().ResetExpiredTasks(gomock.Any()).Return(repository.ErrDatabaseNotAvailable).AnyTimes()
 
-		service.StartExpiredTaskReset(ctx, interval, delay)
+		service.StartExpiredTaskReset(ctx, interval)
 		time.Sleep(interval * 2)
 		cancel()
 	})
 }
 
+func TestExpressionTaskService_WaitTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewExpressionTaskService(mockRepo, &services.OperationTimesMS{}, nil, 30*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.StartTaskDispatch(ctx)
+
+	t.Run("idle agent waits for ready task", func(t *testing.T) {
+		task := &pb.Task{Id: uuid.New().String(), Operator: "+"}
+		idle := make(chan struct{})
+		gomock.InOrder(
+			mockRepo.EXPECT().GetTask(gomock.Any(), "agent-1", 30*time.Second, gomock.Any()).DoAndReturn(
+				func(context.Context, string, time.Duration, func(string) *timestamppb.Timestamp) (*pb.Task, error) {
+					close(idle)
+					return nil, nil
+				}),
+			mockRepo.EXPECT().SetTaskResult(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+			mockRepo.EXPECT().GetTask(gomock.Any(), "agent-1", 30*time.Second, gomock.Any()).Return(task, nil),
+		)
+
+		result := make(chan *pb.Task)
+		go func() {
+			received, err := service.WaitTask(context.Background(), "agent-1")
+			assert.NoError(t, err)
+			result <- received
+		}()
+
+		<-idle
+		assert.NoError(t, service.SetTaskResult(context.Background(), uuid.New(), uuid.New(), models.TaskResult{}))
+		select {
+		case received := <-result:
+			assert.Equal(t, task.Id, received.Id)
+			assert.NotNil(t, received.OperationTime)
+		case <-time.After(time.Second):
+			t.Fatal("task was not dispatched")
+		}
+	})
+
+	t.Run("cancelled wait", func(t *testing.T) {
+		mockRepo.EXPECT().GetTask(gomock.Any(), "agent-2", 30*time.Second, gomock.Any()).Return(nil, nil).AnyTimes()
+
+		waitCtx, waitCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer waitCancel()
+		task, err := service.WaitTask(waitCtx, "agent-2")
+		assert.Nil(t, task)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("task fetched for a gone agent is released", func(t *testing.T) {
+		task := &pb.Task{Id: uuid.New().String(), AssignmentToken: uuid.New().String()}
+		gone := make(chan struct{})
+		released := make(chan struct{})
+		mockRepo.EXPECT().GetTask(gomock.Any(), "agent-3", 30*time.Second, gomock.Any()).DoAndReturn(
+			func(context.Context, string, time.Duration, func(string) *timestamppb.Timestamp) (*pb.Task, error) {
+				<-gone
+				return task, nil
+			})
+		mockRepo.EXPECT().ReleaseTask(gomock.Any(), uuid.MustParse(task.Id), uuid.MustParse(task.AssignmentToken)).DoAndReturn(
+			func(context.Context, uuid.UUID, uuid.UUID) error {
+				close(released)
+				return nil
+			})
+		mockRepo.EXPECT().GetTask(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+		waitCtx, waitCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer waitCancel()
+		received, err := service.WaitTask(waitCtx, "agent-3")
+		assert.Nil(t, received)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		close(gone)
+
+		select {
+		case <-released:
+		case <-time.After(time.Second):
+			t.Fatal("task was not released")
+		}
+	})
+}
+
 func TestValidateExpression(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -472,15 +836,57 @@ func TestValidateExpression(t *testing.T) {
 	}{
 		{"valid expression", "2+2", nil},
 		{"empty expression", "", services.ErrEmptyExpression},
-		{"invalid characters", "2+a", services.ErrInvalidCharacter},
+		{"invalid characters", "2+$", services.ErrInvalidCharacter},
 		{"division by zero", "2/0", services.ErrDivisionByZero},
 		{"missing operator", "22", services.ErrMissingOperator},
 		{"invalid parentheses", "(2+2", services.ErrParenthesisIssue},
 		{"invalid number format", "2.2.2+3", services.ErrNumberFormatIssue},
-		{"consecutive operators", "2++2", services.ErrOperatorIssue},
-		{"invalid start with operator", "+2+2", services.ErrInvalidExpressionStartEnd},
+		{"consecutive operators", "2*/2", services.ErrOperatorIssue},
+		{"invalid start with operator", "*2+2", services.ErrInvalidExpressionStartEnd},
 		{"invalid end with operator", "2+2+", services.ErrInvalidExpressionStartEnd},
-		{"unary operator not supported", "(-2)+3", services.ErrUnaryOperatorNotSupported},
+		{"unary minus in parentheses", "(-2)+3", nil},
+		{"unary minus at start", "-5+3", nil},
+		{"unary minus after operator", "2*-4", nil},
+		{"unary minus before parentheses", "-(2+3)", nil},
+		{"unary plus", "+2+2", nil},
+		{"double sign", "2++2", nil},
+		{"only unary minus", "-5", nil},
+		{"only unary plus", "+5", services.ErrMissingOperator},
+		{"unary minus without operand", "2*-", services.ErrInvalidExpressionStartEnd},
+		{"negative zero divisor", "2/-0", services.ErrDivisionByZero},
+		{"exponentiation", "2^10", nil},
+		{"double star exponentiation", "2**10", nil},
+		{"triple star", "2***10", services.ErrOperatorIssue},
+		{"exponentiation without exponent", "2^", services.ErrInvalidExpressionStartEnd},
+		{"function call", "sqrt(2)", nil},
+		{"nested function calls", "max(1, min(2, 3), abs(-4)) + log(8, 2)", nil},
+		{"function in expression", "2*sin(3.14/2)", nil},
+		{"unknown function", "foo(2)", services.ErrUnknownFunction},
+		{"variable reference", "2+a", nil},
+		{"variable alone", "(a)", services.ErrMissingOperator},
+		{"function without parentheses", "sqrt+2", services.ErrFunctionCallIssue},
+		{"number before function", "2sqrt(4)", services.ErrFunctionCallIssue},
+		{"too many arguments", "sqrt(2, 3)", services.ErrFunctionArgumentCount},
+		{"too few arguments", "log()", services.ErrFunctionArgumentCount},
+		{"separator outside function", "(1, 2)+3", services.ErrSeparatorIssue},
+		{"trailing separator", "max(1,)", services.ErrSeparatorIssue},
+		{"leading separator", "max(,1)", services.ErrSeparatorIssue},
+		{"operator after separator", "max(1,*2)", services.ErrOperatorIssue},
+		{"comparison", "a<=2", nil},
+		{"logical operators", "!(a==1) || b!=2 && c>0", nil},
+		{"conditional", "a>0 ? a : -a", nil},
+		{"conditional function", "if(a, 1, 2)", nil},
+		{"conditional without alternative", "a>0 ? 1", services.ErrConditionalIssue},
+		{"conditional function arity", "if(a, 1)", services.ErrFunctionArgumentCount},
+		{"negated zero is not a zero divisor", "1/!0", nil},
+		{"modulo", "17 % 5", nil},
+		{"bitwise operators", "(a & 0xFF) | b xor c << 2 >> 1", nil},
+		{"integer division", "a // 2", nil},
+		{"integer division by zero", "a // 0", services.ErrDivisionByZero},
+		{"modulo by zero", "a % -0", services.ErrDivisionByZero},
+		{"fractional modulo operand", "7.5 % 2", services.ErrIntegerOperandRequired},
+		{"fractional shift", "1 << -0.5", services.ErrIntegerOperandRequired},
+		{"exponent literal is an integer", "1e3 & 0x0F", nil},
 	}
 
 	for _, tt := range tests {
@@ -488,7 +894,7 @@ func TestValidateExpression(t *testing.T) {
 			err := services.ValidateExpression(tt.expression)
 			if tt.expectedErr != nil {
 				assert.Error(t, err)
-				assert.Equal(t, tt.expectedErr, err)
+				assert.ErrorIs(t, err, tt.expectedErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -507,6 +913,28 @@ func TestInfixToPostfix(t *testing.T) {
 		{"operator precedence", "2+3*4", []string{"2", "3", "4", "*", "+"}},
 		{"complex expression", "3+4*2/(1-5)", []string{"3", "4", "2", "*", "1", "5", "-", "/", "+"}},
 		{"decimal numbers", "2.5+3.7", []string{"2.5", "3.7", "+"}},
+		{"unary minus at start", "-5+3", []string{"5", "neg", "3", "+"}},
+		{"unary minus after operator", "2*-4", []string{"2", "4", "neg", "*"}},
+		{"unary minus in parentheses", "2*(-4)", []string{"2", "4", "neg", "*"}},
+		{"unary minus before parentheses", "-(2+3)*4", []string{"2", "3", "+", "neg", "4", "*"}},
+		{"double unary minus", "--2", []string{"2", "neg", "neg"}},
+		{"unary plus is dropped", "+2-+3", []string{"2", "3", "-"}},
+		{"exponentiation precedence", "2*3^2", []string{"2", "3", "2", "^", "*"}},
+		{"exponentiation right associativity", "2^3^2", []string{"2", "3", "2", "^", "^"}},
+		{"double star exponentiation", "2**3**2", []string{"2", "3", "2", "^", "^"}},
+		{"exponentiation binds tighter than unary minus", "-2^2", []string{"2", "2", "^", "neg"}},
+		{"negative exponent", "2^-3", []string{"2", "3", "neg", "^"}},
+		{"left associativity is kept", "8-4-2", []string{"8", "4", "-", "2", "-"}},
+		{"single argument function", "sqrt(4)+1", []string{"4", "sqrt:1", "1", "+"}},
+		{"variadic function", "max(1,2*3,4)", []string{"1", "2", "3", "*", "4", "max:3"}},
+		{"nested functions", "log(max(1,2),2)", []string{"1", "2", "max:2", "2", "log:2"}},
+		{"negated function", "-abs(-3)", []string{"3", "neg", "abs:1", "neg"}},
+		{"logical not", "!a&&b", []string{"a", "!", "b", "&&"}},
+		{"comparison below arithmetic", "1+2<=3", []string{"1", "2", "+", "3", "<="}},
+		{"conditional", "a<b?1:2", []string{"a", "b", "<", "1", "2", "?:"}},
+		{"modulo binds like multiplication", "1+7%3*2", []string{"1", "7", "3", "%", "2", "*", "+"}},
+		{"shift below addition", "1<<2+3", []string{"1", "2", "3", "+", "<<"}},
+		{"xor between and and or", "a|b xor c&d", []string{"a", "b", "c", "d", "&", "xor", "|"}},
 	}
 
 	for _, tt := range tests {
@@ -523,12 +951,22 @@ func TestGetOperationEndTime(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	opTimes := &services.OperationTimesMS{
-		Addition:       100 * time.Millisecond,
-		Subtraction:    150 * time.Millisecond,
-		Multiplication: 200 * time.Millisecond,
-		Division:       250 * time.Millisecond,
+		Addition:        100 * time.Millisecond,
+		Subtraction:     150 * time.Millisecond,
+		Multiplication:  200 * time.Millisecond,
+		Division:        250 * time.Millisecond,
+		Exponentiation:  300 * time.Millisecond,
+		Function:        350 * time.Millisecond,
+		Comparison:      400 * time.Millisecond,
+		Modulo:          450 * time.Millisecond,
+		IntegerDivision: 500 * time.Millisecond,
+		BitwiseAnd:      550 * time.Millisecond,
+		BitwiseOr:       600 * time.Millisecond,
+		BitwiseXor:      650 * time.Millisecond,
+		ShiftLeft:       700 * time.Millisecond,
+		ShiftRight:      750 * time.Millisecond,
 	}
-	service := services.NewExpressionTaskService(mockRepo, opTimes)
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
 
 	tests := []struct {
 		name     string
@@ -538,6 +976,18 @@ func TestGetOperationEndTime(t *testing.T) {
 		{"subtraction", "-"},
 		{"multiplication", "*"},
 		{"division", "/"},
+		{"negation", services.NegationOperator},
+		{"exponentiation", "^"},
+		{"function", "sqrt"},
+		{"comparison", "<="},
+		{"logical not", "!"},
+		{"modulo", "%"},
+		{"integer division", "//"},
+		{"bitwise and", "&"},
+		{"bitwise or", "|"},
+		{"bitwise xor", "xor"},
+		{"shift left", "<<"},
+		{"shift right", ">>"},
 		{"invalid operator", "?"},
 	}
 
@@ -567,7 +1017,6 @@ func TestIsExpressionError(t *testing.T) {
 		{"operator issue", services.ErrOperatorIssue, true},
 		{"division by zero", services.ErrDivisionByZero, true},
 		{"invalid start/end", services.ErrInvalidExpressionStartEnd, true},
-		{"unary operator", services.ErrUnaryOperatorNotSupported, true},
 		{"invalid expression", services.ErrInvalidExpression, true},
 		{"other error", errors.New("other error"), false},
 	}
@@ -586,13 +1035,12 @@ func TestStartExpiredTaskReset_ErrorHandling(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	opTimes := &services.OperationTimesMS{}
-	service := services.NewExpressionTaskService(mockRepo, opTimes)
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	interval := 10 * time.Millisecond
-	delay := 1 * time.Second
 
 	t.Run("continue on ErrDatabaseNotAvailable", func(t *testing.T) {
 		mockRepo.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
@@ -600,9 +1048,9 @@ func TestStartExpiredTaskReset_ErrorHandling(t *testing.T) {
 				return fn(ctx, nil)
 			},
 		).AnyTimes()
-		mockRepo.EXPECT().ResetExpiredTasks(gomock.Any(), delay).Return(repository.ErrDatabaseNotAvailable).AnyTimes()
+		mockRepo.EXPECT().ResetExpiredTasks(gomock.Any()).Return(repository.ErrDatabaseNotAvailable).AnyTimes()
 
-		service.StartExpiredTaskReset(ctx, interval, delay)
+		service.StartExpiredTaskReset(ctx, interval)
 		time.Sleep(interval * 2)
 	})
 
@@ -613,26 +1061,130 @@ func TestStartExpiredTaskReset_ErrorHandling(t *testing.T) {
 				return fn(ctx, nil)
 			},
 		).AnyTimes()
-		mockRepo.EXPECT().ResetExpiredTasks(gomock.Any(), delay).Return(expectedErr).AnyTimes()
+		mockRepo.EXPECT().ResetExpiredTasks(gomock.Any()).Return(expectedErr).AnyTimes()
 
-		service.StartExpiredTaskReset(ctx, interval, delay)
+		service.StartExpiredTaskReset(ctx, interval)
 		time.Sleep(interval * 2)
 	})
 }
 
+func TestCreateExpressionTask_UnaryMinus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	opTimes := &services.OperationTimesMS{}
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
+
+	userID := uuid.New()
+
+	t.Run("literal negative is folded into operand", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 1)
+				assert.Equal(t, "+", tasks[0].Operator)
+				assert.Equal(t, -5.0, tasks[0].Args[0].Value)
+				assert.Equal(t, 3.0, tasks[0].Args[1].Value)
+				assert.True(t, tasks[0].FinalTask)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "-5+3", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("negation of subexpression creates neg task", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 2)
+				assert.Equal(t, "+", tasks[0].Operator)
+				assert.Equal(t, services.NegationOperator, tasks[1].Operator)
+				assert.Equal(t, tasks[0].ID, *tasks[1].Args[0].TaskID)
+				assert.True(t, tasks[1].FinalTask)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "-(2+3)", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("standalone negative number creates final neg task", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 1)
+				assert.Equal(t, services.NegationOperator, tasks[0].Operator)
+				assert.Equal(t, 5.0, tasks[0].Args[0].Value)
+				assert.True(t, tasks[0].FinalTask)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "-5", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestCreateExpressionTask_NumericLiterals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	opTimes := &services.OperationTimesMS{}
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
+
+	mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+			assert.Equal(t, "0x1F+1_000*2.5e-3", expression.Expression)
+			assert.Len(t, tasks, 2)
+			assert.Equal(t, 1000.0, tasks[0].Args[0].Value)
+			assert.Equal(t, 0.0025, tasks[0].Args[1].Value)
+			assert.Equal(t, 31.0, tasks[1].Args[0].Value)
+			return nil
+		})
+
+	_, err := service.CreateExpressionTask(context.Background(), uuid.New(), " 0x1F + 1_000 * 2.5e-3 ", services.CalculationOptions{})
+	assert.NoError(t, err)
+}
+
+func TestCreateExpressionTask_Functions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	opTimes := &services.OperationTimesMS{}
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
+
+	userID := uuid.New()
+
+	mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+			assert.Len(t, tasks, 2)
+			assert.Equal(t, "+", tasks[0].Operator)
+			assert.Equal(t, "max", tasks[1].Operator)
+			assert.Len(t, tasks[1].Args, 3)
+			assert.Equal(t, 1.0, tasks[1].Args[0].Value)
+			assert.Equal(t, tasks[0].ID, *tasks[1].Args[1].TaskID)
+			assert.Equal(t, 4.0, tasks[1].Args[2].Value)
+			assert.True(t, tasks[1].FinalTask)
+			return nil
+		})
+
+	_, err := service.CreateExpressionTask(context.Background(), userID, "max(1, 2+3, 4)", services.CalculationOptions{})
+	assert.NoError(t, err)
+}
+
 func TestCreateExpressionTask_InvalidNumberParsing(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	opTimes := &services.OperationTimesMS{}
-	service := services.NewExpressionTaskService(mockRepo, opTimes)
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
 
 	userID := uuid.New()
 
-	_, err := service.CreateExpressionTask(context.Background(), userID, "2..3+4")
+	_, err := service.CreateExpressionTask(context.Background(), userID, "2..3+4", services.CalculationOptions{})
 	assert.Error(t, err)
-	assert.Equal(t, services.ErrNumberFormatIssue, err)
+	assert.ErrorIs(t, err, services.ErrNumberFormatIssue)
 }
 
 func TestValidateExpression_ParenthesisAfterOperator(t *testing.T) {
@@ -649,7 +1201,7 @@ func TestValidateExpression_ParenthesisAfterOperator(t *testing.T) {
 		{
 			name:        "number before opening parenthesis",
 			expression:  "2(",
-			expectedErr: services.ErrMissingOperator,
+			expectedErr: services.ErrParenthesisIssue,
 		},
 		{
 			name:        "valid operator before opening parenthesis",
@@ -662,7 +1214,7 @@ func TestValidateExpression_ParenthesisAfterOperator(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			err := services.ValidateExpression(tt.expression)
 			assert.Error(t, err)
-			assert.Equal(t, tt.expectedErr, err)
+			assert.ErrorIs(t, err, tt.expectedErr)
 		})
 	}
 }
@@ -695,7 +1247,7 @@ func TestValidateExpression_BalanceAndAfterParenthesis(t *testing.T) {
 			err := services.ValidateExpression(tt.expression)
 			if tt.expectedErr != nil {
 				assert.Error(t, err)
-				assert.Equal(t, tt.expectedErr, err)
+				assert.ErrorIs(t, err, tt.expectedErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -729,6 +1281,21 @@ func TestValidateExpression_NumberFormat(t *testing.T) {
 			expression:  "0.2+3",
 			expectedErr: nil,
 		},
+		{
+			name:        "exponent notation",
+			expression:  "6.02E23*1e-3",
+			expectedErr: nil,
+		},
+		{
+			name:        "non-decimal literals with separators",
+			expression:  "0x1F+0b1_010-0o17",
+			expectedErr: nil,
+		},
+		{
+			name:        "misplaced digit separator",
+			expression:  "1__000+1",
+			expectedErr: services.ErrNumberFormatIssue,
+		},
 	}
 
 	for _, tt := range tests {
@@ -736,7 +1303,7 @@ func TestValidateExpression_NumberFormat(t *testing.T) {
 			err := services.ValidateExpression(tt.expression)
 			if tt.expectedErr != nil {
 				assert.Error(t, err)
-				assert.Equal(t, tt.expectedErr, err)
+				assert.ErrorIs(t, err, tt.expectedErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -752,12 +1319,12 @@ func TestValidateExpression_ConsecutiveOperators(t *testing.T) {
 	}{
 		{
 			name:        "consecutive operators",
-			expression:  "2++3",
+			expression:  "2+*3",
 			expectedErr: services.ErrOperatorIssue,
 		},
 		{
 			name:        "operator at start",
-			expression:  "+2+3",
+			expression:  "/2+3",
 			expectedErr: services.ErrInvalidExpressionStartEnd,
 		},
 		{
@@ -784,8 +1351,8 @@ func TestValidateExpression_ClosingParenthesisAfterOperator(t *testing.T) {
 	}{
 		{
 			name:        "closing parenthesis after operator",
-			expression:  "(+)",
-			expectedErr: services.ErrUnaryOperatorNotSupported,
+			expression:  "(2+)",
+			expectedErr: services.ErrOperatorIssue,
 		},
 		{
 			name:        "empty parentheses",
@@ -798,7 +1365,970 @@ func TestValidateExpression_ClosingParenthesisAfterOperator(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			err := services.ValidateExpression(tt.expression)
 			assert.Error(t, err)
-			assert.Equal(t, tt.expectedErr, err)
+			assert.ErrorIs(t, err, tt.expectedErr)
 		})
 	}
 }
+
+func TestValidateExpression_ErrorPosition(t *testing.T) {
+	tests := []struct {
+		name        string
+		expression  string
+		expectedErr error
+		pos         int
+		token       string
+	}{
+		{"division by zero", "1+4/(0)", services.ErrDivisionByZero, 4, "0"},
+		{"unknown function", "2+foo(1)", services.ErrUnknownFunction, 2, "foo"},
+		{"function without call", "2+sqrt", services.ErrFunctionCallIssue, 2, "sqrt"},
+		{"wrong argument count", "1+sqrt(1,2)", services.ErrFunctionArgumentCount, 2, "sqrt"},
+		{"single number", "(42)", services.ErrMissingOperator, 0, "(42)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := services.ValidateExpression(tt.expression)
+			assert.ErrorIs(t, err, tt.expectedErr)
+
+			var exprErr *expr.Error
+			if assert.ErrorAs(t, err, &exprErr) {
+				assert.Equal(t, tt.pos, exprErr.Pos)
+				assert.Equal(t, tt.token, exprErr.Token)
+			}
+		})
+	}
+}
+
+func TestDescribeExpressionError(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		expected   *services.ExpressionErrorDetails
+	}{
+		{
+			name:       "misplaced operator",
+			expression: "2+*3",
+			expected: &services.ExpressionErrorDetails{
+				Code:     "MISPLACED_OPERATOR",
+				Message:  services.ErrOperatorIssue.Error(),
+				Start:    2,
+				End:      3,
+				Token:    "*",
+				Expected: []string{"number", "identifier", "("},
+			},
+		},
+		{
+			name:       "unclosed parenthesis",
+			expression: "2*(3+4",
+			expected: &services.ExpressionErrorDetails{
+				Code:     "PARENTHESIS_MISMATCH",
+				Message:  services.ErrParenthesisIssue.Error(),
+				Start:    2,
+				End:      3,
+				Token:    "(",
+				Expected: []string{"operator", ")"},
+			},
+		},
+		{
+			name:       "division by zero",
+			expression: "7/0",
+			expected: &services.ExpressionErrorDetails{
+				Code:    "DIVISION_BY_ZERO",
+				Message: services.ErrDivisionByZero.Error(),
+				Start:   2,
+				End:     3,
+				Token:   "0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := services.ValidateExpression(tt.expression)
+			assert.Equal(t, tt.expected, services.DescribeExpressionError(err, tt.expression))
+		})
+	}
+}
+
+func TestDescribeExpressionError_WithoutPosition(t *testing.T) {
+	details := services.DescribeExpressionError(services.ErrInvalidExpression, "2+2")
+	assert.Equal(t, &services.ExpressionErrorDetails{
+		Code:    "INVALID_EXPRESSION",
+		Message: services.ErrInvalidExpression.Error(),
+		Start:   0,
+		End:     3,
+	}, details)
+
+	assert.Nil(t, services.DescribeExpressionError(services.ErrUnknownUserID, "2+2"))
+}
+
+func TestCreateExpressionTask_DecimalPrecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	opTimes := &services.OperationTimesMS{}
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
+
+	userID := uuid.New()
+	decimal := services.CalculationOptions{Precision: models.PrecisionDecimal, Scale: 4}
+
+	t.Run("operands carry exact decimal strings", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, models.PrecisionDecimal, expression.Precision)
+				assert.Equal(t, 4, expression.Scale)
+				assert.Len(t, tasks, 2)
+				assert.Equal(t, "0.1", tasks[0].Args[0].Decimal)
+				assert.Equal(t, "-0.2", tasks[0].Args[1].Decimal)
+				assert.Equal(t, "1000", tasks[1].Args[1].Decimal)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "(0.1*-0.2)+1e3", decimal)
+		assert.NoError(t, err)
+	})
+
+	t.Run("float precision by default", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, models.PrecisionFloat, expression.Precision)
+				assert.Equal(t, 0, expression.Scale)
+				assert.Empty(t, tasks[0].Args[0].Decimal)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "0.1+0.2", services.CalculationOptions{Scale: 5})
+		assert.NoError(t, err)
+	})
+
+	errorTests := []struct {
+		name        string
+		expression  string
+		options     services.CalculationOptions
+		expectedErr error
+	}{
+		{"unknown precision", "1+2", services.CalculationOptions{Precision: "double"}, services.ErrUnknownPrecision},
+		{"negative scale", "1+2", services.CalculationOptions{Precision: models.PrecisionDecimal, Scale: -1}, services.ErrInvalidScale},
+		{"scale too large", "1+2", services.CalculationOptions{Precision: models.PrecisionDecimal, Scale: 101}, services.ErrInvalidScale},
+		{"transcendental function", "1+sin(2)", decimal, services.ErrDecimalUnsupported},
+		{"fractional exponent", "2^0.5", decimal, services.ErrDecimalUnsupported},
+		{"literal too long", "1+0." + strings.Repeat("9", services.MaxDecimalLiteralLength), decimal, services.ErrDecimalTooLarge},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateExpressionTask(context.Background(), userID, tt.expression, tt.options)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestCreateExpressionTask_Variables(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	opTimes := &services.OperationTimesMS{}
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
+
+	userID := uuid.New()
+	variables := []*models.Variable{
+		{UserID: userID, Name: "rate", Value: 0.05, Decimal: "0.05"},
+		{UserID: userID, Name: "principal", Value: 1000, Decimal: "1000"},
+		{UserID: userID, Name: "unused", Value: 1, Decimal: "1"},
+	}
+
+	t.Run("identifiers are replaced by snapshotted values", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, "rate*principal- -rate", expression.Expression)
+				assert.Equal(t, map[string]string{"rate": "0.05", "principal": "1000"}, expression.Variables)
+				assert.Len(t, tasks, 2)
+				assert.Equal(t, 0.05, tasks[0].Args[0].Value)
+				assert.Equal(t, 1000.0, tasks[0].Args[1].Value)
+				assert.Equal(t, -0.05, tasks[1].Args[1].Value)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "rate * principal - -rate", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("decimal mode uses exact variable values", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, "0.05", tasks[0].Args[0].Decimal)
+				return nil
+			})
+
+		options := services.CalculationOptions{Precision: models.PrecisionDecimal, Scale: 2}
+		_, err := service.CreateExpressionTask(context.Background(), userID, "rate*2", options)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown variable", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "rate*amount", services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrUnknownVariable)
+
+		var exprErr *expr.Error
+		if assert.ErrorAs(t, err, &exprErr) {
+			assert.Equal(t, 5, exprErr.Pos)
+			assert.Equal(t, "amount", exprErr.Token)
+		}
+	})
+
+	t.Run("database unavailable", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(nil, repository.ErrDatabaseNotAvailable)
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "rate*2", services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrDatabaseUnavailable)
+	})
+
+	t.Run("expressions without identifiers skip the lookup", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "2*3", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestCreateExpressionTask_UserFunctions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	opTimes := &services.OperationTimesMS{}
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
+
+	userID := uuid.New()
+	functions := []*models.UserFunction{
+		{UserID: userID, Name: "f", Params: []string{"x", "y"}, Body: "x*x+y"},
+		{UserID: userID, Name: "g", Params: []string{"x"}, Body: "f(x, 1)*2"},
+		{UserID: userID, Name: "trig", Params: []string{"x"}, Body: "sin(x)+1"},
+		{UserID: userID, Name: "broken", Params: []string{"x"}, Body: "missing(x)+1"},
+	}
+
+	t.Run("parameters are substituted and arguments computed once", func(t *testing.T) {
+		mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(functions, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, "f(2+3,4)", expression.Expression)
+				assert.Len(t, tasks, 3)
+				assert.Equal(t, "+", tasks[0].Operator)
+				assert.Equal(t, "*", tasks[1].Operator)
+				assert.Equal(t, tasks[0].ID, *tasks[1].Args[0].TaskID)
+				assert.Equal(t, tasks[0].ID, *tasks[1].Args[1].TaskID)
+				assert.Equal(t, "+", tasks[2].Operator)
+				assert.Equal(t, tasks[1].ID, *tasks[2].Args[0].TaskID)
+				assert.Equal(t, 4.0, tasks[2].Args[1].Value)
+				assert.True(t, tasks[2].FinalTask)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "f(2+3, 4)", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("nested user functions", func(t *testing.T) {
+		mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(functions, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 4)
+				assert.Equal(t, []string{"*", "+", "*", "-"}, []string{tasks[0].Operator, tasks[1].Operator, tasks[2].Operator, tasks[3].Operator})
+				assert.Equal(t, 3.0, tasks[0].Args[0].Value)
+				assert.True(t, tasks[3].FinalTask)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "g(3)-1", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("unused arguments do not create tasks", func(t *testing.T) {
+		unused := []*models.UserFunction{{UserID: userID, Name: "first", Params: []string{"a", "b"}, Body: "a*2"}}
+		mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(unused, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 1)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "first(1, 2+3)", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("wrong argument count", func(t *testing.T) {
+		mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(functions, nil)
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "f(1)+1", services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrFunctionArgumentCount)
+	})
+
+	t.Run("errors inside a body are reported at the call site", func(t *testing.T) {
+		mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(functions, nil)
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "1+broken(2)", services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrUnknownFunction)
+
+		var exprErr *expr.Error
+		if assert.ErrorAs(t, err, &exprErr) {
+			assert.Equal(t, 2, exprErr.Pos)
+			assert.Equal(t, 11, exprErr.End)
+			assert.Equal(t, "broken", exprErr.Token)
+		}
+	})
+
+	t.Run("decimal mode checks function bodies", func(t *testing.T) {
+		mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(functions, nil)
+
+		options := services.CalculationOptions{Precision: models.PrecisionDecimal, Scale: 2}
+		_, err := service.CreateExpressionTask(context.Background(), userID, "trig(1)", options)
+		assert.ErrorIs(t, err, services.ErrDecimalUnsupported)
+	})
+
+	t.Run("recursion introduced after definition", func(t *testing.T) {
+		cycle := []*models.UserFunction{
+			{UserID: userID, Name: "a", Params: []string{"x"}, Body: "b(x)+1"},
+			{UserID: userID, Name: "b", Params: []string{"x"}, Body: "a(x)+1"},
+		}
+		mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(cycle, nil)
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "a(1)", services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrRecursiveFunction)
+	})
+
+	t.Run("same function in arguments is not recursion", func(t *testing.T) {
+		mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(functions, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "f(f(1, 2), 3)", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestCreateExpressionTask_Optimize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	opTimes := &services.OperationTimesMS{}
+	service := services.NewExpressionTaskService(mockRepo, opTimes, services.FoldableOperators, 30*time.Second)
+
+	userID := uuid.New()
+	variables := []*models.Variable{
+		{UserID: userID, Name: "a", Value: 1, Decimal: "1"},
+		{UserID: userID, Name: "b", Value: 2, Decimal: "2"},
+	}
+	optimize := services.CalculationOptions{Optimize: true}
+
+	t.Run("literal subtrees are folded", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, models.Pending, expression.Status)
+				assert.Len(t, tasks, 2)
+				assert.Equal(t, "log", tasks[0].Operator)
+				assert.Equal(t, -1.0, tasks[0].Args[0].Value)
+				assert.Equal(t, "+", tasks[1].Operator)
+				assert.Equal(t, 6.0, tasks[1].Args[0].Value)
+				assert.Nil(t, tasks[1].Args[0].TaskID)
+				assert.True(t, tasks[1].FinalTask)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "2*3+log(-a)", optimize)
+		assert.NoError(t, err)
+	})
+
+	t.Run("identical subtrees share one task", func(t *testing.T) {
+		noFolding := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 2)
+				assert.Equal(t, "+", tasks[0].Operator)
+				assert.Equal(t, "*", tasks[1].Operator)
+				assert.Equal(t, tasks[0].ID, *tasks[1].Args[0].TaskID)
+				assert.Equal(t, tasks[0].ID, *tasks[1].Args[1].TaskID)
+				return nil
+			})
+
+		_, err := noFolding.CreateExpressionTask(context.Background(), userID, "(a+b)*(a+b)", optimize)
+		assert.NoError(t, err)
+	})
+
+	t.Run("without the flag every operator becomes a task", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 3)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "(a+b)*(a+b)", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("fully folded expression is completed immediately", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Empty(t, tasks)
+				assert.Equal(t, models.Done, expression.Status)
+				assert.Equal(t, 14.0, expression.Result)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "2+3*4", optimize)
+		assert.NoError(t, err)
+	})
+
+	t.Run("decimal folding rounds like the agent", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Empty(t, tasks)
+				assert.Equal(t, "0.33", expression.DecimalResult)
+				assert.Equal(t, 0.33, expression.Result)
+				return nil
+			})
+
+		options := services.CalculationOptions{Precision: models.PrecisionDecimal, Scale: 2, Optimize: true}
+		_, err := service.CreateExpressionTask(context.Background(), userID, "1/3", options)
+		assert.NoError(t, err)
+	})
+
+	t.Run("operations that would fail are left to the agent", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 1)
+				assert.Equal(t, "/", tasks[0].Operator)
+				assert.Equal(t, 0.0, tasks[0].Args[1].Value)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "1/(2-2)", optimize)
+		assert.NoError(t, err)
+	})
+
+	t.Run("only configured operators are folded", func(t *testing.T) {
+		restricted := services.NewExpressionTaskService(mockRepo, opTimes, []string{"+"}, 30*time.Second)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 2)
+				assert.Equal(t, "*", tasks[0].Operator)
+				assert.Equal(t, 3.0, tasks[0].Args[1].Value)
+				return nil
+			})
+
+		_, err := restricted.CreateExpressionTask(context.Background(), userID, "2*(1+2)-1", optimize)
+		assert.NoError(t, err)
+	})
+}
+
+func TestCreateExpressionTask_Conditional(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	opTimes := &services.OperationTimesMS{}
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
+
+	userID := uuid.New()
+	variables := []*models.Variable{
+		{UserID: userID, Name: "a", Value: 1, Decimal: "1"},
+		{UserID: userID, Name: "b", Value: 2, Decimal: "2"},
+	}
+
+	t.Run("branches are attached to a conditional task", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, "a>0?a*2:a+b", expression.Expression)
+				assert.Len(t, tasks, 4)
+				assert.Equal(t, ">", tasks[0].Operator)
+				assert.Nil(t, tasks[0].BranchOf)
+
+				conditional := tasks[1]
+				assert.Equal(t, models.ConditionalOperator, conditional.Operator)
+				assert.True(t, conditional.FinalTask)
+				assert.Nil(t, conditional.BranchOf)
+				assert.Equal(t, tasks[0].ID, *conditional.Args[0].TaskID)
+
+				assert.Equal(t, "*", tasks[2].Operator)
+				assert.Equal(t, conditional.ID, *tasks[2].BranchOf)
+				assert.True(t, tasks[2].ThenBranch)
+				assert.False(t, tasks[2].FinalTask)
+				assert.Equal(t, tasks[2].ID, *conditional.Args[1].TaskID)
+
+				assert.Equal(t, "+", tasks[3].Operator)
+				assert.Equal(t, conditional.ID, *tasks[3].BranchOf)
+				assert.False(t, tasks[3].ThenBranch)
+				assert.Equal(t, tasks[3].ID, *conditional.Args[2].TaskID)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "a > 0 ? a*2 : a+b", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("literal condition selects a branch immediately", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 1)
+				assert.Equal(t, "+", tasks[0].Operator)
+				assert.Nil(t, tasks[0].BranchOf)
+				assert.True(t, tasks[0].FinalTask)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "if(0, 2*3, 4+5)", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("nested conditionals", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 4)
+				outer, inner := tasks[1], tasks[3]
+				assert.Equal(t, models.ConditionalOperator, outer.Operator)
+				assert.Equal(t, "<", tasks[2].Operator)
+				assert.Equal(t, outer.ID, *tasks[2].BranchOf)
+				assert.Equal(t, models.ConditionalOperator, inner.Operator)
+				assert.Equal(t, outer.ID, *inner.BranchOf)
+				assert.False(t, inner.FinalTask)
+				assert.Equal(t, inner.ID, *outer.Args[1].TaskID)
+				assert.Equal(t, 3.0, outer.Args[2].Value)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "a<b ? (b<3 ? 1 : 2) : 3", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("shared subexpressions are not reused across branches", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 7)
+				sum, thenDiff, product, elseDiff, quotient := tasks[0], tasks[3], tasks[4], tasks[5], tasks[6]
+				assert.Equal(t, sum.ID, *product.Args[0].TaskID)
+				assert.Equal(t, thenDiff.ID, *product.Args[1].TaskID)
+				assert.Equal(t, "-", elseDiff.Operator)
+				assert.False(t, elseDiff.ThenBranch)
+				assert.Equal(t, elseDiff.ID, *quotient.Args[0].TaskID)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "(a+b)>2 ? (a+b)*(a-b) : (a-b)/2", services.CalculationOptions{Optimize: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("logical not is a task", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 2)
+				assert.Equal(t, "==", tasks[0].Operator)
+				assert.Equal(t, "!", tasks[1].Operator)
+				assert.True(t, tasks[1].FinalTask)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "!(a==b)", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("comparisons are folded", func(t *testing.T) {
+		folding := services.NewExpressionTaskService(mockRepo, opTimes, services.FoldableOperators, 30*time.Second)
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Empty(t, tasks)
+				assert.Equal(t, models.Done, expression.Status)
+				assert.Equal(t, 20.0, expression.Result)
+				return nil
+			})
+
+		_, err := folding.CreateExpressionTask(context.Background(), userID, "a<b && !(a==b) ? b*10 : 0", services.CalculationOptions{Optimize: true})
+		assert.NoError(t, err)
+	})
+}
+
+func TestCreateExpressionTask_IntegerOperators(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	opTimes := &services.OperationTimesMS{}
+	service := services.NewExpressionTaskService(mockRepo, opTimes, services.FoldableOperators, 30*time.Second)
+
+	userID := uuid.New()
+	variables := []*models.Variable{
+		{UserID: userID, Name: "n", Value: 1234, Decimal: "1234"},
+		{UserID: userID, Name: "rate", Value: 0.5, Decimal: "0.5"},
+	}
+
+	t.Run("integer operands become tasks", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, "n xor 0xFF%16", expression.Expression)
+				assert.Len(t, tasks, 2)
+				assert.Equal(t, "%", tasks[0].Operator)
+				assert.Equal(t, "xor", tasks[1].Operator)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "n xor 0xFF % 16", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("non-integer variable is rejected", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(variables, nil)
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "n % rate", services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrIntegerOperandRequired)
+
+		var exprErr *expr.Error
+		if assert.ErrorAs(t, err, &exprErr) {
+			assert.Equal(t, 4, exprErr.Pos)
+			assert.Equal(t, 8, exprErr.End)
+			assert.Equal(t, "rate", exprErr.Token)
+		}
+	})
+
+	t.Run("non-integer folded operand is rejected", func(t *testing.T) {
+		_, err := service.CreateExpressionTask(context.Background(), userID, "(1/4) << 2", services.CalculationOptions{Optimize: true})
+		assert.ErrorIs(t, err, services.ErrIntegerOperandRequired)
+	})
+
+	t.Run("integer operators are folded", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Empty(t, tasks)
+				assert.Equal(t, models.Done, expression.Status)
+				assert.Equal(t, 11.0, expression.Result)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "(-7 // 2 + 5) | 10", services.CalculationOptions{Optimize: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("decimal folding uses arbitrary precision integers", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Empty(t, tasks)
+				assert.Equal(t, "1180591620717411303425", expression.DecimalResult)
+				return nil
+			})
+
+		options := services.CalculationOptions{Precision: models.PrecisionDecimal, Optimize: true}
+		_, err := service.CreateExpressionTask(context.Background(), userID, "1 << 70 | 1", options)
+		assert.NoError(t, err)
+	})
+
+	t.Run("oversized decimal constants are not folded", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.NotEmpty(t, tasks)
+				assert.Empty(t, expression.DecimalResult)
+				return nil
+			})
+
+		options := services.CalculationOptions{Precision: models.PrecisionDecimal, Optimize: true}
+		input := "1" + strings.Repeat(" << 10000", 8)
+		_, err := service.CreateExpressionTask(context.Background(), userID, input, options)
+		assert.NoError(t, err)
+	})
+}
+
+func TestCreateExpressionTask_Lenient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	opTimes := &services.OperationTimesMS{}
+	service := services.NewExpressionTaskService(mockRepo, opTimes, nil, 30*time.Second)
+	userID := uuid.New()
+	lenient := services.CalculationOptions{Lenient: true}
+
+	t.Run("canonical expression is stored", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, "2*(3+4)*(15/100)", expression.Expression)
+				assert.Len(t, tasks, 4)
+				assert.Equal(t, []string{"+", "*", "/", "*"}, []string{tasks[0].Operator, tasks[1].Operator, tasks[2].Operator, tasks[3].Operator})
+				assert.Equal(t, 100.0, tasks[2].Args[1].Value)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "2(3+4)15%", lenient)
+		assert.NoError(t, err)
+	})
+
+	t.Run("strict mode rejects implicit multiplication", func(t *testing.T) {
+		_, err := service.CreateExpressionTask(context.Background(), userID, "2(3+4)", services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrParenthesisIssue)
+	})
+
+	t.Run("errors refer to the original input", func(t *testing.T) {
+		_, err := service.CreateExpressionTask(context.Background(), userID, "(1+2)(3/0)", lenient)
+		assert.ErrorIs(t, err, services.ErrDivisionByZero)
+
+		var exprErr *expr.Error
+		if assert.ErrorAs(t, err, &exprErr) {
+			assert.Equal(t, 8, exprErr.Pos)
+		}
+	})
+}
+
+func TestCreateExpressionTask_NumericPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewExpressionTaskService(mockRepo, &services.OperationTimesMS{}, services.FoldableOperators, 30*time.Second)
+	userID := uuid.New()
+
+	t.Run("strict is the default and leaves overflow to the agent", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, models.PolicyStrict, expression.Policy)
+				assert.Equal(t, models.Pending, expression.Status)
+				assert.Len(t, tasks, 1)
+				assert.Equal(t, "/", tasks[0].Operator)
+				assert.Equal(t, 0.0, tasks[0].Args[1].Value)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "1/(2-2)", services.CalculationOptions{Optimize: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("ieee folds infinities", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, models.PolicyIEEE, expression.Policy)
+				assert.Equal(t, models.Done, expression.Status)
+				assert.True(t, math.IsInf(expression.Result, 1))
+				assert.Empty(t, tasks)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "1/(2-2)",
+			services.CalculationOptions{Optimize: true, Policy: models.PolicyIEEE})
+		assert.NoError(t, err)
+	})
+
+	t.Run("saturate clamps folded overflow", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, models.Done, expression.Status)
+				assert.Equal(t, -math.MaxFloat64, expression.Result)
+				return nil
+			})
+
+		_, err := service.CreateExpressionTask(context.Background(), userID, "-1e308*10",
+			services.CalculationOptions{Optimize: true, Policy: models.PolicySaturate})
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		_, err := service.CreateExpressionTask(context.Background(), userID, "1+2", services.CalculationOptions{Policy: "wrap"})
+		assert.ErrorIs(t, err, services.ErrUnknownNumericPolicy)
+	})
+}
+
+func TestExpressionTaskService_CreateScriptTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewExpressionTaskService(mockRepo, &services.OperationTimesMS{}, services.FoldableOperators, 30*time.Second)
+	userID := uuid.New()
+
+	t.Run("last value builds one graph", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, "a=3*4; b=a+2; b/7", expression.Expression)
+				assert.Equal(t, models.Pending, expression.Status)
+				assert.Empty(t, expression.Values)
+				assert.Len(t, tasks, 3)
+				assert.Equal(t, tasks[0].ID, *tasks[1].Args[0].TaskID)
+				assert.Equal(t, tasks[1].ID, *tasks[2].Args[0].TaskID)
+				assert.Equal(t, []bool{false, false, true}, []bool{tasks[0].FinalTask, tasks[1].FinalTask, tasks[2].FinalTask})
+				return nil
+			})
+
+		_, err := service.CreateScriptTask(context.Background(), userID, "a = 3*4; b = a + 2; b / 7", "", services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("last value skips unused statements", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 2)
+				assert.Equal(t, "*", tasks[0].Operator)
+				assert.Equal(t, "-", tasks[1].Operator)
+				return nil
+			})
+
+		_, err := service.CreateScriptTask(context.Background(), userID, "a = 3*4; b = a + 2; 1 + 1; a - 1",
+			services.ScriptOutputLast, services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("all values", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).
+			Return([]*models.Variable{{UserID: userID, Name: "x", Value: 3, Decimal: "3"}}, nil)
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Len(t, tasks, 2)
+				assert.Len(t, expression.Values, 3)
+				assert.Equal(t, "a", expression.Values[0].Name)
+				assert.Equal(t, models.Number(2), *expression.Values[0].Value)
+				assert.Nil(t, expression.Values[0].TaskID)
+				assert.Equal(t, "b", expression.Values[1].Name)
+				assert.Equal(t, tasks[0].ID, *expression.Values[1].TaskID)
+				assert.Nil(t, expression.Values[1].Value)
+				assert.Equal(t, tasks[1].ID, *expression.Values[2].TaskID)
+				assert.True(t, tasks[1].FinalTask)
+				return nil
+			})
+
+		_, err := service.CreateScriptTask(context.Background(), userID, "a = 2; b = a * x; c = b + a",
+			services.ScriptOutputAll, services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("folded result waits for named values", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+				assert.Equal(t, models.Pending, expression.Status)
+				assert.Equal(t, 5.0, expression.Result)
+				assert.Len(t, tasks, 1)
+				assert.False(t, tasks[0].FinalTask)
+				return nil
+			})
+
+		_, err := service.CreateScriptTask(context.Background(), userID, "a = 1 + 2; 5",
+			services.ScriptOutputAll, services.CalculationOptions{})
+		assert.NoError(t, err)
+	})
+
+	tests := []struct {
+		name        string
+		script      string
+		output      services.ScriptOutput
+		expectedErr error
+	}{
+		{name: "unknown output", script: "a = 1+2; a", output: "first", expectedErr: services.ErrUnknownScriptOutput},
+		{name: "reassignment", script: "a = 1+2; a = 3; a", expectedErr: services.ErrNameReassigned},
+		{name: "assignment to function name", script: "sqrt = 1+2; sqrt", expectedErr: services.ErrAssignmentIssue},
+		{name: "invalid target", script: "2 = 1+2", expectedErr: services.ErrAssignmentIssue},
+		{name: "empty statement", script: "a = 1+2;; a", expectedErr: services.ErrStatementIssue},
+		{name: "no operators", script: "a = 1; a", expectedErr: services.ErrMissingOperator},
+		{name: "invalid value", script: "a = 1+; a", expectedErr: services.ErrInvalidExpressionStartEnd},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateScriptTask(context.Background(), userID, tt.script, tt.output, services.CalculationOptions{})
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+
+	t.Run("unknown variable", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(nil, nil)
+		_, err := service.CreateScriptTask(context.Background(), userID, "a = 1+2; a*b", "", services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrUnknownVariable)
+		assert.Equal(t, &services.ExpressionErrorDetails{
+			Code: "UNKNOWN_VARIABLE", Message: services.ErrUnknownVariable.Error(), Start: 11, End: 12, Token: "b",
+		}, services.DescribeExpressionError(err, "a = 1+2; a*b"))
+	})
+}
+
+func TestExpressionTaskService_CreateExpressionTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewExpressionTaskService(mockRepo, &services.OperationTimesMS{}, services.FoldableOperators, 30*time.Second)
+	userID := uuid.New()
+
+	t.Run("validates each expression on its own", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).
+			Return([]*models.Variable{{UserID: userID, Name: "x", Value: 3, Decimal: "3"}}, nil).Times(1)
+		mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).
+			Return([]*models.UserFunction{{UserID: userID, Name: "sq", Params: []string{"x"}, Body: "x*x"}}, nil).Times(1)
+
+		var saved []*models.Expression
+		mockRepo.EXPECT().CreateExpressionTasks(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, expressions []*models.Expression, tasks []*models.Task) error {
+				saved = expressions
+				assert.Len(t, expressions, 4)
+				assert.Len(t, tasks, 5)
+				assert.Equal(t, expressions[0].ID, tasks[0].ExpressionID)
+				assert.Equal(t, expressions[0].ID, tasks[1].ExpressionID)
+				assert.Equal(t, expressions[1].ID, tasks[2].ExpressionID)
+				assert.Equal(t, expressions[2].ID, tasks[3].ExpressionID)
+				assert.Equal(t, expressions[3].ID, tasks[4].ExpressionID)
+				assert.Equal(t, map[string]string{"x": "3"}, expressions[1].Variables)
+				return nil
+			})
+
+		items, err := service.CreateExpressionTasks(context.Background(), userID,
+			[]string{"2+2*2", "x*y", "x+1", "sq(x)", "1+", "2+2"}, services.CalculationOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, items, 6)
+		assert.Equal(t, saved[0].ID, items[0].ID)
+		assert.ErrorIs(t, items[1].Err, services.ErrUnknownVariable)
+		assert.Equal(t, uuid.Nil, items[1].ID)
+		assert.Equal(t, saved[1].ID, items[2].ID)
+		assert.Equal(t, saved[2].ID, items[3].ID)
+		assert.ErrorIs(t, items[4].Err, services.ErrInvalidExpressionStartEnd)
+		assert.Equal(t, saved[3].ID, items[5].ID)
+	})
+
+	t.Run("nothing to save", func(t *testing.T) {
+		items, err := service.CreateExpressionTasks(context.Background(), userID, []string{"", "2*"}, services.CalculationOptions{})
+		assert.NoError(t, err)
+		assert.ErrorIs(t, items[0].Err, services.ErrEmptyExpression)
+		assert.ErrorIs(t, items[1].Err, services.ErrInvalidExpressionStartEnd)
+	})
+
+	t.Run("batch size", func(t *testing.T) {
+		_, err := service.CreateExpressionTasks(context.Background(), userID, nil, services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrInvalidBatchSize)
+
+		expressions := make([]string, services.MaxBatchExpressions+1)
+		_, err = service.CreateExpressionTasks(context.Background(), userID, expressions, services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrInvalidBatchSize)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := service.CreateExpressionTasks(context.Background(), userID, []string{"1+2"}, services.CalculationOptions{Policy: "wrap"})
+		assert.ErrorIs(t, err, services.ErrUnknownNumericPolicy)
+	})
+
+	t.Run("lookup failure aborts the batch", func(t *testing.T) {
+		mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(nil, repository.ErrDatabaseNotAvailable)
+		_, err := service.CreateExpressionTasks(context.Background(), userID, []string{"1+2", "x+1"}, services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrDatabaseUnavailable)
+	})
+
+	t.Run("repository errors", func(t *testing.T) {
+		mockRepo.EXPECT().CreateExpressionTasks(gomock.Any(), gomock.Any(), gomock.Any()).Return(repository.ErrUnknownUserID)
+		_, err := service.CreateExpressionTasks(context.Background(), userID, []string{"1+2"}, services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrUnknownUserID)
+
+		mockRepo.EXPECT().CreateExpressionTasks(gomock.Any(), gomock.Any(), gomock.Any()).Return(repository.ErrDatabaseNotAvailable)
+		_, err = service.CreateExpressionTasks(context.Background(), userID, []string{"1+2"}, services.CalculationOptions{})
+		assert.ErrorIs(t, err, services.ErrDatabaseUnavailable)
+	})
+}
@@ -0,0 +1,33 @@
+package services
+
+const VariadicArity = -1
+
+type Function struct {
+	Name    string
+	MinArgs int
+	MaxArgs int
+	Decimal bool
+}
+
+var functions = map[string]Function{
+	"sqrt": {Name: "sqrt", MinArgs: 1, MaxArgs: 1, Decimal: true},
+	"sin":  {Name: "sin", MinArgs: 1, MaxArgs: 1},
+	"cos":  {Name: "cos", MinArgs: 1, MaxArgs: 1},
+	"log":  {Name: "log", MinArgs: 1, MaxArgs: 2},
+	"abs":  {Name: "abs", MinArgs: 1, MaxArgs: 1, Decimal: true},
+	"min":  {Name: "min", MinArgs: 1, MaxArgs: VariadicArity, Decimal: true},
+	"max":  {Name: "max", MinArgs: 1, MaxArgs: VariadicArity, Decimal: true},
+	"if":   {Name: "if", MinArgs: 3, MaxArgs: 3, Decimal: true},
+}
+
+func LookupFunction(name string) (Function, bool) {
+	fn, ok := functions[name]
+	return fn, ok
+}
+
+func (f Function) AcceptsArgs(count int) bool {
+	if count < f.MinArgs {
+		return false
+	}
+	return f.MaxArgs == VariadicArity || count <= f.MaxArgs
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"cmp"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+)
+
+var FoldableOperators = []string{
+	"+", "-", "*", "/", "^", NegationOperator, "sqrt", "sin", "cos", "log", "abs", "min", "max",
+	"<", "<=", ">", ">=", "==", "!=", "&&", "||", "!",
+	"%", "//", "&", "|", "xor", "<<", ">>",
+}
+
+var integerOperators = []string{"%", "//", "&", "|", "xor", "<<", ">>"}
+
+func IsIntegerOperator(operator string) bool {
+	return slices.Contains(integerOperators, operator)
+}
+
+func IsFoldableOperator(operator string) bool {
+	return slices.Contains(FoldableOperators, operator)
+}
+
+func foldFloat(operator string, args []models.Operand) (float64, bool) {
+	values := make([]float64, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+
+	var result float64
+	switch {
+	case len(values) == 1 && operator == NegationOperator:
+		result = -values[0]
+	case len(values) == 1 && operator == "sqrt":
+		result = math.Sqrt(values[0])
+	case len(values) == 1 && operator == "sin":
+		result = math.Sin(values[0])
+	case len(values) == 1 && operator == "cos":
+		result = math.Cos(values[0])
+	case len(values) == 1 && operator == "log":
+		result = math.Log(values[0])
+	case len(values) == 1 && operator == "abs":
+		result = math.Abs(values[0])
+	case len(values) == 1 && operator == "!":
+		result = truth(values[0] == 0)
+	case len(values) == 2 && operator == "+":
+		result = values[0] + values[1]
+	case len(values) == 2 && operator == "-":
+		result = values[0] - values[1]
+	case len(values) == 2 && operator == "*":
+		result = values[0] * values[1]
+	case len(values) == 2 && operator == "/":
+		result = values[0] / values[1]
+	case len(values) == 2 && operator == "^":
+		result = math.Pow(values[0], values[1])
+	case len(values) == 2 && operator == "log":
+		result = math.Log(values[0]) / math.Log(values[1])
+	case len(values) == 2 && isLogical(operator):
+		result = truth(compare(operator, cmp.Compare(values[0], values[1]), values[0] != 0, values[1] != 0))
+	case len(values) == 2 && IsIntegerOperator(operator):
+		result = foldInteger(operator, values[0], values[1])
+		if math.IsNaN(result) {
+			return result, false
+		}
+	case len(values) > 0 && operator == "min":
+		result = slices.Min(values)
+	case len(values) > 0 && operator == "max":
+		result = slices.Max(values)
+	default:
+		return math.NaN(), false
+	}
+	return result, true
+}
+
+func foldDecimal(operator string, args []models.Operand, scale int) (string, bool) {
+	values := make([]*big.Rat, len(args))
+	for i, arg := range args {
+		value, ok := new(big.Rat).SetString(arg.Decimal)
+		if !ok {
+			return "", false
+		}
+		values[i] = value
+	}
+
+	var result *big.Rat
+	switch {
+	case len(values) == 1 && operator == NegationOperator:
+		result = new(big.Rat).Neg(values[0])
+	case len(values) == 1 && operator == "abs":
+		result = new(big.Rat).Abs(values[0])
+	case len(values) == 1 && operator == "!":
+		result = big.NewRat(int64(truth(values[0].Sign() == 0)), 1)
+	case len(values) == 2 && operator == "+":
+		result = new(big.Rat).Add(values[0], values[1])
+	case len(values) == 2 && operator == "-":
+		result = new(big.Rat).Sub(values[0], values[1])
+	case len(values) == 2 && operator == "*":
+		result = new(big.Rat).Mul(values[0], values[1])
+	case len(values) == 2 && operator == "/" && values[1].Sign() != 0:
+		result = new(big.Rat).Quo(values[0], values[1])
+	case len(values) == 2 && isLogical(operator):
+		result = big.NewRat(int64(truth(compare(operator, values[0].Cmp(values[1]), values[0].Sign() != 0, values[1].Sign() != 0))), 1)
+	case len(values) == 2 && IsIntegerOperator(operator) && values[0].IsInt() && values[1].IsInt():
+		if integer := foldBigInteger(operator, values[0].Num(), values[1].Num()); integer != nil {
+			result = new(big.Rat).SetInt(integer)
+		}
+	case len(values) > 0 && operator == "min":
+		result = slices.MinFunc(values, (*big.Rat).Cmp)
+	case len(values) > 0 && operator == "max":
+		result = slices.MaxFunc(values, (*big.Rat).Cmp)
+	}
+	if result == nil || result.Num().BitLen()+result.Denom().BitLen() > MaxDecimalBits {
+		return "", false
+	}
+	return result.FloatString(scale), true
+}
+
+func foldInteger(operator string, x, y float64) float64 {
+	a, okA := toInt64(x)
+	b, okB := toInt64(y)
+	if !okA || !okB {
+		return math.NaN()
+	}
+	switch operator {
+	case "%":
+		if b != 0 {
+			m := a % b
+			if m != 0 && (m < 0) != (b < 0) {
+				m += b
+			}
+			return float64(m)
+		}
+	case "//":
+		if b != 0 {
+			q := a / b
+			if a%b != 0 && (a < 0) != (b < 0) {
+				q--
+			}
+			return float64(q)
+		}
+	case "&":
+		return float64(a & b)
+	case "|":
+		return float64(a | b)
+	case "xor":
+		return float64(a ^ b)
+	case "<<":
+		if b >= 0 && b < 64 && a<<b>>b == a {
+			return float64(a << b)
+		}
+	case ">>":
+		if b >= 0 {
+			return float64(a >> b)
+		}
+	}
+	return math.NaN()
+}
+
+func toInt64(value float64) (int64, bool) {
+	if value != math.Trunc(value) || value < math.MinInt64 || value >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(value), true
+}
+
+func foldBigInteger(operator string, x, y *big.Int) *big.Int {
+	switch operator {
+	case "%", "//":
+		if y.Sign() == 0 {
+			return nil
+		}
+		q, m := new(big.Int).QuoRem(x, y, new(big.Int))
+		if m.Sign() != 0 && (m.Sign() < 0) != (y.Sign() < 0) {
+			m.Add(m, y)
+			q.Sub(q, big.NewInt(1))
+		}
+		if operator == "%" {
+			return m
+		}
+		return q
+	case "&":
+		return new(big.Int).And(x, y)
+	case "|":
+		return new(big.Int).Or(x, y)
+	case "xor":
+		return new(big.Int).Xor(x, y)
+	case "<<", ">>":
+		if !y.IsInt64() || y.Sign() < 0 || y.Int64() > MaxDecimalShift {
+			return nil
+		}
+		if operator == "<<" {
+			return new(big.Int).Lsh(x, uint(y.Int64()))
+		}
+		return new(big.Int).Rsh(x, uint(y.Int64()))
+	}
+	return nil
+}
+
+func isLogical(operator string) bool {
+	switch operator {
+	case "<", "<=", ">", ">=", "==", "!=", "&&", "||":
+		return true
+	}
+	return false
+}
+
+func compare(operator string, order int, x, y bool) bool {
+	switch operator {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	case "==":
+		return order == 0
+	case "!=":
+		return order != 0
+	case "&&":
+		return x && y
+	case "||":
+		return x || y
+	}
+	return false
+}
+
+func truth(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func taskKey(operator string, args []models.Operand) string {
+	var key strings.Builder
+	key.WriteString(operator)
+	for _, arg := range args {
+		key.WriteByte('|')
+		switch {
+		case arg.TaskID != nil:
+			key.WriteString(arg.TaskID.String())
+		case arg.Decimal != "":
+			key.WriteString(arg.Decimal)
+		default:
+			key.WriteString(strconv.FormatUint(math.Float64bits(arg.Value), 16))
+		}
+	}
+	return key.String()
+}
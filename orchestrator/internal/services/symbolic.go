@@ -0,0 +1,683 @@
+package services
+
+import (
+	"context"
+	"math"
+	"math/big"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaxFoldExponent      = 64
+	MaxFoldLiteralLength = 64
+)
+
+type SymbolicOptions struct {
+	CalculationOptions
+	Submit   bool
+	Bindings map[string]string
+}
+
+type SymbolicResult struct {
+	Expression string     `json:"expression"`
+	ID         *uuid.UUID `json:"id,omitempty"`
+}
+
+func (s *expressionTaskService) DeriveExpression(ctx context.Context, userID uuid.UUID, expression, variable string, options SymbolicOptions) (*SymbolicResult, error) {
+	if !IsValidName(variable) {
+		return nil, ErrInvalidVariableName
+	}
+	return s.symbolic(ctx, userID, expression, options, func(root expr.Node, functions map[string]*models.UserFunction) (expr.Node, error) {
+		return Derive(root, variable, functions)
+	})
+}
+
+func (s *expressionTaskService) SimplifyExpression(ctx context.Context, userID uuid.UUID, expression string, options SymbolicOptions) (*SymbolicResult, error) {
+	return s.symbolic(ctx, userID, expression, options, func(root expr.Node, _ map[string]*models.UserFunction) (expr.Node, error) {
+		return Simplify(root), nil
+	})
+}
+
+func (s *expressionTaskService) symbolic(ctx context.Context, userID uuid.UUID, expression string, options SymbolicOptions,
+	transform func(expr.Node, map[string]*models.UserFunction) (expr.Node, error)) (*SymbolicResult, error) {
+	if options.Submit {
+		if err := validateOptions(&options.CalculationOptions); err != nil {
+			return nil, err
+		}
+	}
+	bound := make(map[string]*models.Variable, len(options.Bindings))
+	for name, value := range options.Bindings {
+		variable, err := newVariable(userID, name, value)
+		if err != nil {
+			return nil, err
+		}
+		bound[name] = variable
+	}
+
+	if err := checkLength(expression); err != nil {
+		return nil, err
+	}
+	tokens, err := expr.Lex(expression)
+	if err != nil {
+		return nil, err
+	}
+	if options.Lenient {
+		tokens = expr.Normalize(tokens)
+	}
+	root, err := expr.ParseTokens(tokens)
+	if err != nil {
+		return nil, err
+	}
+	catalog := &userCatalog{userID: userID}
+	functions, err := s.resolveFunctions(ctx, catalog, root)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateNode(root, functions); err != nil {
+		return nil, err
+	}
+
+	transformed, err := transform(root, functions)
+	if err != nil {
+		return nil, err
+	}
+	result := &SymbolicResult{Expression: expr.String(transformed)}
+	if !options.Submit {
+		return result, nil
+	}
+
+	expressionToSave, tasks, err := s.buildExpression(ctx, catalog, transformed, result.Expression, functions, bound, options.CalculationOptions)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.saveExpression(ctx, expressionToSave, tasks)
+	if err != nil {
+		return nil, err
+	}
+	result.ID = &id
+	return result, nil
+}
+
+type deriver struct {
+	variable  string
+	functions map[string]*models.UserFunction
+	depth     int
+}
+
+func Derive(root expr.Node, variable string, functions map[string]*models.UserFunction) (expr.Node, error) {
+	d := &deriver{variable: variable, functions: functions}
+	derivative, err := d.derive(root)
+	if err != nil {
+		return nil, err
+	}
+	return Simplify(derivative), nil
+}
+
+func (d *deriver) derive(node expr.Node) (expr.Node, error) {
+	if !d.dependsOn(node) {
+		return integer(0), nil
+	}
+	switch n := node.(type) {
+	case *expr.Ident:
+		return integer(1), nil
+	case *expr.ParenExpr:
+		return d.derive(n.X)
+	case *expr.UnaryExpr:
+		switch n.Op {
+		case "+":
+			return d.derive(n.X)
+		case "-":
+			dx, err := d.derive(n.X)
+			if err != nil {
+				return nil, err
+			}
+			return negate(dx), nil
+		}
+	case *expr.BinaryExpr:
+		return d.binary(n)
+	case *expr.CondExpr:
+		return d.conditional(n.Cond, n.X, n.Y)
+	case *expr.CallExpr:
+		return d.call(n)
+	}
+	return nil, notDifferentiable(node)
+}
+
+func (d *deriver) dependsOn(node expr.Node) bool {
+	found := false
+	expr.Walk(node, func(node expr.Node) bool {
+		if ident, ok := node.(*expr.Ident); ok && ident.Name == d.variable {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func (d *deriver) binary(n *expr.BinaryExpr) (expr.Node, error) {
+	switch n.Op {
+	case "+", "-", "*", "/", "^":
+	default:
+		return nil, notDifferentiable(n)
+	}
+	x, y := n.X, n.Y
+	dx, err := d.derive(x)
+	if err != nil {
+		return nil, err
+	}
+	dy, err := d.derive(y)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case "+", "-":
+		return binary(n.Op, dx, dy), nil
+	case "*":
+		return binary("+", binary("*", dx, y), binary("*", x, dy)), nil
+	case "/":
+		return binary("/", binary("-", binary("*", dx, y), binary("*", x, dy)), binary("^", y, integer(2))), nil
+	}
+	switch {
+	case !d.dependsOn(y):
+		return binary("*", binary("*", y, binary("^", x, binary("-", y, integer(1)))), dx), nil
+	case !d.dependsOn(x):
+		return binary("*", binary("*", binary("^", x, y), call("log", x)), dy), nil
+	}
+	return binary("*", binary("^", x, y), binary("+", binary("*", dy, call("log", x)), binary("/", binary("*", y, dx), x))), nil
+}
+
+func (d *deriver) conditional(cond, then, otherwise expr.Node) (expr.Node, error) {
+	dx, err := d.derive(then)
+	if err != nil {
+		return nil, err
+	}
+	dy, err := d.derive(otherwise)
+	if err != nil {
+		return nil, err
+	}
+	return &expr.CondExpr{Cond: cond, X: dx, Y: dy}, nil
+}
+
+func (d *deriver) call(n *expr.CallExpr) (expr.Node, error) {
+	if _, ok := LookupFunction(n.Func); !ok {
+		return d.expand(n)
+	}
+	if n.Func == models.ConditionalOperator {
+		return d.conditional(n.Args[0], n.Args[1], n.Args[2])
+	}
+	if n.Func == "log" && len(n.Args) == 2 {
+		if d.dependsOn(n.Args[1]) {
+			return d.derive(binary("/", call("log", n.Args[0]), call("log", n.Args[1])))
+		}
+		du, err := d.derive(call("log", n.Args[0]))
+		if err != nil {
+			return nil, err
+		}
+		return binary("/", du, call("log", n.Args[1])), nil
+	}
+
+	var outer expr.Node
+	u := n.Args[0]
+	switch n.Func {
+	case "sqrt":
+		outer = binary("/", integer(1), binary("*", integer(2), call("sqrt", u)))
+	case "sin":
+		outer = call("cos", u)
+	case "cos":
+		outer = negate(call("sin", u))
+	case "log":
+		outer = binary("/", integer(1), u)
+	case "abs":
+		outer = binary("/", u, call("abs", u))
+	default:
+		return nil, notDifferentiable(n)
+	}
+	du, err := d.derive(u)
+	if err != nil {
+		return nil, err
+	}
+	return binary("*", outer, du), nil
+}
+
+func (d *deriver) expand(n *expr.CallExpr) (expr.Node, error) {
+	fn, ok := d.functions[n.Func]
+	if !ok {
+		return nil, expr.NewError(ErrUnknownFunction, n.NamePos, n.NamePos+len(n.Func), n.Func)
+	}
+	if len(n.Args) != len(fn.Params) {
+		return nil, expr.NewError(ErrFunctionArgumentCount, n.Pos(), n.End(), n.Func)
+	}
+	if d.depth >= MaxFunctionDepth {
+		return nil, callSiteError(ErrFunctionDepthExceeded, n)
+	}
+	body, err := expr.Parse(fn.Body)
+	if err != nil {
+		return nil, callSiteError(err, n)
+	}
+	params := make(map[string]expr.Node, len(fn.Params))
+	for i, param := range fn.Params {
+		params[param] = n.Args[i]
+	}
+
+	d.depth++
+	derivative, err := d.derive(substitute(body, params))
+	d.depth--
+	if err != nil {
+		return nil, callSiteError(err, n)
+	}
+	return derivative, nil
+}
+
+func substitute(node expr.Node, params map[string]expr.Node) expr.Node {
+	switch n := node.(type) {
+	case *expr.Ident:
+		if arg, ok := params[n.Name]; ok {
+			return arg
+		}
+	case *expr.ParenExpr:
+		return substitute(n.X, params)
+	case *expr.UnaryExpr:
+		return &expr.UnaryExpr{Op: n.Op, X: substitute(n.X, params)}
+	case *expr.BinaryExpr:
+		return binary(n.Op, substitute(n.X, params), substitute(n.Y, params))
+	case *expr.CondExpr:
+		return &expr.CondExpr{Cond: substitute(n.Cond, params), X: substitute(n.X, params), Y: substitute(n.Y, params)}
+	case *expr.CallExpr:
+		args := make([]expr.Node, len(n.Args))
+		for i, arg := range n.Args {
+			args[i] = substitute(arg, params)
+		}
+		return call(n.Func, args...)
+	}
+	return node
+}
+
+func notDifferentiable(node expr.Node) error {
+	switch n := node.(type) {
+	case *expr.UnaryExpr:
+		return expr.NewError(ErrNotDifferentiable, n.OpPos, n.OpPos+len(n.Op), n.Op)
+	case *expr.BinaryExpr:
+		return expr.NewError(ErrNotDifferentiable, n.OpPos, n.OpPos+len(n.Op), n.Op)
+	case *expr.CallExpr:
+		return expr.NewError(ErrNotDifferentiable, n.NamePos, n.NamePos+len(n.Func), n.Func)
+	}
+	return expr.NewError(ErrNotDifferentiable, node.Pos(), node.End(), "")
+}
+
+func Simplify(node expr.Node) expr.Node {
+	switch n := node.(type) {
+	case *expr.NumberLit:
+		if value, err := expr.ParseDecimal(n.Raw); err == nil {
+			if literal, ok := number(value); ok {
+				return literal
+			}
+		}
+	case *expr.ParenExpr:
+		return Simplify(n.X)
+	case *expr.UnaryExpr:
+		return simplifyUnary(n.Op, Simplify(n.X))
+	case *expr.BinaryExpr:
+		return simplifyBinary(n.Op, Simplify(n.X), Simplify(n.Y))
+	case *expr.CondExpr:
+		cond, then, otherwise := Simplify(n.Cond), Simplify(n.X), Simplify(n.Y)
+		if branch, ok := chooseBranch(cond, then, otherwise); ok {
+			return branch
+		}
+		return &expr.CondExpr{Cond: cond, X: then, Y: otherwise}
+	case *expr.CallExpr:
+		args := make([]expr.Node, len(n.Args))
+		for i, arg := range n.Args {
+			args[i] = Simplify(arg)
+		}
+		return simplifyCall(n.Func, args)
+	}
+	return node
+}
+
+func simplifyUnary(op string, x expr.Node) expr.Node {
+	value, constant := literal(x)
+	switch op {
+	case "+":
+		return x
+	case "-":
+		if constant {
+			if folded, ok := number(new(big.Rat).Neg(value)); ok {
+				return folded
+			}
+		}
+		if inner, ok := negated(x); ok {
+			return inner
+		}
+		if product, ok := x.(*expr.BinaryExpr); ok && product.Op == "*" {
+			if _, ok := literal(product.X); ok {
+				return simplifyBinary("*", simplifyUnary("-", product.X), product.Y)
+			}
+		}
+		return negate(x)
+	case "!":
+		if constant {
+			return boolean(value.Sign() == 0)
+		}
+	}
+	return &expr.UnaryExpr{Op: op, X: x}
+}
+
+func simplifyBinary(op string, x, y expr.Node) expr.Node {
+	a, constantX := literal(x)
+	b, constantY := literal(y)
+	if constantX && constantY {
+		if folded, ok := foldRational(op, a, b); ok {
+			return folded
+		}
+	}
+
+	if term, ok := x.(*expr.BinaryExpr); ok && constantY && (op == "+" || op == "-") && (term.Op == "+" || term.Op == "-") {
+		if c, ok := literal(term.Y); ok {
+			if term.Op == "-" {
+				c.Neg(c)
+			}
+			if op == "-" {
+				b.Neg(b)
+			}
+			if sum, ok := number(c.Add(c, b)); ok {
+				return simplifyBinary("+", term.X, sum)
+			}
+		}
+	}
+
+	switch op {
+	case "+":
+		switch {
+		case isInteger(x, 0):
+			return y
+		case isInteger(y, 0):
+			return x
+		}
+		if inner, ok := negated(y); ok {
+			return simplifyBinary("-", x, inner)
+		}
+		if inner, ok := negated(x); ok {
+			return simplifyBinary("-", y, inner)
+		}
+		if sameNode(x, y) {
+			return simplifyBinary("*", integer(2), x)
+		}
+	case "-":
+		switch {
+		case isInteger(y, 0):
+			return x
+		case isInteger(x, 0):
+			return simplifyUnary("-", y)
+		case sameNode(x, y):
+			return integer(0)
+		}
+		if inner, ok := negated(y); ok {
+			return simplifyBinary("+", x, inner)
+		}
+	case "*":
+		switch {
+		case isInteger(x, 0) || isInteger(y, 0):
+			return integer(0)
+		case isInteger(x, 1):
+			return y
+		case isInteger(y, 1):
+			return x
+		case constantY && !constantX:
+			return simplifyBinary("*", y, x)
+		}
+		if inner, ok := negated(x); ok && !constantX {
+			return simplifyUnary("-", simplifyBinary("*", inner, y))
+		}
+		if inner, ok := negated(y); ok && !constantY {
+			return simplifyUnary("-", simplifyBinary("*", x, inner))
+		}
+		if product, ok := y.(*expr.BinaryExpr); ok && product.Op == "*" {
+			return simplifyBinary("*", simplifyBinary("*", x, product.X), product.Y)
+		}
+		if quotient, ok := y.(*expr.BinaryExpr); ok && quotient.Op == "/" {
+			return simplifyBinary("/", simplifyBinary("*", x, quotient.X), quotient.Y)
+		}
+		if quotient, ok := x.(*expr.BinaryExpr); ok && quotient.Op == "/" {
+			return simplifyBinary("/", simplifyBinary("*", quotient.X, y), quotient.Y)
+		}
+		if base, exponent, ok := samePowerBase(x, y); ok {
+			return simplifyBinary("^", base, simplifyBinary("+", exponent[0], exponent[1]))
+		}
+	case "/":
+		switch {
+		case isInteger(y, 1):
+			return x
+		case isInteger(x, 0) && !constantY:
+			return integer(0)
+		case sameNode(x, y) && !constantY:
+			return integer(1)
+		}
+		if quotient, ok := x.(*expr.BinaryExpr); ok && quotient.Op == "/" {
+			return simplifyBinary("/", quotient.X, simplifyBinary("*", quotient.Y, y))
+		}
+		if inner, ok := negated(x); ok && !constantX {
+			return simplifyUnary("-", simplifyBinary("/", inner, y))
+		}
+		if inner, ok := negated(y); ok && !constantY {
+			return simplifyUnary("-", simplifyBinary("/", x, inner))
+		}
+		if base, exponent, ok := samePowerBase(x, y); ok && !constantY {
+			return simplifyBinary("^", base, simplifyBinary("-", exponent[0], exponent[1]))
+		}
+	case "^":
+		switch {
+		case isInteger(y, 0):
+			return integer(1)
+		case isInteger(y, 1):
+			return x
+		case isInteger(x, 1):
+			return integer(1)
+		}
+	}
+	return binary(op, x, y)
+}
+
+func simplifyCall(name string, args []expr.Node) expr.Node {
+	if name == models.ConditionalOperator {
+		if branch, ok := chooseBranch(args[0], args[1], args[2]); ok {
+			return branch
+		}
+		return call(name, args...)
+	}
+
+	values := make([]*big.Rat, len(args))
+	for i, arg := range args {
+		value, ok := literal(arg)
+		if !ok {
+			return call(name, args...)
+		}
+		values[i] = value
+	}
+	var result *big.Rat
+	switch {
+	case name == "abs" && len(values) == 1:
+		result = new(big.Rat).Abs(values[0])
+	case name == "sqrt" && len(values) == 1 && values[0].Sign() >= 0:
+		num, denom := new(big.Int).Sqrt(values[0].Num()), new(big.Int).Sqrt(values[0].Denom())
+		if root := new(big.Rat).SetFrac(num, denom); new(big.Rat).Mul(root, root).Cmp(values[0]) == 0 {
+			result = root
+		}
+	case name == "min" && len(values) > 0:
+		result = minRat(values, -1)
+	case name == "max" && len(values) > 0:
+		result = minRat(values, 1)
+	}
+	if result != nil {
+		if folded, ok := number(result); ok {
+			return folded
+		}
+	}
+	return call(name, args...)
+}
+
+func chooseBranch(cond, then, otherwise expr.Node) (expr.Node, bool) {
+	if value, ok := literal(cond); ok {
+		if value.Sign() != 0 {
+			return then, true
+		}
+		return otherwise, true
+	}
+	if sameNode(then, otherwise) {
+		return then, true
+	}
+	return nil, false
+}
+
+func foldRational(op string, a, b *big.Rat) (expr.Node, bool) {
+	var result *big.Rat
+	switch {
+	case op == "+":
+		result = new(big.Rat).Add(a, b)
+	case op == "-":
+		result = new(big.Rat).Sub(a, b)
+	case op == "*":
+		result = new(big.Rat).Mul(a, b)
+	case op == "/" && b.Sign() != 0:
+		result = new(big.Rat).Quo(a, b)
+	case op == "^" && b.IsInt() && b.Num().IsInt64() && abs64(b.Num().Int64()) <= MaxFoldExponent:
+		exponent := b.Num().Int64()
+		if exponent < 0 && a.Sign() == 0 {
+			return nil, false
+		}
+		result = big.NewRat(1, 1)
+		for range abs64(exponent) {
+			result.Mul(result, a)
+		}
+		if exponent < 0 {
+			result.Inv(result)
+		}
+	case isLogical(op):
+		return boolean(compare(op, a.Cmp(b), a.Sign() != 0, b.Sign() != 0)), true
+	case IsIntegerOperator(op) && a.IsInt() && b.IsInt():
+		if integer := foldBigInteger(op, a.Num(), b.Num()); integer != nil {
+			result = new(big.Rat).SetInt(integer)
+		}
+	}
+	if result == nil {
+		return nil, false
+	}
+	return number(result)
+}
+
+func literal(node expr.Node) (*big.Rat, bool) {
+	switch n := expr.Unparen(node).(type) {
+	case *expr.NumberLit:
+		value, err := expr.ParseDecimal(n.Raw)
+		return value, err == nil
+	case *expr.UnaryExpr:
+		if n.Op != "-" {
+			return nil, false
+		}
+		if value, ok := literal(n.X); ok {
+			return value.Neg(value), true
+		}
+	}
+	return nil, false
+}
+
+func negated(node expr.Node) (expr.Node, bool) {
+	if unary, ok := expr.Unparen(node).(*expr.UnaryExpr); ok && unary.Op == "-" {
+		return unary.X, true
+	}
+	return nil, false
+}
+
+func samePowerBase(x, y expr.Node) (expr.Node, [2]expr.Node, bool) {
+	baseX, exponentX := powerOf(x)
+	baseY, exponentY := powerOf(y)
+	if _, ok := literal(baseX); ok || !sameNode(baseX, baseY) {
+		return nil, [2]expr.Node{}, false
+	}
+	return baseX, [2]expr.Node{exponentX, exponentY}, true
+}
+
+func powerOf(node expr.Node) (expr.Node, expr.Node) {
+	if power, ok := node.(*expr.BinaryExpr); ok && power.Op == "^" {
+		return power.X, power.Y
+	}
+	return node, integer(1)
+}
+
+func isInteger(node expr.Node, value int64) bool {
+	constant, ok := literal(node)
+	return ok && constant.Cmp(big.NewRat(value, 1)) == 0
+}
+
+func sameNode(x, y expr.Node) bool {
+	return expr.String(x) == expr.String(y)
+}
+
+func minRat(values []*big.Rat, sign int) *big.Rat {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value.Cmp(result) == sign {
+			result = value
+		}
+	}
+	return new(big.Rat).Set(result)
+}
+
+func abs64(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func number(value *big.Rat) (expr.Node, bool) {
+	denom := new(big.Int).Set(value.Denom())
+	for _, factor := range []int64{2, 5} {
+		divisor, remainder := big.NewInt(factor), new(big.Int)
+		for {
+			quotient, mod := new(big.Int).QuoRem(denom, divisor, remainder)
+			if mod.Sign() != 0 {
+				break
+			}
+			denom = quotient
+		}
+	}
+	float, _ := value.Float64()
+	if denom.Cmp(big.NewInt(1)) != 0 || math.IsInf(float, 0) {
+		return nil, false
+	}
+	raw := expr.FormatDecimal(new(big.Rat).Abs(value))
+	if len(raw) > MaxFoldLiteralLength {
+		return nil, false
+	}
+	lit := &expr.NumberLit{Value: math.Abs(float), Raw: raw}
+	if value.Sign() < 0 {
+		return negate(lit), true
+	}
+	return lit, true
+}
+
+func integer(value int64) expr.Node {
+	node, _ := number(big.NewRat(value, 1))
+	return node
+}
+
+func boolean(value bool) expr.Node {
+	return integer(int64(truth(value)))
+}
+
+func negate(x expr.Node) expr.Node {
+	return &expr.UnaryExpr{Op: "-", X: x}
+}
+
+func binary(op string, x, y expr.Node) expr.Node {
+	return &expr.BinaryExpr{Op: op, X: x, Y: y}
+}
+
+func call(name string, args ...expr.Node) expr.Node {
+	return &expr.CallExpr{Func: name, Args: args}
+}
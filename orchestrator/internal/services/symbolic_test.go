@@ -0,0 +1,211 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/services"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSimplify(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"constant folding", "2*3+4/8", "6.5"},
+		{"canonical literals", "1_000 + 0x10*x", "1000+16*x"},
+		{"inexact division kept", "1/3", "1/3"},
+		{"additive identities", "0+x-0", "x"},
+		{"multiplicative identities", "1*x*1/1", "x"},
+		{"zero product", "0*sin(x) + y", "y"},
+		{"power identities", "x^1 + y^0 + 1^z", "x+2"},
+		{"like terms", "(x+1) + (x+1)", "2*(x+1)"},
+		{"self difference", "sin(x) - sin(x)", "0"},
+		{"self quotient", "(x+y)/(x+y)", "1"},
+		{"coefficient first", "x*2*3", "6*x"},
+		{"same base product", "x*x*x", "x^3"},
+		{"same base quotient", "x^5/x^2", "x^3"},
+		{"double negation", "-(-x)", "x"},
+		{"subtract negative", "x - -y", "x+y"},
+		{"add negative", "-x + y", "y-x"},
+		{"negative coefficient", "-(2*x)", "-2*x"},
+		{"constant condition", "1 < 2 ? x : y", "x"},
+		{"equal branches", "if(x > 0, y, y)", "y"},
+		{"builtin functions", "abs(-4) + sqrt(2.25) + max(1, 5, 3) + sqrt(2)", "10.5+sqrt(2)"},
+		{"integer operators", "7 // 2 + (5 % 3) + (1 << 4)", "21"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := expr.Parse(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, expr.String(services.Simplify(root)))
+		})
+	}
+}
+
+func TestDerive(t *testing.T) {
+	functions := map[string]*models.UserFunction{
+		"sq":  {Name: "sq", Params: []string{"v"}, Body: "v*v"},
+		"hyp": {Name: "hyp", Params: []string{"a", "b"}, Body: "sqrt(sq(a)+sq(b))"},
+	}
+
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectedErr error
+		pos         int
+	}{
+		{name: "polynomial", input: "x^3 + 2*x - 7", expected: "3*x^2+2"},
+		{name: "constant", input: "y*z + 4", expected: "0"},
+		{name: "other symbols are constants", input: "a*x^2 + b*x + c", expected: "2*a*x+b"},
+		{name: "product rule", input: "x*sin(x)", expected: "sin(x)+x*cos(x)"},
+		{name: "quotient rule", input: "1/x", expected: "-1/x^2"},
+		{name: "chain rule", input: "cos(2*x)", expected: "-2*sin(2*x)"},
+		{name: "square root", input: "sqrt(x)", expected: "1/(2*sqrt(x))"},
+		{name: "natural logarithm", input: "log(x^2)", expected: "2*x/x^2"},
+		{name: "logarithm with base", input: "log(x, 2)", expected: "1/(x*log(2))"},
+		{name: "exponential", input: "2^x", expected: "2^x*log(2)"},
+		{name: "general power", input: "x^x", expected: "x^x*(log(x)+1)"},
+		{name: "absolute value", input: "abs(x)", expected: "x/abs(x)"},
+		{name: "piecewise", input: "x > 0 ? x^2 : -x", expected: "x>0?2*x: -1"},
+		{name: "user function", input: "sq(3*x)", expected: "18*x"},
+		{name: "nested user functions", input: "hyp(x, 4)", expected: "2*x/(2*sqrt(sq(x)+sq(4)))"},
+		{name: "user function without variable", input: "sq(y)", expected: "0"},
+		{name: "comparison", input: "x + (x < 1)", expectedErr: services.ErrNotDifferentiable, pos: 7},
+		{name: "min", input: "2 * min(x, 1)", expectedErr: services.ErrNotDifferentiable, pos: 4},
+		{name: "integer operator", input: "x % 2", expectedErr: services.ErrNotDifferentiable, pos: 2},
+		{name: "unknown user function", input: "f(x)", expectedErr: services.ErrUnknownFunction, pos: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := expr.Parse(tt.input)
+			assert.NoError(t, err)
+			derivative, err := services.Derive(root, "x", functions)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				var exprErr *expr.Error
+				if assert.ErrorAs(t, err, &exprErr) {
+					assert.Equal(t, tt.pos, exprErr.Pos)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			output := expr.String(derivative)
+			assert.Equal(t, tt.expected, output)
+
+			_, err = expr.Parse(output)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestExpressionTaskService_DeriveExpression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewExpressionTaskService(mockRepo, &services.OperationTimesMS{}, nil, 30*time.Second)
+	userID := uuid.New()
+
+	result, err := service.DeriveExpression(context.Background(), userID, "x^2 + a*x", "x", services.SymbolicOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, &services.SymbolicResult{Expression: "2*x+a"}, result)
+
+	mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return([]*models.Variable{
+		{UserID: userID, Name: "a", Value: 10, Decimal: "10"},
+	}, nil)
+	mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+			assert.Equal(t, "2*x+a", expression.Expression)
+			assert.Equal(t, map[string]string{"x": "1.5", "a": "10"}, expression.Variables)
+			assert.Equal(t, models.Pending, expression.Status)
+			assert.Len(t, tasks, 2)
+			return nil
+		})
+	result, err = service.DeriveExpression(context.Background(), userID, "x^2 + a*x", "x", services.SymbolicOptions{
+		Submit:   true,
+		Bindings: map[string]string{"x": "1.5"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "2*x+a", result.Expression)
+	assert.NotNil(t, result.ID)
+
+	mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+			assert.Equal(t, models.Done, expression.Status)
+			assert.Equal(t, float64(3), expression.Result)
+			assert.Empty(t, tasks)
+			return nil
+		})
+	result, err = service.DeriveExpression(context.Background(), userID, "3*x", "x", services.SymbolicOptions{Submit: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "3", result.Expression)
+
+	_, err = service.DeriveExpression(context.Background(), userID, "x^2", "sqrt", services.SymbolicOptions{})
+	assert.Equal(t, services.ErrInvalidVariableName, err)
+
+	_, err = service.DeriveExpression(context.Background(), userID, "x^2", "x", services.SymbolicOptions{
+		Submit:   true,
+		Bindings: map[string]string{"x": "abc"},
+	})
+	assert.Equal(t, services.ErrInvalidVariableValue, err)
+
+	_, err = service.DeriveExpression(context.Background(), userID, "x^2", "x", services.SymbolicOptions{
+		CalculationOptions: services.CalculationOptions{Precision: "double"},
+		Submit:             true,
+	})
+	assert.Equal(t, services.ErrUnknownPrecision, err)
+
+	mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return(nil, nil)
+	_, err = service.DeriveExpression(context.Background(), userID, "x^2*y", "x", services.SymbolicOptions{Submit: true})
+	assert.ErrorIs(t, err, services.ErrUnknownVariable)
+}
+
+func TestExpressionTaskService_SimplifyExpression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewExpressionTaskService(mockRepo, &services.OperationTimesMS{}, nil, 30*time.Second)
+	userID := uuid.New()
+
+	mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return([]*models.UserFunction{
+		{UserID: userID, Name: "sq", Params: []string{"v"}, Body: "v*v"},
+	}, nil)
+	result, err := service.SimplifyExpression(context.Background(), userID, "sq(x) * 1 + 0", services.SymbolicOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, &services.SymbolicResult{Expression: "sq(x)"}, result)
+
+	mockRepo.EXPECT().CreateExpressionTask(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, expression *models.Expression, tasks []*models.Task) error {
+			assert.Equal(t, "x^2+1", expression.Expression)
+			assert.Equal(t, models.PrecisionDecimal, expression.Precision)
+			assert.Equal(t, map[string]string{"x": "0.1"}, expression.Variables)
+			return nil
+		})
+	result, err = service.SimplifyExpression(context.Background(), userID, "x*x + 2 - 1", services.SymbolicOptions{
+		CalculationOptions: services.CalculationOptions{Precision: models.PrecisionDecimal, Scale: 4},
+		Submit:             true,
+		Bindings:           map[string]string{"x": "0.1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "x^2+1", result.Expression)
+
+	_, err = service.SimplifyExpression(context.Background(), userID, "x +* 1", services.SymbolicOptions{})
+	assert.ErrorIs(t, err, services.ErrOperatorIssue)
+
+	_, err = service.SimplifyExpression(context.Background(), userID, "x / 0", services.SymbolicOptions{})
+	assert.ErrorIs(t, err, services.ErrDivisionByZero)
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const MaxFunctionParams = 8
+
+type UserFunctionService interface {
+	DefineFunction(ctx context.Context, userID uuid.UUID, name string, params []string, body string) (*models.UserFunction, error)
+	GetFunctions(ctx context.Context, userID uuid.UUID) ([]*models.UserFunction, error)
+	DeleteFunction(ctx context.Context, userID uuid.UUID, name string) error
+}
+
+type userFunctionService struct {
+	repo repository.Repository
+}
+
+func NewUserFunctionService(repo repository.Repository) UserFunctionService {
+	return &userFunctionService{
+		repo: repo,
+	}
+}
+
+func (s *userFunctionService) DefineFunction(ctx context.Context, userID uuid.UUID, name string, params []string, body string) (*models.UserFunction, error) {
+	if !IsValidName(name) {
+		return nil, ErrInvalidFunctionName
+	}
+	if !validParams(params) {
+		return nil, ErrInvalidFunctionParameters
+	}
+	if err := checkLength(body); err != nil {
+		return nil, err
+	}
+	root, err := expr.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := expr.Lex(body)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetUserFunctions(ctx, userID)
+	if err != nil {
+		return nil, mapUserFunctionError(err)
+	}
+	functions := userFunctionMap(existing)
+	if _, ok := functions[name]; ok {
+		return nil, ErrFunctionAlreadyExists
+	}
+
+	function := &models.UserFunction{
+		UserID: userID,
+		Name:   name,
+		Params: append(make([]string, 0, len(params)), params...),
+		Body:   expr.Format(tokens),
+	}
+	functions[name] = function
+
+	if err := validateExpression(root, body, functions); err != nil {
+		return nil, err
+	}
+	if err := validateBodyIdents(root, params); err != nil {
+		return nil, err
+	}
+	if err := checkExpansion(function, functions); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateUserFunction(ctx, function); err != nil {
+		return nil, mapUserFunctionError(err)
+	}
+	return function, nil
+}
+
+func (s *userFunctionService) GetFunctions(ctx context.Context, userID uuid.UUID) ([]*models.UserFunction, error) {
+	functions, err := s.repo.GetUserFunctions(ctx, userID)
+	if err != nil {
+		return nil, mapUserFunctionError(err)
+	}
+	return functions, nil
+}
+
+func (s *userFunctionService) DeleteFunction(ctx context.Context, userID uuid.UUID, name string) error {
+	if err := s.repo.DeleteUserFunction(ctx, userID, name); err != nil {
+		return mapUserFunctionError(err)
+	}
+	return nil
+}
+
+func validParams(params []string) bool {
+	if len(params) > MaxFunctionParams {
+		return false
+	}
+	seen := make(map[string]bool, len(params))
+	for _, param := range params {
+		if !IsValidName(param) || seen[param] {
+			return false
+		}
+		seen[param] = true
+	}
+	return true
+}
+
+func validateBodyIdents(root expr.Node, params []string) error {
+	declared := make(map[string]bool, len(params))
+	for _, param := range params {
+		declared[param] = true
+	}
+	var err error
+	expr.Walk(root, func(node expr.Node) bool {
+		if ident, ok := node.(*expr.Ident); ok && err == nil && !declared[ident.Name] {
+			err = expr.NewError(ErrUnknownVariable, ident.Pos(), ident.End(), ident.Name)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func checkExpansion(function *models.UserFunction, functions map[string]*models.UserFunction) error {
+	call := &expr.CallExpr{Func: function.Name, Args: make([]expr.Node, len(function.Params))}
+	for i := range call.Args {
+		call.Args[i] = &expr.NumberLit{Value: 1, Raw: "1"}
+	}
+	if _, _, err := BuildTasks(uuid.Nil, call, BuildOptions{Functions: functions}); err != nil {
+		var exprErr *expr.Error
+		if errors.As(err, &exprErr) {
+			return exprErr.Err
+		}
+		return err
+	}
+	return nil
+}
+
+func mapUserFunctionError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrUserFunctionAlreadyExists):
+		return ErrFunctionAlreadyExists
+	case errors.Is(err, repository.ErrUnknownUserFunction):
+		return ErrUnknownFunction
+	case errors.Is(err, repository.ErrUnknownUserID):
+		return ErrUnknownUserID
+	case errors.Is(err, repository.ErrDatabaseNotAvailable):
+		return ErrDatabaseUnavailable
+	}
+	return err
+}
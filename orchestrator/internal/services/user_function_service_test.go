@@ -0,0 +1,246 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/repository"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/services"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUserFunctionService_DefineFunction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewUserFunctionService(mockRepo)
+	userID := uuid.New()
+
+	existing := []*models.UserFunction{
+		{UserID: userID, Name: "sq", Params: []string{"x"}, Body: "x*x"},
+		{UserID: userID, Name: "loop", Params: []string{"x"}, Body: "back(x)+1"},
+	}
+
+	tests := []struct {
+		name        string
+		fnName      string
+		params      []string
+		body        string
+		mockSetup   func()
+		expected    *models.UserFunction
+		expectedErr error
+	}{
+		{
+			name:   "success",
+			fnName: "f",
+			params: []string{"x", "y"},
+			body:   "x * x + y",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(existing, nil)
+				mockRepo.EXPECT().CreateUserFunction(gomock.Any(), &models.UserFunction{
+					UserID: userID, Name: "f", Params: []string{"x", "y"}, Body: "x*x+y",
+				}).Return(nil)
+			},
+			expected: &models.UserFunction{UserID: userID, Name: "f", Params: []string{"x", "y"}, Body: "x*x+y"},
+		},
+		{
+			name:   "calls other user functions",
+			fnName: "hyp",
+			params: []string{"a", "b"},
+			body:   "sqrt(sq(a) + sq(b))",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(existing, nil)
+				mockRepo.EXPECT().CreateUserFunction(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expected: &models.UserFunction{UserID: userID, Name: "hyp", Params: []string{"a", "b"}, Body: "sqrt(sq(a)+sq(b))"},
+		},
+		{
+			name:   "no parameters",
+			fnName: "tau",
+			body:   "2*3.14159",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(nil, nil)
+				mockRepo.EXPECT().CreateUserFunction(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expected: &models.UserFunction{UserID: userID, Name: "tau", Params: []string{}, Body: "2*3.14159"},
+		},
+		{
+			name:        "name clashes with built-in",
+			fnName:      "sqrt",
+			params:      []string{"x"},
+			body:        "x*x",
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidFunctionName,
+		},
+		{
+			name:        "duplicate parameter",
+			fnName:      "f",
+			params:      []string{"x", "x"},
+			body:        "x*x",
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidFunctionParameters,
+		},
+		{
+			name:        "too many parameters",
+			fnName:      "f",
+			params:      []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"},
+			body:        "a+b",
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidFunctionParameters,
+		},
+		{
+			name:        "invalid body",
+			fnName:      "f",
+			params:      []string{"x"},
+			body:        "x*",
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidExpressionStartEnd,
+		},
+		{
+			name:   "body uses undeclared name",
+			fnName: "f",
+			params: []string{"x"},
+			body:   "x*y",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(existing, nil)
+			},
+			expectedErr: services.ErrUnknownVariable,
+		},
+		{
+			name:   "body without operator",
+			fnName: "f",
+			params: []string{"x"},
+			body:   "(x)",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(existing, nil)
+			},
+			expectedErr: services.ErrMissingOperator,
+		},
+		{
+			name:   "wrong argument count for user function",
+			fnName: "f",
+			params: []string{"x"},
+			body:   "sq(x, 2)",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(existing, nil)
+			},
+			expectedErr: services.ErrFunctionArgumentCount,
+		},
+		{
+			name:   "direct recursion",
+			fnName: "f",
+			params: []string{"x"},
+			body:   "f(x-1)*x",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(existing, nil)
+			},
+			expectedErr: services.ErrRecursiveFunction,
+		},
+		{
+			name:   "indirect recursion",
+			fnName: "back",
+			params: []string{"x"},
+			body:   "loop(x)*2",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(existing, nil)
+			},
+			expectedErr: services.ErrRecursiveFunction,
+		},
+		{
+			name:   "already exists",
+			fnName: "sq",
+			params: []string{"x"},
+			body:   "x^2",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(existing, nil)
+			},
+			expectedErr: services.ErrFunctionAlreadyExists,
+		},
+		{
+			name:   "database unavailable",
+			fnName: "f",
+			params: []string{"x"},
+			body:   "x+1",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(nil, repository.ErrDatabaseNotAvailable)
+			},
+			expectedErr: services.ErrDatabaseUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			function, err := service.DefineFunction(context.Background(), userID, tt.fnName, tt.params, tt.body)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, function)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, function)
+			}
+		})
+	}
+}
+
+func TestUserFunctionService_DefineFunction_ExpansionLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewUserFunctionService(mockRepo)
+	userID := uuid.New()
+
+	var chain []*models.UserFunction
+	for i := 1; i <= services.MaxFunctionDepth; i++ {
+		chain = append(chain, &models.UserFunction{Name: fmt.Sprintf("f%d", i), Params: []string{"x"}, Body: fmt.Sprintf("f%d(x)+1", i-1)})
+	}
+	chain[0].Body = "x+1"
+
+	mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(chain, nil)
+	_, err := service.DefineFunction(context.Background(), userID, "deep", []string{"x"}, fmt.Sprintf("f%d(x)*2", services.MaxFunctionDepth))
+	assert.ErrorIs(t, err, services.ErrFunctionDepthExceeded)
+
+	var doubling []*models.UserFunction
+	for i := 1; i <= 14; i++ {
+		doubling = append(doubling, &models.UserFunction{Name: fmt.Sprintf("d%d", i), Params: []string{"x"}, Body: fmt.Sprintf("d%d(x)+d%d(x)", i-1, i-1)})
+	}
+	doubling[0].Body = "x+x"
+
+	mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return(doubling, nil)
+	_, err = service.DefineFunction(context.Background(), userID, "huge", []string{"x"}, "d14(x)*2")
+	assert.ErrorIs(t, err, services.ErrTooManyTasks)
+
+	var exprErr *expr.Error
+	assert.False(t, errors.As(err, &exprErr))
+}
+
+func TestUserFunctionService_GetAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewUserFunctionService(mockRepo)
+	userID := uuid.New()
+	sq := &models.UserFunction{UserID: userID, Name: "sq", Params: []string{"x"}, Body: "x*x"}
+
+	mockRepo.EXPECT().GetUserFunctions(gomock.Any(), userID).Return([]*models.UserFunction{sq}, nil)
+	functions, err := service.GetFunctions(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.UserFunction{sq}, functions)
+
+	mockRepo.EXPECT().DeleteUserFunction(gomock.Any(), userID, "sq").Return(nil)
+	assert.NoError(t, service.DeleteFunction(context.Background(), userID, "sq"))
+
+	mockRepo.EXPECT().DeleteUserFunction(gomock.Any(), userID, "sq").Return(repository.ErrUnknownUserFunction)
+	assert.Equal(t, services.ErrUnknownFunction, service.DeleteFunction(context.Background(), userID, "sq"))
+}
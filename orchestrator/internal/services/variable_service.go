@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const MaxVariableNameLength = 64
+
+type VariableService interface {
+	CreateVariable(ctx context.Context, userID uuid.UUID, name, value string) (*models.Variable, error)
+	UpdateVariable(ctx context.Context, userID uuid.UUID, name, value string) (*models.Variable, error)
+	GetVariables(ctx context.Context, userID uuid.UUID) ([]*models.Variable, error)
+	GetVariable(ctx context.Context, userID uuid.UUID, name string) (*models.Variable, error)
+	DeleteVariable(ctx context.Context, userID uuid.UUID, name string) error
+}
+
+type variableService struct {
+	repo repository.Repository
+}
+
+func NewVariableService(repo repository.Repository) VariableService {
+	return &variableService{
+		repo: repo,
+	}
+}
+
+func (s *variableService) CreateVariable(ctx context.Context, userID uuid.UUID, name, value string) (*models.Variable, error) {
+	variable, err := newVariable(userID, name, value)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateVariable(ctx, variable); err != nil {
+		return nil, mapVariableError(err)
+	}
+	return variable, nil
+}
+
+func (s *variableService) UpdateVariable(ctx context.Context, userID uuid.UUID, name, value string) (*models.Variable, error) {
+	variable, err := newVariable(userID, name, value)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateVariable(ctx, variable); err != nil {
+		return nil, mapVariableError(err)
+	}
+	return variable, nil
+}
+
+func (s *variableService) GetVariables(ctx context.Context, userID uuid.UUID) ([]*models.Variable, error) {
+	variables, err := s.repo.GetVariables(ctx, userID)
+	if err != nil {
+		return nil, mapVariableError(err)
+	}
+	return variables, nil
+}
+
+func (s *variableService) GetVariable(ctx context.Context, userID uuid.UUID, name string) (*models.Variable, error) {
+	variable, err := s.repo.GetVariable(ctx, userID, name)
+	if err != nil {
+		return nil, mapVariableError(err)
+	}
+	return variable, nil
+}
+
+func (s *variableService) DeleteVariable(ctx context.Context, userID uuid.UUID, name string) error {
+	if err := s.repo.DeleteVariable(ctx, userID, name); err != nil {
+		return mapVariableError(err)
+	}
+	return nil
+}
+
+func newVariable(userID uuid.UUID, name, value string) (*models.Variable, error) {
+	if !IsValidName(name) {
+		return nil, ErrInvalidVariableName
+	}
+
+	if len(value) > MaxDecimalLiteralLength {
+		return nil, ErrInvalidVariableValue
+	}
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsInf(floatValue, 0) || math.IsNaN(floatValue) {
+		return nil, ErrInvalidVariableValue
+	}
+	decimalValue, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, ErrInvalidVariableValue
+	}
+
+	return &models.Variable{
+		UserID:  userID,
+		Name:    name,
+		Value:   floatValue,
+		Decimal: expr.FormatDecimal(decimalValue),
+	}, nil
+}
+
+func IsValidName(name string) bool {
+	if name == "" || len(name) > MaxVariableNameLength {
+		return false
+	}
+	if _, ok := LookupFunction(name); ok {
+		return false
+	}
+	tokens, err := expr.Lex(name)
+	return err == nil && len(tokens) == 2 && tokens[0].Kind == expr.Identifier && tokens[0].Text == name
+}
+
+func mapVariableError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrVariableAlreadyExists):
+		return ErrVariableAlreadyExists
+	case errors.Is(err, repository.ErrUnknownVariable):
+		return ErrUnknownVariable
+	case errors.Is(err, repository.ErrUnknownUserID):
+		return ErrUnknownUserID
+	case errors.Is(err, repository.ErrDatabaseNotAvailable):
+		return ErrDatabaseUnavailable
+	}
+	return err
+}
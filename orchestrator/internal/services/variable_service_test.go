@@ -0,0 +1,186 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/repository"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/services"
+	"github.com/alexGoLyceum/calculator-service/orchestrator/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVariableService_CreateVariable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewVariableService(mockRepo)
+	userID := uuid.New()
+
+	tests := []struct {
+		name        string
+		varName     string
+		value       string
+		mockSetup   func()
+		expected    *models.Variable
+		expectedErr error
+	}{
+		{
+			name:    "success",
+			varName: "rate",
+			value:   "0.05",
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateVariable(gomock.Any(), &models.Variable{
+					UserID: userID, Name: "rate", Value: 0.05, Decimal: "0.05",
+				}).Return(nil)
+			},
+			expected: &models.Variable{UserID: userID, Name: "rate", Value: 0.05, Decimal: "0.05"},
+		},
+		{
+			name:    "exponent value is stored exactly",
+			varName: "_small2",
+			value:   "-1.5e-3",
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateVariable(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expected: &models.Variable{UserID: userID, Name: "_small2", Value: -0.0015, Decimal: "-0.0015"},
+		},
+		{
+			name:        "name starts with digit",
+			varName:     "2rate",
+			value:       "1",
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidVariableName,
+		},
+		{
+			name:        "name with invalid character",
+			varName:     "rate-1",
+			value:       "1",
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidVariableName,
+		},
+		{
+			name:        "name too long",
+			varName:     strings.Repeat("a", services.MaxVariableNameLength+1),
+			value:       "1",
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidVariableName,
+		},
+		{
+			name:        "name clashes with function",
+			varName:     "sqrt",
+			value:       "1",
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidVariableName,
+		},
+		{
+			name:        "invalid value",
+			varName:     "rate",
+			value:       "abc",
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidVariableValue,
+		},
+		{
+			name:        "infinite value",
+			varName:     "rate",
+			value:       "1e400",
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidVariableValue,
+		},
+		{
+			name:        "value too long",
+			varName:     "rate",
+			value:       "0." + strings.Repeat("1", services.MaxDecimalLiteralLength),
+			mockSetup:   func() {},
+			expectedErr: services.ErrInvalidVariableValue,
+		},
+		{
+			name:    "already exists",
+			varName: "rate",
+			value:   "1",
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateVariable(gomock.Any(), gomock.Any()).Return(repository.ErrVariableAlreadyExists)
+			},
+			expectedErr: services.ErrVariableAlreadyExists,
+		},
+		{
+			name:    "database unavailable",
+			varName: "rate",
+			value:   "1",
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateVariable(gomock.Any(), gomock.Any()).Return(repository.ErrDatabaseNotAvailable)
+			},
+			expectedErr: services.ErrDatabaseUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			variable, err := service.CreateVariable(context.Background(), userID, tt.varName, tt.value)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, variable)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, variable)
+			}
+		})
+	}
+}
+
+func TestVariableService_UpdateVariable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewVariableService(mockRepo)
+	userID := uuid.New()
+
+	mockRepo.EXPECT().UpdateVariable(gomock.Any(), gomock.Any()).Return(nil)
+	variable, err := service.UpdateVariable(context.Background(), userID, "rate", "0.07")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.07, variable.Value)
+
+	mockRepo.EXPECT().UpdateVariable(gomock.Any(), gomock.Any()).Return(repository.ErrUnknownVariable)
+	_, err = service.UpdateVariable(context.Background(), userID, "missing", "1")
+	assert.Equal(t, services.ErrUnknownVariable, err)
+}
+
+func TestVariableService_GetAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := services.NewVariableService(mockRepo)
+	userID := uuid.New()
+	rate := &models.Variable{UserID: userID, Name: "rate", Value: 0.05, Decimal: "0.05"}
+
+	mockRepo.EXPECT().GetVariables(gomock.Any(), userID).Return([]*models.Variable{rate}, nil)
+	variables, err := service.GetVariables(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.Variable{rate}, variables)
+
+	mockRepo.EXPECT().GetVariable(gomock.Any(), userID, "rate").Return(rate, nil)
+	variable, err := service.GetVariable(context.Background(), userID, "rate")
+	assert.NoError(t, err)
+	assert.Equal(t, rate, variable)
+
+	mockRepo.EXPECT().GetVariable(gomock.Any(), userID, "missing").Return(nil, repository.ErrUnknownVariable)
+	_, err = service.GetVariable(context.Background(), userID, "missing")
+	assert.Equal(t, services.ErrUnknownVariable, err)
+
+	mockRepo.EXPECT().DeleteVariable(gomock.Any(), userID, "rate").Return(nil)
+	assert.NoError(t, service.DeleteVariable(context.Background(), userID, "rate"))
+
+	unexpected := errors.New("unexpected error")
+	mockRepo.EXPECT().DeleteVariable(gomock.Any(), userID, "rate").Return(unexpected)
+	assert.Equal(t, unexpected, service.DeleteVariable(context.Background(), userID, "rate"))
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
-	"time"
 
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/services"
 	pb "github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/grpc/proto"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -18,64 +20,130 @@ import (
 type Server interface {
 	pb.OrchestratorServiceServer
 	Start() error
-	AssignTasks(req *pb.AssignTasksRequest, stream pb.OrchestratorService_AssignTasksServer) error
+	AssignTasks(stream pb.OrchestratorService_AssignTasksServer) error
 }
 
 type server struct {
 	pb.UnimplementedOrchestratorServiceServer
 	exprTaskService services.ExpressionTaskService
+	agentService    services.AgentService
 	address         string
 }
 
-func NewServer(exprTaskService services.ExpressionTaskService, host string, port int) Server {
+func NewServer(exprTaskService services.ExpressionTaskService, agentService services.AgentService, host string, port int) Server {
 	return &server{
 		exprTaskService: exprTaskService,
+		agentService:    agentService,
 		address:         fmt.Sprintf("%s:%d", host, port),
 	}
 }
 
-func (s *server) AssignTasks(req *pb.AssignTasksRequest, stream pb.OrchestratorService_AssignTasksServer) error {
+func (s *server) RegisterAgent(ctx context.Context, req *pb.RegisterAgentRequest) (*pb.RegisterAgentResponse, error) {
+	agent := &models.Agent{
+		ID:        req.AgentId,
+		Hostname:  req.Hostname,
+		Version:   req.Version,
+		Capacity:  int(req.Capacity),
+		Operators: req.Operators,
+	}
+	if err := s.agentService.RegisterAgent(ctx, agent); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidAgentID), errors.Is(err, services.ErrInvalidAgentCapacity):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, services.ErrDatabaseUnavailable):
+			return nil, status.Error(codes.Unavailable, "server is unavailable")
+		default:
+			return nil, status.Error(codes.Internal, "failed to register agent")
+		}
+	}
+	return &pb.RegisterAgentResponse{}, nil
+}
+
+func (s *server) AssignTasks(stream pb.OrchestratorService_AssignTasksServer) error {
 	ctx := stream.Context()
+	var agent string
 
-	for {
-		select {
-		case <-ctx.Done():
-			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-				return status.Error(codes.DeadlineExceeded, ctx.Err().Error())
-			}
-			return nil
-		default:
-			task, err := s.exprTaskService.GetTask(ctx)
+	grants := make(chan *pb.AssignTasksRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
 			if err != nil {
-				if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
-					return status.FromContextError(err).Err()
-				}
-				return status.Error(codes.Internal, "failed to get task")
+				recvErr <- err
+				return
+			}
+			select {
+			case grants <- req:
+			case <-ctx.Done():
+				return
 			}
+		}
+	}()
 
-			if task == nil {
-				time.Sleep(100 * time.Millisecond)
-				continue
+	var credits int32
+	for {
+		for credits <= 0 {
+			select {
+			case req := <-grants:
+				if agent == "" {
+					if err := s.touchAgent(ctx, req.AgentId); err != nil {
+						return err
+					}
+					agent = req.AgentId
+				}
+				credits += max(req.Credits, 0)
+			case err := <-recvErr:
+				if errors.Is(err, io.EOF) || ctx.Err() != nil {
+					return contextStatus(ctx)
+				}
+				return status.Errorf(codes.Unavailable, "failed to receive credits: %v", err)
+			case <-ctx.Done():
+				return contextStatus(ctx)
 			}
+		}
 
-			if err := stream.Send(task); err != nil {
-				return status.Errorf(codes.Unavailable, "failed to send task: %v", err)
+		task, err := s.exprTaskService.WaitTask(ctx, agent)
+		if err != nil {
+			if ctx.Err() != nil {
+				return contextStatus(ctx)
 			}
+			return status.Error(codes.Internal, "failed to get task")
+		}
+
+		if err := stream.Send(task); err != nil {
+			_ = s.exprTaskService.ReleaseTask(context.WithoutCancel(ctx), task)
+			return status.Errorf(codes.Unavailable, "failed to send task: %v", err)
 		}
+		credits--
+	}
+}
+
+func contextStatus(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, ctx.Err().Error())
 	}
+	return nil
 }
 
 func (s *server) SubmitTask(ctx context.Context, req *pb.SubmitTaskRequest) (*pb.SubmitTaskResponse, error) {
-	if req.Task == nil {
-		return nil, status.Error(codes.InvalidArgument, "task required")
+	taskID, err := uuid.Parse(req.TaskId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid task id")
+	}
+	token, err := uuid.Parse(req.AssignmentToken)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid assignment token")
 	}
-	err := s.exprTaskService.SetTaskResult(ctx, req.Task, req.Result)
+	result := models.TaskResult{Value: req.Result, Decimal: req.DecimalResult, Error: req.Error}
+	err = s.exprTaskService.SetTaskResult(ctx, taskID, token, result)
 	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrDatabaseUnavailable):
 			return nil, status.Error(codes.Unavailable, "server is unavailable")
 		case errors.Is(err, services.ErrUnknownTaskID):
 			return nil, status.Error(codes.NotFound, "task id not found")
+		case errors.Is(err, services.ErrLeaseNotHeld):
+			return nil, status.Error(codes.FailedPrecondition, "task lease is not held")
 		default:
 			return nil, status.Error(codes.Internal, "failed to set result")
 		}
@@ -83,6 +151,52 @@ func (s *server) SubmitTask(ctx context.Context, req *pb.SubmitTaskRequest) (*pb
 	return &pb.SubmitTaskResponse{}, nil
 }
 
+func (s *server) Heartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
+	taskIDs := make([]uuid.UUID, len(req.TaskIds))
+	for i, id := range req.TaskIds {
+		taskID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid task id %q", id)
+		}
+		taskIDs[i] = taskID
+	}
+
+	if err := s.touchAgent(ctx, req.AgentId); err != nil {
+		return nil, err
+	}
+
+	lost, err := s.exprTaskService.RenewLeases(ctx, req.AgentId, taskIDs)
+	if err != nil {
+		if errors.Is(err, services.ErrDatabaseUnavailable) {
+			return nil, status.Error(codes.Unavailable, "server is unavailable")
+		}
+		return nil, status.Error(codes.Internal, "failed to renew leases")
+	}
+
+	resp := &pb.HeartbeatResponse{LostTaskIds: make([]string, len(lost))}
+	for i, id := range lost {
+		resp.LostTaskIds[i] = id.String()
+	}
+	return resp, nil
+}
+
+func (s *server) touchAgent(ctx context.Context, id string) error {
+	if id == "" {
+		return status.Error(codes.InvalidArgument, "agent id is required")
+	}
+	if err := s.agentService.TouchAgent(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownAgent):
+			return status.Error(codes.FailedPrecondition, "agent is not registered")
+		case errors.Is(err, services.ErrDatabaseUnavailable):
+			return status.Error(codes.Unavailable, "server is unavailable")
+		default:
+			return status.Error(codes.Internal, "failed to update agent")
+		}
+	}
+	return nil
+}
+
 func (s *server) Start() error {
 	listener, err := net.Listen("tcp", s.address)
 	if err != nil {
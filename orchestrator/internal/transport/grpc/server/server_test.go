@@ -3,13 +3,17 @@ package server_test
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
+	"time"
 
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/services"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/grpc/proto"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/grpc/server"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/mocks"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc/codes"
@@ -21,18 +25,26 @@ func TestSubmitTask(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockExpressionTaskService(ctrl)
-	s := server.NewServer(mockService, "localhost", 0)
+	s := server.NewServer(mockService, mocks.NewMockAgentService(ctrl), "localhost", 0)
 
-	t.Run("nil task", func(t *testing.T) {
-		resp, err := s.SubmitTask(context.Background(), &proto.SubmitTaskRequest{})
+	taskID, token := uuid.New(), uuid.New()
+
+	t.Run("invalid task id", func(t *testing.T) {
+		resp, err := s.SubmitTask(context.Background(), &proto.SubmitTaskRequest{AssignmentToken: token.String()})
+		require.Nil(t, resp)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("invalid assignment token", func(t *testing.T) {
+		resp, err := s.SubmitTask(context.Background(), &proto.SubmitTaskRequest{TaskId: taskID.String(), AssignmentToken: "abc"})
 		require.Nil(t, resp)
 		require.Equal(t, codes.InvalidArgument, status.Code(err))
 	})
 
 	t.Run("database unavailable", func(t *testing.T) {
-		req := &proto.SubmitTaskRequest{Task: &proto.Task{}}
+		req := &proto.SubmitTaskRequest{TaskId: taskID.String(), AssignmentToken: token.String()}
 		mockService.EXPECT().
-			SetTaskResult(gomock.Any(), req.Task, req.Result).
+			SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Value: req.Result}).
 			Return(services.ErrDatabaseUnavailable)
 
 		resp, err := s.SubmitTask(context.Background(), req)
@@ -41,9 +53,9 @@ func TestSubmitTask(t *testing.T) {
 	})
 
 	t.Run("unknown task id", func(t *testing.T) {
-		req := &proto.SubmitTaskRequest{Task: &proto.Task{}}
+		req := &proto.SubmitTaskRequest{TaskId: taskID.String(), AssignmentToken: token.String()}
 		mockService.EXPECT().
-			SetTaskResult(gomock.Any(), req.Task, req.Result).
+			SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Value: req.Result}).
 			Return(services.ErrUnknownTaskID)
 
 		resp, err := s.SubmitTask(context.Background(), req)
@@ -51,10 +63,21 @@ func TestSubmitTask(t *testing.T) {
 		require.Equal(t, codes.NotFound, status.Code(err))
 	})
 
+	t.Run("lease not held", func(t *testing.T) {
+		req := &proto.SubmitTaskRequest{TaskId: taskID.String(), AssignmentToken: token.String()}
+		mockService.EXPECT().
+			SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Value: req.Result}).
+			Return(services.ErrLeaseNotHeld)
+
+		resp, err := s.SubmitTask(context.Background(), req)
+		require.Nil(t, resp)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
 	t.Run("internal error", func(t *testing.T) {
-		req := &proto.SubmitTaskRequest{Task: &proto.Task{}}
+		req := &proto.SubmitTaskRequest{TaskId: taskID.String(), AssignmentToken: token.String()}
 		mockService.EXPECT().
-			SetTaskResult(gomock.Any(), req.Task, req.Result).
+			SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Value: req.Result}).
 			Return(errors.New("unexpected"))
 
 		resp, err := s.SubmitTask(context.Background(), req)
@@ -62,10 +85,21 @@ func TestSubmitTask(t *testing.T) {
 		require.Equal(t, codes.Internal, status.Code(err))
 	})
 
+	t.Run("evaluation error", func(t *testing.T) {
+		req := &proto.SubmitTaskRequest{TaskId: taskID.String(), AssignmentToken: token.String(), Error: "division by zero"}
+		mockService.EXPECT().
+			SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Error: "division by zero"}).
+			Return(nil)
+
+		resp, err := s.SubmitTask(context.Background(), req)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+	})
+
 	t.Run("success", func(t *testing.T) {
-		req := &proto.SubmitTaskRequest{Task: &proto.Task{}}
+		req := &proto.SubmitTaskRequest{TaskId: taskID.String(), AssignmentToken: token.String()}
 		mockService.EXPECT().
-			SetTaskResult(gomock.Any(), req.Task, req.Result).
+			SetTaskResult(gomock.Any(), taskID, token, models.TaskResult{Value: req.Result}).
 			Return(nil)
 
 		resp, err := s.SubmitTask(context.Background(), req)
@@ -74,59 +108,185 @@ func TestSubmitTask(t *testing.T) {
 	})
 }
 
+func TestHeartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockExpressionTaskService(ctrl)
+	mockAgents := mocks.NewMockAgentService(ctrl)
+	s := server.NewServer(mockService, mockAgents, "localhost", 0)
+
+	held, lost := uuid.New(), uuid.New()
+
+	t.Run("invalid task id", func(t *testing.T) {
+		resp, err := s.Heartbeat(context.Background(), &proto.HeartbeatRequest{AgentId: "agent-1", TaskIds: []string{"abc"}})
+		require.Nil(t, resp)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("missing agent id", func(t *testing.T) {
+		resp, err := s.Heartbeat(context.Background(), &proto.HeartbeatRequest{TaskIds: []string{held.String()}})
+		require.Nil(t, resp)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unregistered agent", func(t *testing.T) {
+		mockAgents.EXPECT().TouchAgent(gomock.Any(), "agent-2").Return(services.ErrUnknownAgent)
+
+		resp, err := s.Heartbeat(context.Background(), &proto.HeartbeatRequest{AgentId: "agent-2", TaskIds: []string{held.String()}})
+		require.Nil(t, resp)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	mockAgents.EXPECT().TouchAgent(gomock.Any(), "agent-1").Return(nil).AnyTimes()
+
+	t.Run("database unavailable", func(t *testing.T) {
+		mockService.EXPECT().RenewLeases(gomock.Any(), "agent-1", []uuid.UUID{held}).Return(nil, services.ErrDatabaseUnavailable)
+
+		resp, err := s.Heartbeat(context.Background(), &proto.HeartbeatRequest{AgentId: "agent-1", TaskIds: []string{held.String()}})
+		require.Nil(t, resp)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		mockService.EXPECT().RenewLeases(gomock.Any(), "agent-1", []uuid.UUID{held}).Return(nil, errors.New("unexpected"))
+
+		resp, err := s.Heartbeat(context.Background(), &proto.HeartbeatRequest{AgentId: "agent-1", TaskIds: []string{held.String()}})
+		require.Nil(t, resp)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockService.EXPECT().RenewLeases(gomock.Any(), "agent-1", []uuid.UUID{held, lost}).Return([]uuid.UUID{lost}, nil)
+
+		resp, err := s.Heartbeat(context.Background(), &proto.HeartbeatRequest{
+			AgentId: "agent-1",
+			TaskIds: []string{held.String(), lost.String()},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{lost.String()}, resp.LostTaskIds)
+	})
+}
+
 func TestStart_ListenError(t *testing.T) {
-	mockService := mocks.NewMockExpressionTaskService(gomock.NewController(t))
-	s := server.NewServer(mockService, "invalid_host", -1)
+	ctrl := gomock.NewController(t)
+	s := server.NewServer(mocks.NewMockExpressionTaskService(ctrl), mocks.NewMockAgentService(ctrl), "invalid_host", -1)
 
 	err := s.Start()
 	require.Error(t, err)
 }
 
+type assignStream = mocks.MockOrchestratorService_AssignTasksServer[*proto.AssignTasksRequest, *proto.Task]
+
+func grantCredits(t *testing.T, stream *assignStream, credits ...int32) context.CancelFunc {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	stream.EXPECT().Context().Return(ctx).AnyTimes()
+	calls := make([]any, 0, len(credits)+1)
+	for _, n := range credits {
+		calls = append(calls, stream.EXPECT().Recv().Return(&proto.AssignTasksRequest{Credits: n, AgentId: "agent-1"}, nil))
+	}
+	calls = append(calls, stream.EXPECT().Recv().DoAndReturn(func() (*proto.AssignTasksRequest, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}).AnyTimes())
+	gomock.InOrder(calls...)
+	return cancel
+}
+
 func TestAssignTasks(t *testing.T) {
 	tests := []struct {
-		name       string
-		setupMock  func(*mocks.MockExpressionTaskService, *mocks.MockOrchestratorService_AssignTasksServer[*proto.Task])
-		wantErr    bool
-		errContain string
-		code       codes.Code
+		name      string
+		setupMock func(*testing.T, *mocks.MockExpressionTaskService, *assignStream)
+		wantErr   bool
+		code      codes.Code
 	}{
 		{
 			name: "successful stream",
-			setupMock: func(ets *mocks.MockExpressionTaskService, stream *mocks.MockOrchestratorService_AssignTasksServer[*proto.Task]) {
-				ctx, cancel := context.WithCancel(context.Background())
-				stream.EXPECT().Context().Return(ctx).AnyTimes()
+			setupMock: func(t *testing.T, ets *mocks.MockExpressionTaskService, stream *assignStream) {
+				cancel := grantCredits(t, stream, 2)
 
 				task := &proto.Task{Id: "123"}
-				ets.EXPECT().GetTask(gomock.Any()).Return(task, nil).Times(1)
+				ets.EXPECT().WaitTask(gomock.Any(), gomock.Any()).Return(task, nil).Times(1)
 				stream.EXPECT().Send(task).Return(nil).Times(1)
 
-				ets.EXPECT().GetTask(gomock.Any()).DoAndReturn(func(_ context.Context) (*proto.Task, error) {
+				ets.EXPECT().WaitTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string) (*proto.Task, error) {
 					cancel()
-					return nil, nil
+					return nil, context.Canceled
 				}).Times(1)
 			},
-			wantErr: false,
 		},
 		{
-			name: "GetTask returns error",
-			setupMock: func(ets *mocks.MockExpressionTaskService, stream *mocks.MockOrchestratorService_AssignTasksServer[*proto.Task]) {
+			name: "no tasks beyond granted credits",
+			setupMock: func(t *testing.T, ets *mocks.MockExpressionTaskService, stream *assignStream) {
 				stream.EXPECT().Context().Return(context.Background()).AnyTimes()
-				ets.EXPECT().GetTask(gomock.Any()).Return(nil, errors.New("internal error")).Times(1)
+				release := make(chan struct{})
+				gomock.InOrder(
+					stream.EXPECT().Recv().Return(&proto.AssignTasksRequest{Credits: 1, AgentId: "agent-1"}, nil),
+					stream.EXPECT().Recv().DoAndReturn(func() (*proto.AssignTasksRequest, error) {
+						<-release
+						return nil, io.EOF
+					}),
+				)
+
+				task := &proto.Task{Id: "123"}
+				ets.EXPECT().WaitTask(gomock.Any(), "agent-1").Return(task, nil).Times(1)
+				stream.EXPECT().Send(task).DoAndReturn(func(*proto.Task) error {
+					close(release)
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name: "WaitTask returns error",
+			setupMock: func(t *testing.T, ets *mocks.MockExpressionTaskService, stream *assignStream) {
+				grantCredits(t, stream, 1)
+				ets.EXPECT().WaitTask(gomock.Any(), gomock.Any()).Return(nil, errors.New("internal error")).Times(1)
 			},
 			wantErr: true,
 			code:    codes.Internal,
 		},
 		{
 			name: "Send returns error",
-			setupMock: func(ets *mocks.MockExpressionTaskService, stream *mocks.MockOrchestratorService_AssignTasksServer[*proto.Task]) {
-				stream.EXPECT().Context().Return(context.Background()).AnyTimes()
+			setupMock: func(t *testing.T, ets *mocks.MockExpressionTaskService, stream *assignStream) {
+				grantCredits(t, stream, 1)
 				task := &proto.Task{Id: "123"}
-				ets.EXPECT().GetTask(gomock.Any()).Return(task, nil).Times(1)
+				ets.EXPECT().WaitTask(gomock.Any(), gomock.Any()).Return(task, nil).Times(1)
 				stream.EXPECT().Send(task).Return(errors.New("unavailable")).Times(1)
+				ets.EXPECT().ReleaseTask(gomock.Any(), task).Return(nil).Times(1)
 			},
 			wantErr: true,
 			code:    codes.Unavailable,
 		},
+		{
+			name: "Recv returns error",
+			setupMock: func(t *testing.T, ets *mocks.MockExpressionTaskService, stream *assignStream) {
+				stream.EXPECT().Context().Return(context.Background()).AnyTimes()
+				stream.EXPECT().Recv().Return(nil, errors.New("broken stream"))
+			},
+			wantErr: true,
+			code:    codes.Unavailable,
+		},
+		{
+			name: "deadline exceeded while waiting",
+			setupMock: func(t *testing.T, ets *mocks.MockExpressionTaskService, stream *assignStream) {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				t.Cleanup(cancel)
+				stream.EXPECT().Context().Return(ctx).AnyTimes()
+				stream.EXPECT().Recv().Return(&proto.AssignTasksRequest{Credits: 1, AgentId: "agent-1"}, nil)
+				stream.EXPECT().Recv().DoAndReturn(func() (*proto.AssignTasksRequest, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}).AnyTimes()
+				ets.EXPECT().WaitTask(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ string) (*proto.Task, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}).Times(1)
+			},
+			wantErr: true,
+			code:    codes.DeadlineExceeded,
+		},
 	}
 
 	for _, tt := range tests {
@@ -135,26 +295,104 @@ func TestAssignTasks(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockETS := mocks.NewMockExpressionTaskService(ctrl)
-			mockStream := mocks.NewMockOrchestratorService_AssignTasksServer[*proto.Task](ctrl)
+			mockAgents := mocks.NewMockAgentService(ctrl)
+			mockAgents.EXPECT().TouchAgent(gomock.Any(), "agent-1").Return(nil).AnyTimes()
+			mockStream := mocks.NewMockOrchestratorService_AssignTasksServer[*proto.AssignTasksRequest, *proto.Task](ctrl)
 
-			tt.setupMock(mockETS, mockStream)
+			tt.setupMock(t, mockETS, mockStream)
 
-			srv := server.NewServer(mockETS, "localhost", 50051)
+			srv := server.NewServer(mockETS, mockAgents, "localhost", 50051)
 
-			err := srv.AssignTasks(&proto.AssignTasksRequest{}, mockStream)
+			err := srv.AssignTasks(mockStream)
 
 			if tt.wantErr {
 				require.Error(t, err)
-				if tt.errContain != "" {
-					require.Contains(t, err.Error(), tt.errContain)
-				}
-				if tt.code != 0 {
-					st, ok := status.FromError(err)
-					require.True(t, ok, "error should be a gRPC status error")
-					require.Equal(t, tt.code, st.Code())
-				}
+				st, ok := status.FromError(err)
+				require.True(t, ok, "error should be a gRPC status error")
+				require.Equal(t, tt.code, st.Code())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAssignTasks_Registration(t *testing.T) {
+	tests := []struct {
+		name    string
+		agentID string
+		touch   error
+		code    codes.Code
+	}{
+		{name: "missing agent id", code: codes.InvalidArgument},
+		{name: "unregistered agent", agentID: "agent-1", touch: services.ErrUnknownAgent, code: codes.FailedPrecondition},
+		{name: "database unavailable", agentID: "agent-1", touch: services.ErrDatabaseUnavailable, code: codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAgents := mocks.NewMockAgentService(ctrl)
+			if tt.agentID != "" {
+				mockAgents.EXPECT().TouchAgent(gomock.Any(), tt.agentID).Return(tt.touch)
+			}
+			stream := mocks.NewMockOrchestratorService_AssignTasksServer[*proto.AssignTasksRequest, *proto.Task](ctrl)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			stream.EXPECT().Context().Return(ctx).AnyTimes()
+			stream.EXPECT().Recv().Return(&proto.AssignTasksRequest{Credits: 1, AgentId: tt.agentID}, nil)
+			stream.EXPECT().Recv().DoAndReturn(func() (*proto.AssignTasksRequest, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}).AnyTimes()
+
+			srv := server.NewServer(mocks.NewMockExpressionTaskService(ctrl), mockAgents, "localhost", 0)
+			err := srv.AssignTasks(stream)
+			require.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestRegisterAgent(t *testing.T) {
+	req := &proto.RegisterAgentRequest{
+		AgentId:   "agent-1",
+		Hostname:  "host",
+		Version:   "1.0.0",
+		Capacity:  4,
+		Operators: []string{"+", "-"},
+	}
+	agent := &models.Agent{ID: "agent-1", Hostname: "host", Version: "1.0.0", Capacity: 4, Operators: []string{"+", "-"}}
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+		code    codes.Code
+	}{
+		{name: "success"},
+		{name: "invalid agent", err: services.ErrInvalidAgentCapacity, wantErr: true, code: codes.InvalidArgument},
+		{name: "database unavailable", err: services.ErrDatabaseUnavailable, wantErr: true, code: codes.Unavailable},
+		{name: "internal error", err: errors.New("unexpected"), wantErr: true, code: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAgents := mocks.NewMockAgentService(ctrl)
+			mockAgents.EXPECT().RegisterAgent(gomock.Any(), agent).Return(tt.err)
+			s := server.NewServer(mocks.NewMockExpressionTaskService(ctrl), mockAgents, "localhost", 0)
+
+			resp, err := s.RegisterAgent(context.Background(), req)
+			if tt.wantErr {
+				require.Nil(t, resp)
+				require.Equal(t, tt.code, status.Code(err))
 			} else {
 				require.NoError(t, err)
+				require.NotNil(t, resp)
 			}
 		})
 	}
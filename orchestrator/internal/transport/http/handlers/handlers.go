@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -32,13 +33,73 @@ type LoginResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
+type OptionsRequest struct {
+	Precision string `json:"precision,omitempty"`
+	Scale     *int   `json:"scale,omitempty"`
+	Policy    string `json:"policy,omitempty"`
+	Optimize  bool   `json:"optimize,omitempty"`
+	Lenient   bool   `json:"lenient,omitempty"`
+}
+
+func (r OptionsRequest) toOptions() services.CalculationOptions {
+	options := services.CalculationOptions{
+		Precision: models.Precision(r.Precision),
+		Scale:     services.DefaultDecimalScale,
+		Policy:    models.NumericPolicy(r.Policy),
+		Optimize:  r.Optimize,
+		Lenient:   r.Lenient,
+	}
+	if r.Scale != nil {
+		options.Scale = *r.Scale
+	}
+	return options
+}
+
 type CalculateRequest struct {
 	Expression string `json:"expression"`
+	OptionsRequest
+}
+
+type BatchCalculateRequest struct {
+	Expressions []string `json:"expressions"`
+	OptionsRequest
+}
+
+type BatchCalculateResponse struct {
+	Results []CalculateResponse `json:"results,omitempty"`
+	Error   string              `json:"error,omitempty"`
+}
+
+type ScriptRequest struct {
+	Script string `json:"script"`
+	Output string `json:"output,omitempty"`
+	OptionsRequest
 }
 
 type CalculateResponse struct {
-	ID    *uuid.UUID `json:"id,omitempty"`
-	Error string     `json:"error,omitempty"`
+	ID      *uuid.UUID                       `json:"id,omitempty"`
+	Error   string                           `json:"error,omitempty"`
+	Details *services.ExpressionErrorDetails `json:"details,omitempty"`
+}
+
+type ExplainResponse struct {
+	Explanation *services.Explanation            `json:"explanation,omitempty"`
+	Error       string                           `json:"error,omitempty"`
+	Details     *services.ExpressionErrorDetails `json:"details,omitempty"`
+}
+
+type SymbolicRequest struct {
+	Expression string                 `json:"expression"`
+	Variable   string                 `json:"variable,omitempty"`
+	Submit     bool                   `json:"submit,omitempty"`
+	Bindings   map[string]json.Number `json:"bindings,omitempty"`
+	OptionsRequest
+}
+
+type SymbolicResponse struct {
+	Result  *services.SymbolicResult         `json:"result,omitempty"`
+	Error   string                           `json:"error,omitempty"`
+	Details *services.ExpressionErrorDetails `json:"details,omitempty"`
 }
 
 type GetExpressionResponse struct {
@@ -50,24 +111,85 @@ type GetExpressionByIDResponse struct {
 	Error      string             `json:"error,omitempty"`
 }
 
+type GetExpressionTasksResponse struct {
+	Tasks []*models.TaskRecord `json:"tasks,omitempty"`
+	Error string               `json:"error,omitempty"`
+}
+
+type VariableRequest struct {
+	Name  string      `json:"name"`
+	Value json.Number `json:"value"`
+}
+
+type VariableResponse struct {
+	Variable *models.Variable `json:"variable,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+type GetVariablesResponse struct {
+	Variables []*models.Variable `json:"variables"`
+}
+
+type FunctionRequest struct {
+	Name   string   `json:"name"`
+	Params []string `json:"params"`
+	Body   string   `json:"body"`
+}
+
+type FunctionResponse struct {
+	Function *models.UserFunction             `json:"function,omitempty"`
+	Error    string                           `json:"error,omitempty"`
+	Details  *services.ExpressionErrorDetails `json:"details,omitempty"`
+}
+
+type GetFunctionsResponse struct {
+	Functions []*models.UserFunction `json:"functions"`
+}
+
+type GetAgentsResponse struct {
+	Agents []*models.Agent `json:"agents"`
+	Error  string          `json:"error,omitempty"`
+}
+
 type Handler interface {
 	Register(c echo.Context) error
 	Login(c echo.Context) error
 	Calculate(c echo.Context) error
+	CalculateBatch(c echo.Context) error
+	CalculateScript(c echo.Context) error
+	ExplainExpression(c echo.Context) error
+	DeriveExpression(c echo.Context) error
+	SimplifyExpression(c echo.Context) error
 	GetExpressions(c echo.Context) error
 	GetExpressionByID(c echo.Context) error
+	GetExpressionTasks(c echo.Context) error
+	CreateVariable(c echo.Context) error
+	GetVariables(c echo.Context) error
+	GetVariable(c echo.Context) error
+	UpdateVariable(c echo.Context) error
+	DeleteVariable(c echo.Context) error
+	DefineFunction(c echo.Context) error
+	GetFunctions(c echo.Context) error
+	DeleteFunction(c echo.Context) error
+	GetAgents(c echo.Context) error
 	Ping(c echo.Context) error
 }
 
 type handler struct {
 	userService       services.UserService
 	expressionService services.ExpressionTaskService
+	variableService   services.VariableService
+	functionService   services.UserFunctionService
+	agentService      services.AgentService
 }
 
-func NewHandler(userService services.UserService, expressionService services.ExpressionTaskService) Handler {
+func NewHandler(userService services.UserService, expressionService services.ExpressionTaskService, variableService services.VariableService, functionService services.UserFunctionService, agentService services.AgentService) Handler {
 	return &handler{
 		userService:       userService,
 		expressionService: expressionService,
+		variableService:   variableService,
+		functionService:   functionService,
+		agentService:      agentService,
 	}
 }
 
@@ -129,10 +251,14 @@ func (h *handler) Calculate(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, CalculateResponse{Error: "invalid request payload"})
 	}
 
-	expressionID, err := h.expressionService.CreateExpressionTask(c.Request().Context(), parsedUserID, request.Expression)
+	expressionID, err := h.expressionService.CreateExpressionTask(c.Request().Context(), parsedUserID, request.Expression, request.toOptions())
 	if err != nil {
-		if services.IsExpressionError(err) {
-			return c.JSON(http.StatusUnprocessableEntity, CalculateResponse{Error: err.Error()})
+		if errors.Is(err, services.ErrUnknownPrecision) || errors.Is(err, services.ErrInvalidScale) ||
+			errors.Is(err, services.ErrUnknownNumericPolicy) {
+			return c.JSON(http.StatusBadRequest, CalculateResponse{Error: err.Error()})
+		}
+		if details := services.DescribeExpressionError(err, request.Expression); details != nil {
+			return c.JSON(http.StatusUnprocessableEntity, CalculateResponse{Error: err.Error(), Details: details})
 		}
 		if errors.Is(err, services.ErrUnknownUserID) {
 			return c.JSON(http.StatusNotFound, CalculateResponse{Error: err.Error()})
@@ -145,6 +271,172 @@ func (h *handler) Calculate(c echo.Context) error {
 	return c.JSON(http.StatusCreated, CalculateResponse{ID: &expressionID})
 }
 
+func (h *handler) CalculateBatch(c echo.Context) error {
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, BatchCalculateResponse{Error: "unauthorized"})
+	}
+
+	var request BatchCalculateRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, BatchCalculateResponse{Error: "invalid request payload"})
+	}
+
+	items, err := h.expressionService.CreateExpressionTasks(c.Request().Context(), parsedUserID, request.Expressions, request.toOptions())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBatchSize) || errors.Is(err, services.ErrUnknownPrecision) ||
+			errors.Is(err, services.ErrInvalidScale) || errors.Is(err, services.ErrUnknownNumericPolicy) {
+			return c.JSON(http.StatusBadRequest, BatchCalculateResponse{Error: err.Error()})
+		}
+		if errors.Is(err, services.ErrUnknownUserID) {
+			return c.JSON(http.StatusNotFound, BatchCalculateResponse{Error: err.Error()})
+		}
+		if errors.Is(err, services.ErrDatabaseUnavailable) {
+			return c.JSON(http.StatusServiceUnavailable, BatchCalculateResponse{Error: "service temporarily unavailable"})
+		}
+		return c.JSON(http.StatusInternalServerError, BatchCalculateResponse{Error: "internal server error"})
+	}
+
+	results := make([]CalculateResponse, len(items))
+	for i, item := range items {
+		if item.Err != nil {
+			results[i] = CalculateResponse{
+				Error:   item.Err.Error(),
+				Details: services.DescribeExpressionError(item.Err, request.Expressions[i]),
+			}
+			continue
+		}
+		results[i] = CalculateResponse{ID: &items[i].ID}
+	}
+	return c.JSON(http.StatusOK, BatchCalculateResponse{Results: results})
+}
+
+func (h *handler) CalculateScript(c echo.Context) error {
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, CalculateResponse{Error: "unauthorized"})
+	}
+
+	var request ScriptRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, CalculateResponse{Error: "invalid request payload"})
+	}
+	if request.Script == "" {
+		return c.JSON(http.StatusBadRequest, CalculateResponse{Error: "invalid request payload"})
+	}
+
+	expressionID, err := h.expressionService.CreateScriptTask(c.Request().Context(), parsedUserID, request.Script,
+		services.ScriptOutput(request.Output), request.toOptions())
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownPrecision) || errors.Is(err, services.ErrInvalidScale) ||
+			errors.Is(err, services.ErrUnknownNumericPolicy) || errors.Is(err, services.ErrUnknownScriptOutput) {
+			return c.JSON(http.StatusBadRequest, CalculateResponse{Error: err.Error()})
+		}
+		if details := services.DescribeExpressionError(err, request.Script); details != nil {
+			return c.JSON(http.StatusUnprocessableEntity, CalculateResponse{Error: err.Error(), Details: details})
+		}
+		if errors.Is(err, services.ErrUnknownUserID) {
+			return c.JSON(http.StatusNotFound, CalculateResponse{Error: err.Error()})
+		}
+		if errors.Is(err, services.ErrDatabaseUnavailable) {
+			return c.JSON(http.StatusServiceUnavailable, CalculateResponse{Error: "service temporarily unavailable"})
+		}
+		return c.JSON(http.StatusInternalServerError, CalculateResponse{Error: "internal server error"})
+	}
+	return c.JSON(http.StatusCreated, CalculateResponse{ID: &expressionID})
+}
+
+func (h *handler) ExplainExpression(c echo.Context) error {
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, ExplainResponse{Error: "unauthorized"})
+	}
+
+	var request CalculateRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, ExplainResponse{Error: "invalid request payload"})
+	}
+	if request.Expression == "" {
+		return c.JSON(http.StatusBadRequest, ExplainResponse{Error: "invalid request payload"})
+	}
+
+	explanation, err := h.expressionService.ExplainExpression(c.Request().Context(), parsedUserID, request.Expression, request.toOptions())
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownPrecision) || errors.Is(err, services.ErrInvalidScale) ||
+			errors.Is(err, services.ErrUnknownNumericPolicy) {
+			return c.JSON(http.StatusBadRequest, ExplainResponse{Error: err.Error()})
+		}
+		if details := services.DescribeExpressionError(err, request.Expression); details != nil {
+			return c.JSON(http.StatusUnprocessableEntity, ExplainResponse{Error: err.Error(), Details: details})
+		}
+		if errors.Is(err, services.ErrDatabaseUnavailable) {
+			return c.JSON(http.StatusServiceUnavailable, ExplainResponse{Error: "service temporarily unavailable"})
+		}
+		return c.JSON(http.StatusInternalServerError, ExplainResponse{Error: "internal server error"})
+	}
+	return c.JSON(http.StatusOK, ExplainResponse{Explanation: explanation})
+}
+
+func (h *handler) DeriveExpression(c echo.Context) error {
+	return h.symbolic(c, true)
+}
+
+func (h *handler) SimplifyExpression(c echo.Context) error {
+	return h.symbolic(c, false)
+}
+
+func (h *handler) symbolic(c echo.Context, derive bool) error {
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, SymbolicResponse{Error: "unauthorized"})
+	}
+
+	var request SymbolicRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, SymbolicResponse{Error: "invalid request payload"})
+	}
+	if request.Expression == "" || derive && request.Variable == "" {
+		return c.JSON(http.StatusBadRequest, SymbolicResponse{Error: "invalid request payload"})
+	}
+
+	options := services.SymbolicOptions{
+		CalculationOptions: request.toOptions(),
+		Submit:             request.Submit,
+		Bindings:           make(map[string]string, len(request.Bindings)),
+	}
+	for name, value := range request.Bindings {
+		options.Bindings[name] = value.String()
+	}
+
+	var result *services.SymbolicResult
+	if derive {
+		result, err = h.expressionService.DeriveExpression(c.Request().Context(), parsedUserID, request.Expression, request.Variable, options)
+	} else {
+		result, err = h.expressionService.SimplifyExpression(c.Request().Context(), parsedUserID, request.Expression, options)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownPrecision) || errors.Is(err, services.ErrInvalidScale) ||
+			errors.Is(err, services.ErrUnknownNumericPolicy) || errors.Is(err, services.ErrInvalidVariableName) ||
+			errors.Is(err, services.ErrInvalidVariableValue) {
+			return c.JSON(http.StatusBadRequest, SymbolicResponse{Error: err.Error()})
+		}
+		if details := services.DescribeExpressionError(err, request.Expression); details != nil {
+			return c.JSON(http.StatusUnprocessableEntity, SymbolicResponse{Error: err.Error(), Details: details})
+		}
+		if errors.Is(err, services.ErrUnknownUserID) {
+			return c.JSON(http.StatusNotFound, SymbolicResponse{Error: err.Error()})
+		}
+		if errors.Is(err, services.ErrDatabaseUnavailable) {
+			return c.JSON(http.StatusServiceUnavailable, SymbolicResponse{Error: "service temporarily unavailable"})
+		}
+		return c.JSON(http.StatusInternalServerError, SymbolicResponse{Error: "internal server error"})
+	}
+	if result.ID != nil {
+		return c.JSON(http.StatusCreated, SymbolicResponse{Result: result})
+	}
+	return c.JSON(http.StatusOK, SymbolicResponse{Result: result})
+}
+
 func (h *handler) GetExpressions(c echo.Context) error {
 	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
 	if err != nil {
@@ -196,6 +488,204 @@ func (h *handler) GetExpressionByID(c echo.Context) error {
 	return c.JSON(http.StatusOK, GetExpressionByIDResponse{Expression: expression})
 }
 
+func (h *handler) GetExpressionTasks(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil || id == uuid.Nil {
+		return c.JSON(http.StatusBadRequest, GetExpressionTasksResponse{Error: "invalid request payload"})
+	}
+
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, GetExpressionTasksResponse{Error: "unauthorized"})
+	}
+	ctx := context.WithValue(c.Request().Context(), "user_id", parsedUserID)
+	tasks, err := h.expressionService.GetExpressionTasks(ctx, id)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownExpressionsID) {
+			return c.JSON(http.StatusNotFound, GetExpressionTasksResponse{Error: err.Error()})
+		}
+		if errors.Is(err, services.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, GetExpressionTasksResponse{Error: "you are not allowed to access this expression"})
+		}
+		if errors.Is(err, services.ErrDatabaseUnavailable) {
+			return c.JSON(http.StatusServiceUnavailable, GetExpressionTasksResponse{Error: "service temporarily unavailable"})
+		}
+		return c.JSON(http.StatusInternalServerError, GetExpressionTasksResponse{Error: "internal server error"})
+	}
+	return c.JSON(http.StatusOK, GetExpressionTasksResponse{Tasks: tasks})
+}
+
+func (h *handler) CreateVariable(c echo.Context) error {
+	var request VariableRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, VariableResponse{Error: "invalid request payload"})
+	}
+	if request.Name == "" || request.Value == "" {
+		return c.JSON(http.StatusBadRequest, VariableResponse{Error: "name and value should not be empty"})
+	}
+
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, VariableResponse{Error: "unauthorized"})
+	}
+
+	variable, err := h.variableService.CreateVariable(c.Request().Context(), parsedUserID, request.Name, request.Value.String())
+	if err != nil {
+		return variableError(c, err)
+	}
+	return c.JSON(http.StatusCreated, VariableResponse{Variable: variable})
+}
+
+func (h *handler) GetVariables(c echo.Context) error {
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, VariableResponse{Error: "unauthorized"})
+	}
+
+	variables, err := h.variableService.GetVariables(c.Request().Context(), parsedUserID)
+	if err != nil {
+		return variableError(c, err)
+	}
+	return c.JSON(http.StatusOK, GetVariablesResponse{Variables: variables})
+}
+
+func (h *handler) GetVariable(c echo.Context) error {
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, VariableResponse{Error: "unauthorized"})
+	}
+
+	variable, err := h.variableService.GetVariable(c.Request().Context(), parsedUserID, c.Param("name"))
+	if err != nil {
+		return variableError(c, err)
+	}
+	return c.JSON(http.StatusOK, VariableResponse{Variable: variable})
+}
+
+func (h *handler) UpdateVariable(c echo.Context) error {
+	var request VariableRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, VariableResponse{Error: "invalid request payload"})
+	}
+	if request.Value == "" {
+		return c.JSON(http.StatusBadRequest, VariableResponse{Error: "value should not be empty"})
+	}
+
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, VariableResponse{Error: "unauthorized"})
+	}
+
+	variable, err := h.variableService.UpdateVariable(c.Request().Context(), parsedUserID, c.Param("name"), request.Value.String())
+	if err != nil {
+		return variableError(c, err)
+	}
+	return c.JSON(http.StatusOK, VariableResponse{Variable: variable})
+}
+
+func (h *handler) DeleteVariable(c echo.Context) error {
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, VariableResponse{Error: "unauthorized"})
+	}
+
+	if err := h.variableService.DeleteVariable(c.Request().Context(), parsedUserID, c.Param("name")); err != nil {
+		return variableError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func variableError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidVariableName), errors.Is(err, services.ErrInvalidVariableValue), errors.Is(err, services.ErrVariableAlreadyExists):
+		return c.JSON(http.StatusUnprocessableEntity, VariableResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrUnknownVariable):
+		return c.JSON(http.StatusNotFound, VariableResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrUnknownUserID):
+		return c.JSON(http.StatusUnauthorized, VariableResponse{Error: "unauthorized"})
+	case errors.Is(err, services.ErrDatabaseUnavailable):
+		return c.JSON(http.StatusServiceUnavailable, VariableResponse{Error: "service temporarily unavailable"})
+	}
+	return c.JSON(http.StatusInternalServerError, VariableResponse{Error: "internal server error"})
+}
+
+func (h *handler) DefineFunction(c echo.Context) error {
+	var request FunctionRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, FunctionResponse{Error: "invalid request payload"})
+	}
+	if request.Name == "" || request.Body == "" {
+		return c.JSON(http.StatusBadRequest, FunctionResponse{Error: "name and body should not be empty"})
+	}
+
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, FunctionResponse{Error: "unauthorized"})
+	}
+
+	function, err := h.functionService.DefineFunction(c.Request().Context(), parsedUserID, request.Name, request.Params, request.Body)
+	if err != nil {
+		if services.IsExpressionError(err) {
+			return c.JSON(http.StatusUnprocessableEntity, FunctionResponse{
+				Error:   err.Error(),
+				Details: services.DescribeExpressionError(err, request.Body),
+			})
+		}
+		return functionError(c, err)
+	}
+	return c.JSON(http.StatusCreated, FunctionResponse{Function: function})
+}
+
+func (h *handler) GetFunctions(c echo.Context) error {
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, FunctionResponse{Error: "unauthorized"})
+	}
+
+	functions, err := h.functionService.GetFunctions(c.Request().Context(), parsedUserID)
+	if err != nil {
+		return functionError(c, err)
+	}
+	return c.JSON(http.StatusOK, GetFunctionsResponse{Functions: functions})
+}
+
+func (h *handler) DeleteFunction(c echo.Context) error {
+	parsedUserID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, FunctionResponse{Error: "unauthorized"})
+	}
+
+	if err := h.functionService.DeleteFunction(c.Request().Context(), parsedUserID, c.Param("name")); err != nil {
+		return functionError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func functionError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidFunctionName), errors.Is(err, services.ErrInvalidFunctionParameters), errors.Is(err, services.ErrFunctionAlreadyExists):
+		return c.JSON(http.StatusUnprocessableEntity, FunctionResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrUnknownFunction):
+		return c.JSON(http.StatusNotFound, FunctionResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrUnknownUserID):
+		return c.JSON(http.StatusUnauthorized, FunctionResponse{Error: "unauthorized"})
+	case errors.Is(err, services.ErrDatabaseUnavailable):
+		return c.JSON(http.StatusServiceUnavailable, FunctionResponse{Error: "service temporarily unavailable"})
+	}
+	return c.JSON(http.StatusInternalServerError, FunctionResponse{Error: "internal server error"})
+}
+
+func (h *handler) GetAgents(c echo.Context) error {
+	agents, err := h.agentService.GetLiveAgents(c.Request().Context())
+	if err != nil {
+		if errors.Is(err, services.ErrDatabaseUnavailable) {
+			return c.JSON(http.StatusServiceUnavailable, GetAgentsResponse{Error: "service temporarily unavailable"})
+		}
+		return c.JSON(http.StatusInternalServerError, GetAgentsResponse{Error: "internal server error"})
+	}
+	return c.JSON(http.StatusOK, GetAgentsResponse{Agents: agents})
+}
+
 func (h *handler) Ping(c echo.Context) error {
 	return c.String(http.StatusOK, "pong")
 }
@@ -2,11 +2,14 @@ package handlers_test
 
 import (
 	"errors"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/expr"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/services"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/http/handlers"
@@ -24,7 +27,9 @@ func TestHandler_Register(t *testing.T) {
 
 	mockUserService := mocks.NewMockUserService(ctrl)
 	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
-	h := handlers.NewHandler(mockUserService, mockExpressionService)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
 
 	validPassword := "ValidPass123!"
 	weakPassword := "weak"
@@ -137,7 +142,9 @@ func TestHandler_Login(t *testing.T) {
 
 	mockUserService := mocks.NewMockUserService(ctrl)
 	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
-	h := handlers.NewHandler(mockUserService, mockExpressionService)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
 
 	validPassword := "ValidPass123!"
 
@@ -239,7 +246,9 @@ func TestHandler_Calculate(t *testing.T) {
 
 	mockUserService := mocks.NewMockUserService(ctrl)
 	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
-	h := handlers.NewHandler(mockUserService, mockExpressionService)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
 
 	testUserID := uuid.New()
 	expressionID := uuid.New()
@@ -258,12 +267,113 @@ func TestHandler_Calculate(t *testing.T) {
 			requestBody: `{"expression":"2+2"}`,
 			mockSetup: func() {
 				mockExpressionService.EXPECT().
-					CreateExpressionTask(gomock.Any(), testUserID, "2+2").
+					CreateExpressionTask(gomock.Any(), testUserID, "2+2", gomock.Any()).
 					Return(expressionID, nil)
 			},
 			expectedStatus: http.StatusCreated,
 			expectedBody:   `{"id":"` + expressionID.String() + `"}` + "\n",
 		},
+		{
+			name:        "decimal precision with default scale",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"0.1+0.2","precision":"decimal"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					CreateExpressionTask(gomock.Any(), testUserID, "0.1+0.2", services.CalculationOptions{
+						Precision: models.PrecisionDecimal,
+						Scale:     services.DefaultDecimalScale,
+					}).
+					Return(expressionID, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"` + expressionID.String() + `"}` + "\n",
+		},
+		{
+			name:        "decimal precision with explicit scale",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"0.1+0.2","precision":"decimal","scale":2}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					CreateExpressionTask(gomock.Any(), testUserID, "0.1+0.2", services.CalculationOptions{
+						Precision: models.PrecisionDecimal,
+						Scale:     2,
+					}).
+					Return(expressionID, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"` + expressionID.String() + `"}` + "\n",
+		},
+		{
+			name:        "optimisation requested",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"2*3+x","optimize":true}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					CreateExpressionTask(gomock.Any(), testUserID, "2*3+x", services.CalculationOptions{
+						Precision: "",
+						Scale:     services.DefaultDecimalScale,
+						Optimize:  true,
+					}).
+					Return(expressionID, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"` + expressionID.String() + `"}` + "\n",
+		},
+		{
+			name:        "lenient parsing requested",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"2(3+4)","lenient":true}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					CreateExpressionTask(gomock.Any(), testUserID, "2(3+4)", services.CalculationOptions{
+						Precision: "",
+						Scale:     services.DefaultDecimalScale,
+						Lenient:   true,
+					}).
+					Return(expressionID, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"` + expressionID.String() + `"}` + "\n",
+		},
+		{
+			name:        "invalid scale",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"0.1+0.2","precision":"decimal","scale":500}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					CreateExpressionTask(gomock.Any(), testUserID, "0.1+0.2", gomock.Any()).
+					Return(uuid.Nil, services.ErrInvalidScale)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"scale must be between 0 and 100"}` + "\n",
+		},
+		{
+			name:        "numeric policy requested",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"1e308*10","policy":"saturate"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					CreateExpressionTask(gomock.Any(), testUserID, "1e308*10", services.CalculationOptions{
+						Scale:  services.DefaultDecimalScale,
+						Policy: models.PolicySaturate,
+					}).
+					Return(expressionID, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"` + expressionID.String() + `"}` + "\n",
+		},
+		{
+			name:        "unknown numeric policy",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"2+2","policy":"wrap"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					CreateExpressionTask(gomock.Any(), testUserID, "2+2", gomock.Any()).
+					Return(uuid.Nil, services.ErrUnknownNumericPolicy)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"policy must be one of \"strict\", \"ieee\" or \"saturate\""}` + "\n",
+		},
 		{
 			name:           "invalid user id",
 			userID:         "invalid",
@@ -286,11 +396,23 @@ func TestHandler_Calculate(t *testing.T) {
 			requestBody: `{"expression":"invalid"}`,
 			mockSetup: func() {
 				mockExpressionService.EXPECT().
-					CreateExpressionTask(gomock.Any(), testUserID, "invalid").
+					CreateExpressionTask(gomock.Any(), testUserID, "invalid", gomock.Any()).
 					Return(uuid.Nil, services.ErrInvalidExpression)
 			},
 			expectedStatus: http.StatusUnprocessableEntity,
-			expectedBody:   `{"error":"invalid expression"}` + "\n",
+			expectedBody:   `{"error":"invalid expression","details":{"code":"INVALID_EXPRESSION","message":"invalid expression","start":0,"end":7}}` + "\n",
+		},
+		{
+			name:        "expression error with position",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"2+*3"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					CreateExpressionTask(gomock.Any(), testUserID, "2+*3", gomock.Any()).
+					Return(uuid.Nil, expr.NewError(services.ErrOperatorIssue, 2, 3, "*", "number", "("))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"consecutive or misplaced operators at position 2: \"*\"","details":{"code":"MISPLACED_OPERATOR","message":"consecutive or misplaced operators","start":2,"end":3,"token":"*","expected":["number","("]}}` + "\n",
 		},
 		{
 			name:        "unknown user id",
@@ -298,7 +420,7 @@ func TestHandler_Calculate(t *testing.T) {
 			requestBody: `{"expression":"2+2"}`,
 			mockSetup: func() {
 				mockExpressionService.EXPECT().
-					CreateExpressionTask(gomock.Any(), testUserID, "2+2").
+					CreateExpressionTask(gomock.Any(), testUserID, "2+2", gomock.Any()).
 					Return(uuid.Nil, services.ErrUnknownUserID)
 			},
 			expectedStatus: http.StatusNotFound,
@@ -310,7 +432,7 @@ func TestHandler_Calculate(t *testing.T) {
 			requestBody: `{"expression":"2+2"}`,
 			mockSetup: func() {
 				mockExpressionService.EXPECT().
-					CreateExpressionTask(gomock.Any(), testUserID, "2+2").
+					CreateExpressionTask(gomock.Any(), testUserID, "2+2", gomock.Any()).
 					Return(uuid.Nil, services.ErrDatabaseUnavailable)
 			},
 			expectedStatus: http.StatusServiceUnavailable,
@@ -345,82 +467,101 @@ func TestHandler_Calculate(t *testing.T) {
 	}
 }
 
-func TestHandler_GetExpressions(t *testing.T) {
+func TestHandler_CalculateBatch(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockUserService := mocks.NewMockUserService(ctrl)
 	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
-	h := handlers.NewHandler(mockUserService, mockExpressionService)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
 
 	testUserID := uuid.New()
-	expressions := []*models.Expression{
-		{
-			ID:         uuid.MustParse("b466ca50-0158-494a-b278-43375c6e3c34"),
-			UserID:     uuid.Nil,
-			Expression: "2+2",
-			Status:     "completed",
-			Result:     4,
-		},
-	}
+	firstID := uuid.New()
+	secondID := uuid.New()
 
 	tests := []struct {
 		name           string
 		userID         string
+		requestBody    string
 		mockSetup      func()
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
-			name:   "successful get expressions",
-			userID: testUserID.String(),
+			name:        "per-item results",
+			userID:      testUserID.String(),
+			requestBody: `{"expressions":["2+2","2**","3*4"],"policy":"ieee"}`,
 			mockSetup: func() {
 				mockExpressionService.EXPECT().
-					GetAllExpressions(gomock.Any(), testUserID).
-					Return(expressions, nil)
+					CreateExpressionTasks(gomock.Any(), testUserID, []string{"2+2", "2**", "3*4"}, services.CalculationOptions{
+						Scale:  services.DefaultDecimalScale,
+						Policy: models.PolicyIEEE,
+					}).
+					Return([]services.BatchItem{
+						{ID: firstID},
+						{Err: expr.NewError(services.ErrOperatorIssue, 2, 3, "*", "number")},
+						{ID: secondID},
+					}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"expressions":[{"id":"b466ca50-0158-494a-b278-43375c6e3c34","user_id":"00000000-0000-0000-0000-000000000000","expression":"2+2","status":"completed","result":4}]}` + "\n",
+			expectedBody: `{"results":[{"id":"` + firstID.String() + `"},` +
+				`{"error":"consecutive or misplaced operators at position 2: \"*\"","details":{"code":"MISPLACED_OPERATOR",` +
+				`"message":"consecutive or misplaced operators","start":2,"end":3,"token":"*","expected":["number"]}},` +
+				`{"id":"` + secondID.String() + `"}]}` + "\n",
 		},
 		{
-			name:           "invalid user id",
-			userID:         "invalid",
-			mockSetup:      func() {},
-			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"unauthorized"}` + "\n",
+			name:        "batch too large",
+			userID:      testUserID.String(),
+			requestBody: `{"expressions":[]}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					CreateExpressionTasks(gomock.Any(), testUserID, []string{}, gomock.Any()).
+					Return(nil, services.ErrInvalidBatchSize)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"batch must contain between 1 and 1000 expressions"}` + "\n",
 		},
 		{
-			name:   "unknown user id",
-			userID: testUserID.String(),
+			name:        "unknown user",
+			userID:      testUserID.String(),
+			requestBody: `{"expressions":["2+2"]}`,
 			mockSetup: func() {
 				mockExpressionService.EXPECT().
-					GetAllExpressions(gomock.Any(), testUserID).
+					CreateExpressionTasks(gomock.Any(), testUserID, []string{"2+2"}, gomock.Any()).
 					Return(nil, services.ErrUnknownUserID)
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"unauthorized"}` + "\n",
+			expectedBody:   `{"error":"unknown user id"}` + "\n",
 		},
 		{
-			name:   "database unavailable when getting expressions",
-			userID: testUserID.String(),
+			name:        "database unavailable",
+			userID:      testUserID.String(),
+			requestBody: `{"expressions":["2+2"]}`,
 			mockSetup: func() {
 				mockExpressionService.EXPECT().
-					GetAllExpressions(gomock.Any(), testUserID).
+					CreateExpressionTasks(gomock.Any(), testUserID, []string{"2+2"}, gomock.Any()).
 					Return(nil, services.ErrDatabaseUnavailable)
 			},
 			expectedStatus: http.StatusServiceUnavailable,
 			expectedBody:   `{"error":"service temporarily unavailable"}` + "\n",
 		},
 		{
-			name:   "internal server error when getting expressions",
-			userID: testUserID.String(),
-			mockSetup: func() {
-				mockExpressionService.EXPECT().
-					GetAllExpressions(gomock.Any(), testUserID).
-					Return(nil, errors.New("some unexpected error"))
-			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"internal server error"}` + "\n",
+			name:           "bind error - invalid json",
+			userID:         testUserID.String(),
+			requestBody:    `{"expressions":"2+2"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request payload"}` + "\n",
+		},
+		{
+			name:           "unauthorized",
+			userID:         "invalid",
+			requestBody:    `{"expressions":["2+2"]}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"unauthorized"}` + "\n",
 		},
 	}
 
@@ -429,12 +570,13 @@ func TestHandler_GetExpressions(t *testing.T) {
 			tt.mockSetup()
 
 			e := echo.New()
-			req := httptest.NewRequest(http.MethodGet, "/expressions", nil)
+			req := httptest.NewRequest(http.MethodPost, "/calculate/batch", strings.NewReader(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 			c.Set("user_id", tt.userID)
 
-			err := h.GetExpressions(c)
+			err := h.CalculateBatch(c)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			assert.Equal(t, tt.expectedBody, rec.Body.String())
@@ -442,99 +584,106 @@ func TestHandler_GetExpressions(t *testing.T) {
 	}
 }
 
-func TestHandler_GetExpressionByID(t *testing.T) {
+func TestHandler_CalculateScript(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockUserService := mocks.NewMockUserService(ctrl)
 	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
-	h := handlers.NewHandler(mockUserService, mockExpressionService)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
 
-	expressionID := uuid.MustParse("b85cdb62-8d5c-435f-b921-35bbf229e822")
-	expression := &models.Expression{
-		ID:         expressionID,
-		UserID:     uuid.Nil,
-		Expression: "2+2",
-		Status:     "completed",
-		Result:     4,
-	}
+	testUserID := uuid.New()
+	expressionID := uuid.New()
 
 	tests := []struct {
 		name           string
-		idParam        string
 		userID         string
+		requestBody    string
 		mockSetup      func()
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
-			name:    "successful get expression by id",
-			idParam: expressionID.String(),
-			userID:  uuid.New().String(),
+			name:        "successful script",
+			userID:      testUserID.String(),
+			requestBody: `{"script":"a = 3*4; b = a + 2; b / 7"}`,
 			mockSetup: func() {
 				mockExpressionService.EXPECT().
-					GetExpressionById(gomock.Any(), expressionID).
-					Return(expression, nil)
+					CreateScriptTask(gomock.Any(), testUserID, "a = 3*4; b = a + 2; b / 7", services.ScriptOutput(""), services.CalculationOptions{
+						Scale: services.DefaultDecimalScale,
+					}).
+					Return(expressionID, nil)
 			},
-			expectedStatus: http.StatusOK,
-			expectedBody:   `{"expression":{"id":"b85cdb62-8d5c-435f-b921-35bbf229e822","user_id":"00000000-0000-0000-0000-000000000000","expression":"2+2","status":"completed","result":4}}` + "\n",
-		},
-		{
-			name:           "invalid id",
-			idParam:        "invalid",
-			userID:         uuid.New().String(),
-			mockSetup:      func() {},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"error":"invalid request payload"}` + "\n",
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"` + expressionID.String() + `"}` + "\n",
 		},
 		{
-			name:    "expression not found",
-			idParam: expressionID.String(),
-			userID:  uuid.New().String(),
+			name:        "all values with options",
+			userID:      testUserID.String(),
+			requestBody: `{"script":"a = 0.1+0.2; a*2","output":"all","precision":"decimal","scale":4,"optimize":true}`,
 			mockSetup: func() {
 				mockExpressionService.EXPECT().
-					GetExpressionById(gomock.Any(), expressionID).
-					Return(nil, services.ErrUnknownExpressionsID)
+					CreateScriptTask(gomock.Any(), testUserID, "a = 0.1+0.2; a*2", services.ScriptOutputAll, services.CalculationOptions{
+						Precision: models.PrecisionDecimal,
+						Scale:     4,
+						Optimize:  true,
+					}).
+					Return(expressionID, nil)
 			},
-			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"error":"unknown expressions id"}` + "\n",
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"` + expressionID.String() + `"}` + "\n",
 		},
 		{
-			name:    "database unavailable when getting expression by id",
-			idParam: expressionID.String(),
-			userID:  uuid.New().String(),
+			name:        "unknown output",
+			userID:      testUserID.String(),
+			requestBody: `{"script":"a = 1+2; a","output":"first"}`,
 			mockSetup: func() {
 				mockExpressionService.EXPECT().
-					GetExpressionById(gomock.Any(), expressionID).
-					Return(nil, services.ErrDatabaseUnavailable)
+					CreateScriptTask(gomock.Any(), testUserID, "a = 1+2; a", services.ScriptOutput("first"), gomock.Any()).
+					Return(uuid.Nil, services.ErrUnknownScriptOutput)
 			},
-			expectedStatus: http.StatusServiceUnavailable,
-			expectedBody:   `{"error":"service temporarily unavailable"}` + "\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"output must be either \"last\" or \"all\""}` + "\n",
 		},
 		{
-			name:    "internal server error when getting expression by id",
-			idParam: expressionID.String(),
-			userID:  uuid.New().String(),
+			name:        "reassigned name",
+			userID:      testUserID.String(),
+			requestBody: `{"script":"a = 1+2; a = 3"}`,
 			mockSetup: func() {
 				mockExpressionService.EXPECT().
-					GetExpressionById(gomock.Any(), expressionID).
-					Return(nil, errors.New("some unexpected error"))
+					CreateScriptTask(gomock.Any(), testUserID, "a = 1+2; a = 3", gomock.Any(), gomock.Any()).
+					Return(uuid.Nil, expr.NewError(services.ErrNameReassigned, 9, 10, "a"))
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"error":"internal server error"}` + "\n",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: `{"error":"name is already assigned earlier in the script at position 9: \"a\"",` +
+				`"details":{"code":"NAME_REASSIGNED","message":"name is already assigned earlier in the script","start":9,"end":10,"token":"a"}}` + "\n",
 		},
 		{
-			name:           "nil uuid",
-			idParam:        "00000000-0000-0000-0000-000000000000",
-			userID:         uuid.New().String(),
+			name:           "empty script",
+			userID:         testUserID.String(),
+			requestBody:    `{"script":""}`,
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   `{"error":"invalid request payload"}` + "\n",
 		},
 		{
-			name:           "invalid user id",
-			idParam:        expressionID.String(),
+			name:        "database unavailable",
+			userID:      testUserID.String(),
+			requestBody: `{"script":"a = 1+2; a"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					CreateScriptTask(gomock.Any(), testUserID, "a = 1+2; a", gomock.Any(), gomock.Any()).
+					Return(uuid.Nil, services.ErrDatabaseUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"service temporarily unavailable"}` + "\n",
+		},
+		{
+			name:           "unauthorized",
 			userID:         "invalid",
+			requestBody:    `{"script":"a = 1+2; a"}`,
 			mockSetup:      func() {},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   `{"error":"unauthorized"}` + "\n",
@@ -546,14 +695,1273 @@ func TestHandler_GetExpressionByID(t *testing.T) {
 			tt.mockSetup()
 
 			e := echo.New()
-			req := httptest.NewRequest(http.MethodGet, "/expressions/"+tt.idParam, nil)
+			req := httptest.NewRequest(http.MethodPost, "/calculate/script", strings.NewReader(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
-			c.SetParamNames("id")
-			c.SetParamValues(tt.idParam)
 			c.Set("user_id", tt.userID)
 
-			err := h.GetExpressionByID(c)
+			err := h.CalculateScript(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_ExplainExpression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	testUserID := uuid.New()
+	taskID := uuid.New()
+	completion := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	two := models.Number(2)
+
+	tests := []struct {
+		name           string
+		userID         string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "successful explanation",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"2+2","optimize":false}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					ExplainExpression(gomock.Any(), testUserID, "2+2", services.CalculationOptions{Scale: services.DefaultDecimalScale}).
+					Return(&services.Explanation{
+						Expression: "2+2",
+						Tokens: []expr.Token{
+							{Kind: expr.Number, Text: "2", Pos: 0},
+							{Kind: expr.Operator, Text: "+", Pos: 1},
+							{Kind: expr.Number, Text: "2", Pos: 2},
+						},
+						Postfix: []string{"2", "2", "+"},
+						Tasks: []*services.PlannedTask{{
+							ID: taskID, Operator: "+", Args: []services.PlannedArgument{{Value: &two}, {Value: &two}},
+							DependsOn: []uuid.UUID{}, Final: true, DurationMS: 100, FinishMS: 100,
+						}},
+						CriticalPath:        []uuid.UUID{taskID},
+						CriticalPathLength:  1,
+						EstimatedDurationMS: 100,
+						EstimatedCompletion: completion,
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"explanation":{"expression":"2+2","tokens":[{"kind":"number","text":"2","pos":0},` +
+				`{"kind":"operator","text":"+","pos":1},{"kind":"number","text":"2","pos":2}],"postfix":["2","2","+"],` +
+				`"tasks":[{"id":"` + taskID.String() + `","operator":"+","args":[{"value":2},{"value":2}],"depends_on":[],` +
+				`"final":true,"duration_ms":100,"start_ms":0,"finish_ms":100}],"critical_path":["` + taskID.String() + `"],` +
+				`"critical_path_length":1,"estimated_duration_ms":100,"estimated_completion":"2025-01-02T03:04:05Z"}}` + "\n",
+		},
+		{
+			name:        "invalid expression",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"2+"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					ExplainExpression(gomock.Any(), testUserID, "2+", gomock.Any()).
+					Return(nil, expr.NewError(services.ErrInvalidExpressionStartEnd, 1, 2, "+"))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: `{"error":"expression cannot start or end with an operator at position 1: \"+\"",` +
+				`"details":{"code":"OPERATOR_AT_BOUNDARY","message":"expression cannot start or end with an operator","start":1,"end":2,"token":"+"}}` + "\n",
+		},
+		{
+			name:        "unknown precision",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"2+2","precision":"exact"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					ExplainExpression(gomock.Any(), testUserID, "2+2", gomock.Any()).
+					Return(nil, services.ErrUnknownPrecision)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"precision must be either \"float\" or \"decimal\""}` + "\n",
+		},
+		{
+			name:        "database unavailable",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"x+2"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					ExplainExpression(gomock.Any(), testUserID, "x+2", gomock.Any()).
+					Return(nil, services.ErrDatabaseUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"service temporarily unavailable"}` + "\n",
+		},
+		{
+			name:           "empty expression",
+			userID:         testUserID.String(),
+			requestBody:    `{"expression":""}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request payload"}` + "\n",
+		},
+		{
+			name:           "unauthorized",
+			userID:         "invalid",
+			requestBody:    `{"expression":"2+2"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"unauthorized"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/expressions/explain", strings.NewReader(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", tt.userID)
+
+			err := h.ExplainExpression(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_Symbolic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	testUserID := uuid.New()
+	expressionID := uuid.MustParse("b85cdb62-8d5c-435f-b921-35bbf229e822")
+
+	tests := []struct {
+		name           string
+		derive         bool
+		userID         string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "successful derivative",
+			derive:      true,
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"x^2+3*x","variable":"x"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					DeriveExpression(gomock.Any(), testUserID, "x^2+3*x", "x", services.SymbolicOptions{
+						CalculationOptions: services.CalculationOptions{Scale: services.DefaultDecimalScale},
+						Bindings:           map[string]string{},
+					}).
+					Return(&services.SymbolicResult{Expression: "2*x+3"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":{"expression":"2*x+3"}}` + "\n",
+		},
+		{
+			name:        "derivative submitted with bindings",
+			derive:      true,
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"x^2","variable":"x","submit":true,"bindings":{"x":1.5},"precision":"decimal","scale":4}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					DeriveExpression(gomock.Any(), testUserID, "x^2", "x", services.SymbolicOptions{
+						CalculationOptions: services.CalculationOptions{Precision: models.PrecisionDecimal, Scale: 4},
+						Submit:             true,
+						Bindings:           map[string]string{"x": "1.5"},
+					}).
+					Return(&services.SymbolicResult{Expression: "2*x", ID: &expressionID}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"result":{"expression":"2*x","id":"b85cdb62-8d5c-435f-b921-35bbf229e822"}}` + "\n",
+		},
+		{
+			name:        "successful simplification",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"x*1+0"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					SimplifyExpression(gomock.Any(), testUserID, "x*1+0", gomock.Any()).
+					Return(&services.SymbolicResult{Expression: "x"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":{"expression":"x"}}` + "\n",
+		},
+		{
+			name:        "not differentiable",
+			derive:      true,
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"x%2","variable":"x"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					DeriveExpression(gomock.Any(), testUserID, "x%2", "x", gomock.Any()).
+					Return(nil, expr.NewError(services.ErrNotDifferentiable, 1, 2, "%"))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: `{"error":"operation is not differentiable at position 1: \"%\"",` +
+				`"details":{"code":"NOT_DIFFERENTIABLE","message":"operation is not differentiable","start":1,"end":2,"token":"%"}}` + "\n",
+		},
+		{
+			name:        "invalid variable name",
+			derive:      true,
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"x^2","variable":"sin"}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					DeriveExpression(gomock.Any(), testUserID, "x^2", "sin", gomock.Any()).
+					Return(nil, services.ErrInvalidVariableName)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"` + services.ErrInvalidVariableName.Error() + `"}` + "\n",
+		},
+		{
+			name:        "database unavailable",
+			userID:      testUserID.String(),
+			requestBody: `{"expression":"x+y","submit":true}`,
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					SimplifyExpression(gomock.Any(), testUserID, "x+y", gomock.Any()).
+					Return(nil, services.ErrDatabaseUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"service temporarily unavailable"}` + "\n",
+		},
+		{
+			name:           "missing variable",
+			derive:         true,
+			userID:         testUserID.String(),
+			requestBody:    `{"expression":"x^2"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request payload"}` + "\n",
+		},
+		{
+			name:           "unauthorized",
+			userID:         "invalid",
+			requestBody:    `{"expression":"x"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"unauthorized"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/symbolic", strings.NewReader(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", tt.userID)
+
+			var err error
+			if tt.derive {
+				err = h.DeriveExpression(c)
+			} else {
+				err = h.SimplifyExpression(c)
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetExpressions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	testUserID := uuid.New()
+	expressions := []*models.Expression{
+		{
+			ID:         uuid.MustParse("b466ca50-0158-494a-b278-43375c6e3c34"),
+			UserID:     uuid.Nil,
+			Expression: "2+2",
+			Status:     models.Done,
+			Result:     4,
+
+			PercentComplete: 100,
+		},
+		{
+			ID:         uuid.MustParse("4d1f6a3e-2c5b-4e0a-9f7d-1b2c3d4e5f60"),
+			UserID:     uuid.Nil,
+			Expression: "2-2",
+			Status:     models.Done,
+
+			PercentComplete: 100,
+		},
+		{
+			ID:         uuid.MustParse("7a8b9c0d-1e2f-4a3b-8c4d-5e6f7a8b9c0d"),
+			UserID:     uuid.Nil,
+			Expression: "x*10",
+			Status:     models.Done,
+			Result:     math.Inf(1),
+			Policy:     models.PolicyIEEE,
+
+			PercentComplete: 100,
+		},
+		{
+			ID:         uuid.MustParse("0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0"),
+			UserID:     uuid.Nil,
+			Expression: "2*2",
+			Status:     models.Pending,
+
+			PercentComplete: 50,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "successful get expressions",
+			userID: testUserID.String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetAllExpressions(gomock.Any(), testUserID).
+					Return(expressions, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"expressions":[` +
+				`{"id":"b466ca50-0158-494a-b278-43375c6e3c34","user_id":"00000000-0000-0000-0000-000000000000","expression":"2+2","status":"done","percent_complete":100,"result":4},` +
+				`{"id":"4d1f6a3e-2c5b-4e0a-9f7d-1b2c3d4e5f60","user_id":"00000000-0000-0000-0000-000000000000","expression":"2-2","status":"done","percent_complete":100,"result":0},` +
+				`{"id":"7a8b9c0d-1e2f-4a3b-8c4d-5e6f7a8b9c0d","user_id":"00000000-0000-0000-0000-000000000000","expression":"x*10","status":"done","policy":"ieee","percent_complete":100,"result":"Infinity"},` +
+				`{"id":"0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0","user_id":"00000000-0000-0000-0000-000000000000","expression":"2*2","status":"pending","percent_complete":50}` +
+				`]}` + "\n",
+		},
+		{
+			name:           "invalid user id",
+			userID:         "invalid",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"unauthorized"}` + "\n",
+		},
+		{
+			name:   "unknown user id",
+			userID: testUserID.String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetAllExpressions(gomock.Any(), testUserID).
+					Return(nil, services.ErrUnknownUserID)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"unauthorized"}` + "\n",
+		},
+		{
+			name:   "database unavailable when getting expressions",
+			userID: testUserID.String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetAllExpressions(gomock.Any(), testUserID).
+					Return(nil, services.ErrDatabaseUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"service temporarily unavailable"}` + "\n",
+		},
+		{
+			name:   "internal server error when getting expressions",
+			userID: testUserID.String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetAllExpressions(gomock.Any(), testUserID).
+					Return(nil, errors.New("some unexpected error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"internal server error"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/expressions", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", tt.userID)
+
+			err := h.GetExpressions(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetExpressionByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	expressionID := uuid.MustParse("b85cdb62-8d5c-435f-b921-35bbf229e822")
+	expression := &models.Expression{
+		ID:         expressionID,
+		UserID:     uuid.Nil,
+		Expression: "2+2",
+		Status:     models.Done,
+		Result:     4,
+
+		PercentComplete: 100,
+	}
+
+	tests := []struct {
+		name           string
+		idParam        string
+		userID         string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "successful get expression by id",
+			idParam: expressionID.String(),
+			userID:  uuid.New().String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetExpressionById(gomock.Any(), expressionID).
+					Return(expression, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"expression":{"id":"b85cdb62-8d5c-435f-b921-35bbf229e822","user_id":"00000000-0000-0000-0000-000000000000","expression":"2+2","status":"done","percent_complete":100,"result":4}}` + "\n",
+		},
+		{
+			name:           "invalid id",
+			idParam:        "invalid",
+			userID:         uuid.New().String(),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request payload"}` + "\n",
+		},
+		{
+			name:    "expression not found",
+			idParam: expressionID.String(),
+			userID:  uuid.New().String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetExpressionById(gomock.Any(), expressionID).
+					Return(nil, services.ErrUnknownExpressionsID)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"unknown expressions id"}` + "\n",
+		},
+		{
+			name:    "database unavailable when getting expression by id",
+			idParam: expressionID.String(),
+			userID:  uuid.New().String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetExpressionById(gomock.Any(), expressionID).
+					Return(nil, services.ErrDatabaseUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"service temporarily unavailable"}` + "\n",
+		},
+		{
+			name:    "internal server error when getting expression by id",
+			idParam: expressionID.String(),
+			userID:  uuid.New().String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetExpressionById(gomock.Any(), expressionID).
+					Return(nil, errors.New("some unexpected error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"internal server error"}` + "\n",
+		},
+		{
+			name:           "nil uuid",
+			idParam:        "00000000-0000-0000-0000-000000000000",
+			userID:         uuid.New().String(),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request payload"}` + "\n",
+		},
+		{
+			name:           "invalid user id",
+			idParam:        expressionID.String(),
+			userID:         "invalid",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"unauthorized"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/expressions/"+tt.idParam, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.idParam)
+			c.Set("user_id", tt.userID)
+
+			err := h.GetExpressionByID(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetExpressionTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	expressionID := uuid.MustParse("b85cdb62-8d5c-435f-b921-35bbf229e822")
+	first := uuid.MustParse("4d1f6a3e-2c5b-4e0a-9f7d-1b2c3d4e5f60")
+	second := uuid.MustParse("0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0")
+	two, four := models.Number(2), models.Number(4)
+	startedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	finishedAt := startedAt.Add(time.Second)
+	tasks := []*models.TaskRecord{
+		{
+			ID:         first,
+			Operator:   "*",
+			Args:       []models.TaskArgument{{Value: &two}, {Value: &two}},
+			Status:     models.Done,
+			Agent:      "10.0.0.7:5000",
+			StartedAt:  &startedAt,
+			FinishedAt: &finishedAt,
+			Result:     &four,
+		},
+		{
+			ID:        second,
+			Operator:  "+",
+			Args:      []models.TaskArgument{{Value: &two}, {TaskID: &first}},
+			Status:    models.Pending,
+			FinalTask: true,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		idParam        string
+		userID         string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "successful get expression tasks",
+			idParam: expressionID.String(),
+			userID:  uuid.New().String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetExpressionTasks(gomock.Any(), expressionID).
+					Return(tasks, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"tasks":[` +
+				`{"id":"4d1f6a3e-2c5b-4e0a-9f7d-1b2c3d4e5f60","operator":"*","args":[{"value":2},{"value":2}],"status":"done","agent":"10.0.0.7:5000",` +
+				`"started_at":"2025-01-02T03:04:05Z","finished_at":"2025-01-02T03:04:06Z","result":4},` +
+				`{"id":"0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0","operator":"+","args":[{"value":2},{"task_id":"4d1f6a3e-2c5b-4e0a-9f7d-1b2c3d4e5f60"}],"status":"pending","final_task":true}` +
+				`]}` + "\n",
+		},
+		{
+			name:           "invalid id",
+			idParam:        "invalid",
+			userID:         uuid.New().String(),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request payload"}` + "\n",
+		},
+		{
+			name:           "invalid user id",
+			idParam:        expressionID.String(),
+			userID:         "invalid",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"unauthorized"}` + "\n",
+		},
+		{
+			name:    "expression not found",
+			idParam: expressionID.String(),
+			userID:  uuid.New().String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetExpressionTasks(gomock.Any(), expressionID).
+					Return(nil, services.ErrUnknownExpressionsID)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"unknown expressions id"}` + "\n",
+		},
+		{
+			name:    "forbidden",
+			idParam: expressionID.String(),
+			userID:  uuid.New().String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetExpressionTasks(gomock.Any(), expressionID).
+					Return(nil, services.ErrForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":"you are not allowed to access this expression"}` + "\n",
+		},
+		{
+			name:    "database unavailable",
+			idParam: expressionID.String(),
+			userID:  uuid.New().String(),
+			mockSetup: func() {
+				mockExpressionService.EXPECT().
+					GetExpressionTasks(gomock.Any(), expressionID).
+					Return(nil, services.ErrDatabaseUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"service temporarily unavailable"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/expressions/"+tt.idParam+"/tasks", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.idParam)
+			c.Set("user_id", tt.userID)
+
+			err := h.GetExpressionTasks(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_CreateVariable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	testUserID := uuid.New()
+
+	tests := []struct {
+		name           string
+		userID         string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "successful create",
+			userID:      testUserID.String(),
+			requestBody: `{"name":"rate","value":0.15}`,
+			mockSetup: func() {
+				mockVariableService.EXPECT().
+					CreateVariable(gomock.Any(), testUserID, "rate", "0.15").
+					Return(&models.Variable{UserID: testUserID, Name: "rate", Value: 0.15, Decimal: "0.15"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"variable":{"name":"rate","value":0.15,"decimal":"0.15"}}` + "\n",
+		},
+		{
+			name:           "invalid json",
+			userID:         testUserID.String(),
+			requestBody:    `{"name":"rate","value":"abc"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request payload"}` + "\n",
+		},
+		{
+			name:           "empty value",
+			userID:         testUserID.String(),
+			requestBody:    `{"name":"rate"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"name and value should not be empty"}` + "\n",
+		},
+		{
+			name:           "invalid user id",
+			userID:         "invalid",
+			requestBody:    `{"name":"rate","value":1}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"unauthorized"}` + "\n",
+		},
+		{
+			name:        "invalid name",
+			userID:      testUserID.String(),
+			requestBody: `{"name":"sqrt","value":1}`,
+			mockSetup: func() {
+				mockVariableService.EXPECT().
+					CreateVariable(gomock.Any(), testUserID, "sqrt", "1").
+					Return(nil, services.ErrInvalidVariableName)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"` + services.ErrInvalidVariableName.Error() + `"}` + "\n",
+		},
+		{
+			name:        "variable already exists",
+			userID:      testUserID.String(),
+			requestBody: `{"name":"rate","value":1}`,
+			mockSetup: func() {
+				mockVariableService.EXPECT().
+					CreateVariable(gomock.Any(), testUserID, "rate", "1").
+					Return(nil, services.ErrVariableAlreadyExists)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"variable already exists"}` + "\n",
+		},
+		{
+			name:        "database unavailable",
+			userID:      testUserID.String(),
+			requestBody: `{"name":"rate","value":1}`,
+			mockSetup: func() {
+				mockVariableService.EXPECT().
+					CreateVariable(gomock.Any(), testUserID, "rate", "1").
+					Return(nil, services.ErrDatabaseUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"service temporarily unavailable"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/variables", strings.NewReader(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", tt.userID)
+
+			err := h.CreateVariable(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetVariables(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	testUserID := uuid.New()
+	mockVariableService.EXPECT().
+		GetVariables(gomock.Any(), testUserID).
+		Return([]*models.Variable{{Name: "x", Value: 2, Decimal: "2"}}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/variables", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", testUserID.String())
+
+	err := h.GetVariables(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"variables":[{"name":"x","value":2,"decimal":"2"}]}`+"\n", rec.Body.String())
+}
+
+func TestHandler_GetVariable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	testUserID := uuid.New()
+
+	tests := []struct {
+		name           string
+		variable       string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "successful get variable",
+			variable: "x",
+			mockSetup: func() {
+				mockVariableService.EXPECT().
+					GetVariable(gomock.Any(), testUserID, "x").
+					Return(&models.Variable{Name: "x", Value: 2, Decimal: "2"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"variable":{"name":"x","value":2,"decimal":"2"}}` + "\n",
+		},
+		{
+			name:     "unknown variable",
+			variable: "y",
+			mockSetup: func() {
+				mockVariableService.EXPECT().
+					GetVariable(gomock.Any(), testUserID, "y").
+					Return(nil, services.ErrUnknownVariable)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"unknown variable"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/variables/"+tt.variable, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("name")
+			c.SetParamValues(tt.variable)
+			c.Set("user_id", testUserID.String())
+
+			err := h.GetVariable(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_UpdateVariable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	testUserID := uuid.New()
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "successful update",
+			requestBody: `{"value":"1e3"}`,
+			mockSetup: func() {
+				mockVariableService.EXPECT().
+					UpdateVariable(gomock.Any(), testUserID, "x", "1e3").
+					Return(&models.Variable{Name: "x", Value: 1000, Decimal: "1000"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"variable":{"name":"x","value":1000,"decimal":"1000"}}` + "\n",
+		},
+		{
+			name:           "empty value",
+			requestBody:    `{}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"value should not be empty"}` + "\n",
+		},
+		{
+			name:        "unknown variable",
+			requestBody: `{"value":1}`,
+			mockSetup: func() {
+				mockVariableService.EXPECT().
+					UpdateVariable(gomock.Any(), testUserID, "x", "1").
+					Return(nil, services.ErrUnknownVariable)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"unknown variable"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/variables/x", strings.NewReader(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("name")
+			c.SetParamValues("x")
+			c.Set("user_id", testUserID.String())
+
+			err := h.UpdateVariable(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_DeleteVariable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	testUserID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "successful delete",
+			mockSetup: func() {
+				mockVariableService.EXPECT().
+					DeleteVariable(gomock.Any(), testUserID, "x").
+					Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedBody:   "",
+		},
+		{
+			name: "unknown variable",
+			mockSetup: func() {
+				mockVariableService.EXPECT().
+					DeleteVariable(gomock.Any(), testUserID, "x").
+					Return(services.ErrUnknownVariable)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"unknown variable"}` + "\n",
+		},
+		{
+			name: "internal server error",
+			mockSetup: func() {
+				mockVariableService.EXPECT().
+					DeleteVariable(gomock.Any(), testUserID, "x").
+					Return(errors.New("some unexpected error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"internal server error"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodDelete, "/variables/x", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("name")
+			c.SetParamValues("x")
+			c.Set("user_id", testUserID.String())
+
+			err := h.DeleteVariable(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_DefineFunction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	testUserID := uuid.New()
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "successful define",
+			requestBody: `{"name":"f","params":["x","y"],"body":"x*x + y"}`,
+			mockSetup: func() {
+				mockFunctionService.EXPECT().
+					DefineFunction(gomock.Any(), testUserID, "f", []string{"x", "y"}, "x*x + y").
+					Return(&models.UserFunction{UserID: testUserID, Name: "f", Params: []string{"x", "y"}, Body: "x*x+y"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"function":{"name":"f","params":["x","y"],"body":"x*x+y"}}` + "\n",
+		},
+		{
+			name:           "empty body",
+			requestBody:    `{"name":"f","params":["x"]}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"name and body should not be empty"}` + "\n",
+		},
+		{
+			name:        "invalid body",
+			requestBody: `{"name":"f","params":["x"],"body":"x*y"}`,
+			mockSetup: func() {
+				mockFunctionService.EXPECT().
+					DefineFunction(gomock.Any(), testUserID, "f", []string{"x"}, "x*y").
+					Return(nil, expr.NewError(services.ErrUnknownVariable, 2, 3, "y"))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"unknown variable at position 2: \"y\"","details":{"code":"UNKNOWN_VARIABLE","message":"unknown variable","start":2,"end":3,"token":"y"}}` + "\n",
+		},
+		{
+			name:        "recursive function",
+			requestBody: `{"name":"f","params":["x"],"body":"f(x)+1"}`,
+			mockSetup: func() {
+				mockFunctionService.EXPECT().
+					DefineFunction(gomock.Any(), testUserID, "f", []string{"x"}, "f(x)+1").
+					Return(nil, services.ErrRecursiveFunction)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"function calls itself directly or indirectly","details":{"code":"RECURSIVE_FUNCTION","message":"function calls itself directly or indirectly","start":0,"end":6}}` + "\n",
+		},
+		{
+			name:        "function already exists",
+			requestBody: `{"name":"f","params":["x"],"body":"x+1"}`,
+			mockSetup: func() {
+				mockFunctionService.EXPECT().
+					DefineFunction(gomock.Any(), testUserID, "f", []string{"x"}, "x+1").
+					Return(nil, services.ErrFunctionAlreadyExists)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"function already exists"}` + "\n",
+		},
+		{
+			name:        "database unavailable",
+			requestBody: `{"name":"f","params":["x"],"body":"x+1"}`,
+			mockSetup: func() {
+				mockFunctionService.EXPECT().
+					DefineFunction(gomock.Any(), testUserID, "f", []string{"x"}, "x+1").
+					Return(nil, services.ErrDatabaseUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"service temporarily unavailable"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/functions", strings.NewReader(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", testUserID.String())
+
+			err := h.DefineFunction(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetFunctions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	testUserID := uuid.New()
+	mockFunctionService.EXPECT().
+		GetFunctions(gomock.Any(), testUserID).
+		Return([]*models.UserFunction{{Name: "sq", Params: []string{"x"}, Body: "x*x"}}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/functions", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", testUserID.String())
+
+	err := h.GetFunctions(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"functions":[{"name":"sq","params":["x"],"body":"x*x"}]}`+"\n", rec.Body.String())
+}
+
+func TestHandler_DeleteFunction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserService(ctrl)
+	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
+
+	testUserID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "successful delete",
+			mockSetup: func() {
+				mockFunctionService.EXPECT().DeleteFunction(gomock.Any(), testUserID, "sq").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedBody:   "",
+		},
+		{
+			name: "unknown function",
+			mockSetup: func() {
+				mockFunctionService.EXPECT().DeleteFunction(gomock.Any(), testUserID, "sq").Return(services.ErrUnknownFunction)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"unknown function"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodDelete, "/functions/sq", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("name")
+			c.SetParamValues("sq")
+			c.Set("user_id", testUserID.String())
+
+			err := h.DeleteFunction(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetAgents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAgentService := mocks.NewMockAgentService(ctrl)
+	h := handlers.NewHandler(mocks.NewMockUserService(ctrl), mocks.NewMockExpressionTaskService(ctrl),
+		mocks.NewMockVariableService(ctrl), mocks.NewMockUserFunctionService(ctrl), mockAgentService)
+
+	seen := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	taskID := uuid.MustParse("5f0c6f7e-8a4b-4c2d-9e1f-0a1b2c3d4e5f")
+
+	tests := []struct {
+		name           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "live agents",
+			mockSetup: func() {
+				mockAgentService.EXPECT().GetLiveAgents(gomock.Any()).Return([]*models.Agent{{
+					ID:             "agent-1",
+					Hostname:       "host",
+					Version:        "1.0.0",
+					Capacity:       4,
+					Operators:      []string{"+"},
+					RegisteredAt:   seen,
+					LastSeen:       seen,
+					InFlightTasks:  []uuid.UUID{taskID},
+					TasksPerMinute: 12,
+				}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"agents":[{"id":"agent-1","hostname":"host","version":"1.0.0","capacity":4,"operators":["+"],` +
+				`"registered_at":"2025-01-02T03:04:05Z","last_seen":"2025-01-02T03:04:05Z",` +
+				`"in_flight_tasks":["5f0c6f7e-8a4b-4c2d-9e1f-0a1b2c3d4e5f"],"tasks_per_minute":12}]}` + "\n",
+		},
+		{
+			name: "database unavailable",
+			mockSetup: func() {
+				mockAgentService.EXPECT().GetLiveAgents(gomock.Any()).Return(nil, services.ErrDatabaseUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"agents":null,"error":"service temporarily unavailable"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/agents", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := h.GetAgents(c)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			assert.Equal(t, tt.expectedBody, rec.Body.String())
@@ -567,7 +1975,9 @@ func TestHandler_Ping(t *testing.T) {
 
 	mockUserService := mocks.NewMockUserService(ctrl)
 	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
-	h := handlers.NewHandler(mockUserService, mockExpressionService)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
@@ -586,8 +1996,10 @@ func TestNewHandler(t *testing.T) {
 
 	mockUserService := mocks.NewMockUserService(ctrl)
 	mockExpressionService := mocks.NewMockExpressionTaskService(ctrl)
+	mockVariableService := mocks.NewMockVariableService(ctrl)
+	mockFunctionService := mocks.NewMockUserFunctionService(ctrl)
 
-	h := handlers.NewHandler(mockUserService, mockExpressionService)
+	h := handlers.NewHandler(mockUserService, mockExpressionService, mockVariableService, mockFunctionService, mocks.NewMockAgentService(ctrl))
 
 	assert.NotNil(t, h)
 	_, ok := h.(handlers.Handler)
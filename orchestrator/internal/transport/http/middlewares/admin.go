@@ -0,0 +1,22 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const AdminTokenHeader = "X-Admin-Token"
+
+func AdminMiddleware(adminToken string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token := c.Request().Header.Get(AdminTokenHeader)
+			if adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "Admin access required"})
+			}
+			return next(c)
+		}
+	}
+}
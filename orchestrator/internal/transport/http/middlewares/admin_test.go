@@ -0,0 +1,63 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/http/middlewares"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	tests := []struct {
+		name               string
+		adminToken         string
+		header             string
+		expectedStatusCode int
+	}{
+		{
+			name:               "Missing admin token",
+			adminToken:         "secret",
+			header:             "",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Wrong admin token",
+			adminToken:         "secret",
+			header:             "guess",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Admin access disabled",
+			adminToken:         "",
+			header:             "",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Valid admin token",
+			adminToken:         "secret",
+			header:             "secret",
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.GET("/api/v1/admin/agents", func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			}, middlewares.AdminMiddleware(tt.adminToken))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/agents", nil)
+			req.Header.Set(middlewares.AdminTokenHeader, tt.header)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rec.Code)
+		})
+	}
+}
@@ -6,11 +6,26 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterRoutes(e *echo.Echo, h handlers.Handler) {
+func RegisterRoutes(e *echo.Echo, h handlers.Handler, admin echo.MiddlewareFunc) {
 	e.POST("/api/v1/register", h.Register)
 	e.POST("/api/v1/login", h.Login)
 	e.POST("/api/v1/calculate", h.Calculate)
+	e.POST("/api/v1/calculate/batch", h.CalculateBatch)
+	e.POST("/api/v1/calculate/script", h.CalculateScript)
 	e.GET("/api/v1/expressions", h.GetExpressions)
+	e.POST("/api/v1/expressions/explain", h.ExplainExpression)
 	e.GET("/api/v1/expressions/:id", h.GetExpressionByID)
+	e.GET("/api/v1/expressions/:id/tasks", h.GetExpressionTasks)
+	e.POST("/api/v1/symbolic/derive", h.DeriveExpression)
+	e.POST("/api/v1/symbolic/simplify", h.SimplifyExpression)
+	e.POST("/api/v1/variables", h.CreateVariable)
+	e.GET("/api/v1/variables", h.GetVariables)
+	e.GET("/api/v1/variables/:name", h.GetVariable)
+	e.PUT("/api/v1/variables/:name", h.UpdateVariable)
+	e.DELETE("/api/v1/variables/:name", h.DeleteVariable)
+	e.POST("/api/v1/functions", h.DefineFunction)
+	e.GET("/api/v1/functions", h.GetFunctions)
+	e.DELETE("/api/v1/functions/:name", h.DeleteFunction)
+	e.GET("/api/v1/admin/agents", h.GetAgents, admin)
 	e.GET("/api/v1/ping", h.Ping)
 }
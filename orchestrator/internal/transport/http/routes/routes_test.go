@@ -5,9 +5,11 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/http/middlewares"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/http/routes"
 	"github.com/alexGoLyceum/calculator-service/orchestrator/mocks"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -20,13 +22,28 @@ func TestRegisterRoutes(t *testing.T) {
 	mockHandler := mocks.NewMockHandler(ctrl)
 	e := echo.New()
 
-	routes.RegisterRoutes(e, mockHandler)
+	routes.RegisterRoutes(e, mockHandler, middlewares.AdminMiddleware("secret"))
 
 	mockHandler.EXPECT().Register(gomock.Any()).Return(nil).Times(1)
 	mockHandler.EXPECT().Login(gomock.Any()).Return(nil).Times(1)
 	mockHandler.EXPECT().Calculate(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().CalculateBatch(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().CalculateScript(gomock.Any()).Return(nil).Times(1)
 	mockHandler.EXPECT().GetExpressions(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().ExplainExpression(gomock.Any()).Return(nil).Times(1)
 	mockHandler.EXPECT().GetExpressionByID(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().GetExpressionTasks(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().DeriveExpression(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().SimplifyExpression(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().CreateVariable(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().GetVariables(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().GetVariable(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().UpdateVariable(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().DeleteVariable(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().DefineFunction(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().GetFunctions(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().DeleteFunction(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().GetAgents(gomock.Any()).Return(nil).Times(1)
 	mockHandler.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/register", nil)
@@ -56,6 +73,24 @@ func TestRegisterRoutes(t *testing.T) {
 	}
 	assert.Equal(t, http.StatusOK, rec.Code)
 
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/calculate/batch", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.CalculateBatch(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/calculate/script", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.CalculateScript(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
 	req = httptest.NewRequest(http.MethodGet, "/api/v1/expressions", nil)
 	rec = httptest.NewRecorder()
 	c = e.NewContext(req, rec)
@@ -65,6 +100,15 @@ func TestRegisterRoutes(t *testing.T) {
 	}
 	assert.Equal(t, http.StatusOK, rec.Code)
 
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/expressions/explain", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.ExplainExpression(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
 	req = httptest.NewRequest(http.MethodGet, "/api/v1/expressions/1234", nil)
 	rec = httptest.NewRecorder()
 	c = e.NewContext(req, rec)
@@ -74,6 +118,114 @@ func TestRegisterRoutes(t *testing.T) {
 	}
 	assert.Equal(t, http.StatusOK, rec.Code)
 
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/expressions/1/tasks", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.GetExpressionTasks(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/symbolic/derive", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.DeriveExpression(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/symbolic/simplify", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.SimplifyExpression(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/variables", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.CreateVariable(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/variables", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.GetVariables(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/variables/x", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.GetVariable(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/variables/x", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.UpdateVariable(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/variables/x", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.DeleteVariable(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/functions", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.DefineFunction(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/functions", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.GetFunctions(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/functions/f", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.DeleteFunction(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/agents", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	err = mockHandler.GetAgents(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+
 	req = httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil)
 	rec = httptest.NewRecorder()
 	c = e.NewContext(req, rec)
@@ -83,3 +235,31 @@ func TestRegisterRoutes(t *testing.T) {
 	}
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
+
+func TestRegisterRoutes_AdminAccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHandler := mocks.NewMockHandler(ctrl)
+	mockJWT := mocks.NewMockJWTManager(ctrl)
+	e := echo.New()
+	e.Use(middlewares.JWTMiddleware(mockJWT))
+	routes.RegisterRoutes(e, mockHandler, middlewares.AdminMiddleware("secret"))
+
+	mockJWT.EXPECT().Parse("usertoken").Return(uuid.New(), nil).Times(2)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/agents", nil)
+	req.Header.Set("Authorization", "Bearer usertoken")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	mockHandler.EXPECT().GetAgents(gomock.Any()).Return(nil).Times(1)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/agents", nil)
+	req.Header.Set("Authorization", "Bearer usertoken")
+	req.Header.Set(middlewares.AdminTokenHeader, "secret")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
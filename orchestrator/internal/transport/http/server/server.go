@@ -34,7 +34,7 @@ func NewServer(cfg *config.Config, logger logging.Logger, handler handlers.Handl
 	e.Use(middlewares.SetupCORS())
 	e.Use(middleware.Recover())
 
-	routes.RegisterRoutes(e, handler)
+	routes.RegisterRoutes(e, handler, middlewares.AdminMiddleware(cfg.AdminToken))
 
 	return &Impl{
 		config: cfg,
@@ -0,0 +1,21 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS arg1_value   DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS arg1_task_id UUID REFERENCES tasks (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS arg2_value   DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS arg2_task_id UUID REFERENCES tasks (id) ON DELETE SET NULL;
+
+UPDATE tasks t
+SET arg1_value   = a.value,
+    arg1_task_id = a.source_task_id
+FROM task_arguments a
+WHERE a.task_id = t.id
+  AND a.position = 0;
+
+UPDATE tasks t
+SET arg2_value   = a.value,
+    arg2_task_id = a.source_task_id
+FROM task_arguments a
+WHERE a.task_id = t.id
+  AND a.position = 1;
+
+DROP TABLE IF EXISTS task_arguments;
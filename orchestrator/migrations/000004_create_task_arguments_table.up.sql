@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS task_arguments
+(
+    task_id        UUID             NOT NULL,
+    position       INTEGER          NOT NULL,
+    value          DOUBLE PRECISION,
+    source_task_id UUID,
+    PRIMARY KEY (task_id, position),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (source_task_id) REFERENCES tasks (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS task_arguments_source_task_id_idx ON task_arguments (source_task_id);
+
+INSERT INTO task_arguments (task_id, position, value, source_task_id)
+SELECT id, 0, arg1_value, arg1_task_id
+FROM tasks;
+
+INSERT INTO task_arguments (task_id, position, value, source_task_id)
+SELECT id, 1, arg2_value, arg2_task_id
+FROM tasks;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS arg1_value,
+    DROP COLUMN IF EXISTS arg1_task_id,
+    DROP COLUMN IF EXISTS arg2_value,
+    DROP COLUMN IF EXISTS arg2_task_id;
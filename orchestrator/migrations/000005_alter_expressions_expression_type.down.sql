@@ -0,0 +1,2 @@
+ALTER TABLE expressions
+    ALTER COLUMN expression TYPE VARCHAR(255);
@@ -0,0 +1,7 @@
+ALTER TABLE task_arguments
+    DROP COLUMN IF EXISTS decimal_value;
+
+ALTER TABLE expressions
+    DROP COLUMN IF EXISTS decimal_result,
+    DROP COLUMN IF EXISTS scale,
+    DROP COLUMN IF EXISTS precision;
@@ -0,0 +1,7 @@
+ALTER TABLE expressions
+    ADD COLUMN IF NOT EXISTS precision      VARCHAR(16) NOT NULL DEFAULT 'float',
+    ADD COLUMN IF NOT EXISTS scale          INTEGER     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS decimal_result TEXT;
+
+ALTER TABLE task_arguments
+    ADD COLUMN IF NOT EXISTS decimal_value TEXT;
@@ -0,0 +1,4 @@
+ALTER TABLE expressions
+    DROP COLUMN IF EXISTS variables;
+
+DROP TABLE IF EXISTS variables;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS variables
+(
+    user_id       UUID             NOT NULL,
+    name          VARCHAR(64)      NOT NULL,
+    value         DOUBLE PRECISION NOT NULL,
+    decimal_value TEXT             NOT NULL,
+    PRIMARY KEY (user_id, name),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+ALTER TABLE expressions
+    ADD COLUMN IF NOT EXISTS variables JSONB;
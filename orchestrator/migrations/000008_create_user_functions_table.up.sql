@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS user_functions
+(
+    user_id UUID        NOT NULL,
+    name    VARCHAR(64) NOT NULL,
+    params  TEXT[]      NOT NULL,
+    body    TEXT        NOT NULL,
+    PRIMARY KEY (user_id, name),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
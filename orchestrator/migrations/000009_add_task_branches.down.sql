@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_branch_of_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS then_branch,
+    DROP COLUMN IF EXISTS branch_of;
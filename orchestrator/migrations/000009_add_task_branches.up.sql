@@ -0,0 +1,6 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS branch_of   UUID,
+    ADD COLUMN IF NOT EXISTS then_branch BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD FOREIGN KEY (branch_of) REFERENCES tasks (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS tasks_branch_of_idx ON tasks (branch_of);
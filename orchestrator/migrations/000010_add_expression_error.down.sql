@@ -0,0 +1,2 @@
+ALTER TABLE expressions
+    DROP COLUMN IF EXISTS error_message;
@@ -0,0 +1,2 @@
+ALTER TABLE expressions
+    ADD COLUMN IF NOT EXISTS error_message TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE expressions
+    DROP COLUMN IF EXISTS numeric_policy;
@@ -0,0 +1,2 @@
+ALTER TABLE expressions
+    ADD COLUMN IF NOT EXISTS numeric_policy VARCHAR(16) NOT NULL DEFAULT 'strict';
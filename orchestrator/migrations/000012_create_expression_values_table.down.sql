@@ -0,0 +1 @@
+DROP TABLE IF EXISTS expression_values;
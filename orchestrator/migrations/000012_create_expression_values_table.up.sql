@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS expression_values
+(
+    expression_id  UUID        NOT NULL,
+    position       INTEGER     NOT NULL,
+    name           VARCHAR(64) NOT NULL,
+    value          DOUBLE PRECISION,
+    decimal_value  TEXT,
+    source_task_id UUID,
+    PRIMARY KEY (expression_id, position),
+    FOREIGN KEY (expression_id) REFERENCES expressions (id) ON DELETE CASCADE,
+    FOREIGN KEY (source_task_id) REFERENCES tasks (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS expression_values_source_task_id_idx ON expression_values (source_task_id);
@@ -0,0 +1,16 @@
+DELETE FROM tasks
+WHERE status NOT IN ('pending', 'in progress');
+
+DROP INDEX IF EXISTS task_arguments_origin_task_id_idx;
+DROP INDEX IF EXISTS tasks_expression_id_idx;
+
+ALTER TABLE task_arguments
+    DROP COLUMN IF EXISTS origin_task_id;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS error_message,
+    DROP COLUMN IF EXISTS decimal_result,
+    DROP COLUMN IF EXISTS finished_at,
+    DROP COLUMN IF EXISTS started_at,
+    DROP COLUMN IF EXISTS agent,
+    DROP COLUMN IF EXISTS position;
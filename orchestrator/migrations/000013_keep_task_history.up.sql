@@ -0,0 +1,18 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS position       INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS agent          TEXT,
+    ADD COLUMN IF NOT EXISTS started_at     TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS finished_at    TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS decimal_result TEXT,
+    ADD COLUMN IF NOT EXISTS error_message  TEXT;
+
+ALTER TABLE task_arguments
+    ADD COLUMN IF NOT EXISTS origin_task_id UUID,
+    ADD FOREIGN KEY (origin_task_id) REFERENCES tasks (id) ON DELETE SET NULL;
+
+UPDATE task_arguments
+SET origin_task_id = source_task_id
+WHERE source_task_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS tasks_expression_id_idx ON tasks (expression_id);
+CREATE INDEX IF NOT EXISTS task_arguments_origin_task_id_idx ON task_arguments (origin_task_id);
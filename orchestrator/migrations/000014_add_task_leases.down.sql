@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tasks_lease_expires_at_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS lease_expires_at;
@@ -0,0 +1,8 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS tasks_lease_expires_at_idx ON tasks (lease_expires_at) WHERE status = 'in progress';
+
+UPDATE tasks
+SET lease_expires_at = now()
+WHERE status = 'in progress';
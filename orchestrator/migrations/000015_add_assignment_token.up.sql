@@ -0,0 +1,11 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS assignment_token UUID;
+
+UPDATE tasks
+SET status           = 'pending',
+    result           = NULL,
+    agent            = NULL,
+    started_at       = NULL,
+    lease_expires_at = NULL
+WHERE status = 'in progress'
+  AND assignment_token IS NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS tasks_agent_idx;
+DROP TABLE IF EXISTS agents;
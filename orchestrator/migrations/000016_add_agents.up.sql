@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS agents
+(
+    id            TEXT PRIMARY KEY,
+    hostname      TEXT      NOT NULL,
+    version       TEXT      NOT NULL,
+    capacity      INTEGER   NOT NULL,
+    operators     TEXT[]    NOT NULL,
+    registered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS agents_last_seen_idx ON agents (last_seen);
+CREATE INDEX IF NOT EXISTS tasks_agent_idx ON tasks (agent);
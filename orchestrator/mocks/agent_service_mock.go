@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: orchestrator/internal/services/agent_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=orchestrator/internal/services/agent_service.go -destination=orchestrator/mocks/agent_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAgentService is a mock of AgentService interface.
+type MockAgentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgentServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAgentServiceMockRecorder is the mock recorder for MockAgentService.
+type MockAgentServiceMockRecorder struct {
+	mock *MockAgentService
+}
+
+// NewMockAgentService creates a new mock instance.
+func NewMockAgentService(ctrl *gomock.Controller) *MockAgentService {
+	mock := &MockAgentService{ctrl: ctrl}
+	mock.recorder = &MockAgentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgentService) EXPECT() *MockAgentServiceMockRecorder {
+	return m.recorder
+}
+
+// GetLiveAgents mocks base method.
+func (m *MockAgentService) GetLiveAgents(ctx context.Context) ([]*models.Agent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLiveAgents", ctx)
+	ret0, _ := ret[0].([]*models.Agent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLiveAgents indicates an expected call of GetLiveAgents.
+func (mr *MockAgentServiceMockRecorder) GetLiveAgents(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLiveAgents", reflect.TypeOf((*MockAgentService)(nil).GetLiveAgents), ctx)
+}
+
+// RegisterAgent mocks base method.
+func (m *MockAgentService) RegisterAgent(ctx context.Context, agent *models.Agent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterAgent", ctx, agent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterAgent indicates an expected call of RegisterAgent.
+func (mr *MockAgentServiceMockRecorder) RegisterAgent(ctx, agent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAgent", reflect.TypeOf((*MockAgentService)(nil).RegisterAgent), ctx, agent)
+}
+
+// TouchAgent mocks base method.
+func (m *MockAgentService) TouchAgent(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAgent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAgent indicates an expected call of TouchAgent.
+func (mr *MockAgentServiceMockRecorder) TouchAgent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAgent", reflect.TypeOf((*MockAgentService)(nil).TouchAgent), ctx, id)
+}
@@ -15,6 +15,7 @@ import (
 	time "time"
 
 	models "github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	services "github.com/alexGoLyceum/calculator-service/orchestrator/internal/services"
 	proto "github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/grpc/proto"
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -46,18 +47,78 @@ func (m *MockExpressionTaskService) EXPECT() *MockExpressionTaskServiceMockRecor
 }
 
 // CreateExpressionTask mocks base method.
-func (m *MockExpressionTaskService) CreateExpressionTask(ctx context.Context, userID uuid.UUID, expression string) (uuid.UUID, error) {
+func (m *MockExpressionTaskService) CreateExpressionTask(ctx context.Context, userID uuid.UUID, expression string, options services.CalculationOptions) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateExpressionTask", ctx, userID, expression)
+	ret := m.ctrl.Call(m, "CreateExpressionTask", ctx, userID, expression, options)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateExpressionTask indicates an expected call of CreateExpressionTask.
-func (mr *MockExpressionTaskServiceMockRecorder) CreateExpressionTask(ctx, userID, expression any) *gomock.Call {
+func (mr *MockExpressionTaskServiceMockRecorder) CreateExpressionTask(ctx, userID, expression, options any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpressionTask", reflect.TypeOf((*MockExpressionTaskService)(nil).CreateExpressionTask), ctx, userID, expression)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpressionTask", reflect.TypeOf((*MockExpressionTaskService)(nil).CreateExpressionTask), ctx, userID, expression, options)
+}
+
+// CreateExpressionTasks mocks base method.
+func (m *MockExpressionTaskService) CreateExpressionTasks(ctx context.Context, userID uuid.UUID, expressions []string, options services.CalculationOptions) ([]services.BatchItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExpressionTasks", ctx, userID, expressions, options)
+	ret0, _ := ret[0].([]services.BatchItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExpressionTasks indicates an expected call of CreateExpressionTasks.
+func (mr *MockExpressionTaskServiceMockRecorder) CreateExpressionTasks(ctx, userID, expressions, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpressionTasks", reflect.TypeOf((*MockExpressionTaskService)(nil).CreateExpressionTasks), ctx, userID, expressions, options)
+}
+
+// CreateScriptTask mocks base method.
+func (m *MockExpressionTaskService) CreateScriptTask(ctx context.Context, userID uuid.UUID, script string, output services.ScriptOutput, options services.CalculationOptions) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScriptTask", ctx, userID, script, output, options)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScriptTask indicates an expected call of CreateScriptTask.
+func (mr *MockExpressionTaskServiceMockRecorder) CreateScriptTask(ctx, userID, script, output, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScriptTask", reflect.TypeOf((*MockExpressionTaskService)(nil).CreateScriptTask), ctx, userID, script, output, options)
+}
+
+// DeriveExpression mocks base method.
+func (m *MockExpressionTaskService) DeriveExpression(ctx context.Context, userID uuid.UUID, expression, variable string, options services.SymbolicOptions) (*services.SymbolicResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeriveExpression", ctx, userID, expression, variable, options)
+	ret0, _ := ret[0].(*services.SymbolicResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeriveExpression indicates an expected call of DeriveExpression.
+func (mr *MockExpressionTaskServiceMockRecorder) DeriveExpression(ctx, userID, expression, variable, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeriveExpression", reflect.TypeOf((*MockExpressionTaskService)(nil).DeriveExpression), ctx, userID, expression, variable, options)
+}
+
+// ExplainExpression mocks base method.
+func (m *MockExpressionTaskService) ExplainExpression(ctx context.Context, userID uuid.UUID, expression string, options services.CalculationOptions) (*services.Explanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainExpression", ctx, userID, expression, options)
+	ret0, _ := ret[0].(*services.Explanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainExpression indicates an expected call of ExplainExpression.
+func (mr *MockExpressionTaskServiceMockRecorder) ExplainExpression(ctx, userID, expression, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainExpression", reflect.TypeOf((*MockExpressionTaskService)(nil).ExplainExpression), ctx, userID, expression, options)
 }
 
 // GetAllExpressions mocks base method.
@@ -90,6 +151,21 @@ func (mr *MockExpressionTaskServiceMockRecorder) GetExpressionById(ctx, expressi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpressionById", reflect.TypeOf((*MockExpressionTaskService)(nil).GetExpressionById), ctx, expression)
 }
 
+// GetExpressionTasks mocks base method.
+func (m *MockExpressionTaskService) GetExpressionTasks(ctx context.Context, expressionID uuid.UUID) ([]*models.TaskRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpressionTasks", ctx, expressionID)
+	ret0, _ := ret[0].([]*models.TaskRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpressionTasks indicates an expected call of GetExpressionTasks.
+func (mr *MockExpressionTaskServiceMockRecorder) GetExpressionTasks(ctx, expressionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpressionTasks", reflect.TypeOf((*MockExpressionTaskService)(nil).GetExpressionTasks), ctx, expressionID)
+}
+
 // GetOperationEndTime mocks base method.
 func (m *MockExpressionTaskService) GetOperationEndTime(operator string) *timestamppb.Timestamp {
 	m.ctrl.T.Helper()
@@ -105,42 +181,113 @@ func (mr *MockExpressionTaskServiceMockRecorder) GetOperationEndTime(operator an
 }
 
 // GetTask mocks base method.
-func (m *MockExpressionTaskService) GetTask(ctx context.Context) (*proto.Task, error) {
+func (m *MockExpressionTaskService) GetTask(ctx context.Context, agent string) (*proto.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTask", ctx)
+	ret := m.ctrl.Call(m, "GetTask", ctx, agent)
 	ret0, _ := ret[0].(*proto.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTask indicates an expected call of GetTask.
-func (mr *MockExpressionTaskServiceMockRecorder) GetTask(ctx any) *gomock.Call {
+func (mr *MockExpressionTaskServiceMockRecorder) GetTask(ctx, agent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockExpressionTaskService)(nil).GetTask), ctx, agent)
+}
+
+// ReleaseTask mocks base method.
+func (m *MockExpressionTaskService) ReleaseTask(ctx context.Context, task *proto.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseTask", ctx, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseTask indicates an expected call of ReleaseTask.
+func (mr *MockExpressionTaskServiceMockRecorder) ReleaseTask(ctx, task any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseTask", reflect.TypeOf((*MockExpressionTaskService)(nil).ReleaseTask), ctx, task)
+}
+
+// RenewLeases mocks base method.
+func (m *MockExpressionTaskService) RenewLeases(ctx context.Context, agent string, taskIDs []uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewLeases", ctx, agent, taskIDs)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewLeases indicates an expected call of RenewLeases.
+func (mr *MockExpressionTaskServiceMockRecorder) RenewLeases(ctx, agent, taskIDs any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockExpressionTaskService)(nil).GetTask), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewLeases", reflect.TypeOf((*MockExpressionTaskService)(nil).RenewLeases), ctx, agent, taskIDs)
 }
 
 // SetTaskResult mocks base method.
-func (m *MockExpressionTaskService) SetTaskResult(ctx context.Context, task *proto.Task, result float64) error {
+func (m *MockExpressionTaskService) SetTaskResult(ctx context.Context, taskID, token uuid.UUID, result models.TaskResult) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetTaskResult", ctx, task, result)
+	ret := m.ctrl.Call(m, "SetTaskResult", ctx, taskID, token, result)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetTaskResult indicates an expected call of SetTaskResult.
-func (mr *MockExpressionTaskServiceMockRecorder) SetTaskResult(ctx, task, result any) *gomock.Call {
+func (mr *MockExpressionTaskServiceMockRecorder) SetTaskResult(ctx, taskID, token, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskResult", reflect.TypeOf((*MockExpressionTaskService)(nil).SetTaskResult), ctx, taskID, token, result)
+}
+
+// SimplifyExpression mocks base method.
+func (m *MockExpressionTaskService) SimplifyExpression(ctx context.Context, userID uuid.UUID, expression string, options services.SymbolicOptions) (*services.SymbolicResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimplifyExpression", ctx, userID, expression, options)
+	ret0, _ := ret[0].(*services.SymbolicResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimplifyExpression indicates an expected call of SimplifyExpression.
+func (mr *MockExpressionTaskServiceMockRecorder) SimplifyExpression(ctx, userID, expression, options any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskResult", reflect.TypeOf((*MockExpressionTaskService)(nil).SetTaskResult), ctx, task, result)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimplifyExpression", reflect.TypeOf((*MockExpressionTaskService)(nil).SimplifyExpression), ctx, userID, expression, options)
 }
 
 // StartExpiredTaskReset mocks base method.
-func (m *MockExpressionTaskService) StartExpiredTaskReset(ctx context.Context, interval, delay time.Duration) {
+func (m *MockExpressionTaskService) StartExpiredTaskReset(ctx context.Context, interval time.Duration) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "StartExpiredTaskReset", ctx, interval, delay)
+	m.ctrl.Call(m, "StartExpiredTaskReset", ctx, interval)
 }
 
 // StartExpiredTaskReset indicates an expected call of StartExpiredTaskReset.
-func (mr *MockExpressionTaskServiceMockRecorder) StartExpiredTaskReset(ctx, interval, delay any) *gomock.Call {
+func (mr *MockExpressionTaskServiceMockRecorder) StartExpiredTaskReset(ctx, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartExpiredTaskReset", reflect.TypeOf((*MockExpressionTaskService)(nil).StartExpiredTaskReset), ctx, interval)
+}
+
+// StartTaskDispatch mocks base method.
+func (m *MockExpressionTaskService) StartTaskDispatch(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StartTaskDispatch", ctx)
+}
+
+// StartTaskDispatch indicates an expected call of StartTaskDispatch.
+func (mr *MockExpressionTaskServiceMockRecorder) StartTaskDispatch(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartTaskDispatch", reflect.TypeOf((*MockExpressionTaskService)(nil).StartTaskDispatch), ctx)
+}
+
+// WaitTask mocks base method.
+func (m *MockExpressionTaskService) WaitTask(ctx context.Context, agent string) (*proto.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitTask", ctx, agent)
+	ret0, _ := ret[0].(*proto.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitTask indicates an expected call of WaitTask.
+func (mr *MockExpressionTaskServiceMockRecorder) WaitTask(ctx, agent any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartExpiredTaskReset", reflect.TypeOf((*MockExpressionTaskService)(nil).StartExpiredTaskReset), ctx, interval, delay)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitTask", reflect.TypeOf((*MockExpressionTaskService)(nil).WaitTask), ctx, agent)
 }
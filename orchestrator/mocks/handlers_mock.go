@@ -54,6 +54,132 @@ func (mr *MockHandlerMockRecorder) Calculate(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Calculate", reflect.TypeOf((*MockHandler)(nil).Calculate), c)
 }
 
+// CalculateBatch mocks base method.
+func (m *MockHandler) CalculateBatch(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalculateBatch", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CalculateBatch indicates an expected call of CalculateBatch.
+func (mr *MockHandlerMockRecorder) CalculateBatch(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateBatch", reflect.TypeOf((*MockHandler)(nil).CalculateBatch), c)
+}
+
+// CalculateScript mocks base method.
+func (m *MockHandler) CalculateScript(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalculateScript", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CalculateScript indicates an expected call of CalculateScript.
+func (mr *MockHandlerMockRecorder) CalculateScript(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateScript", reflect.TypeOf((*MockHandler)(nil).CalculateScript), c)
+}
+
+// CreateVariable mocks base method.
+func (m *MockHandler) CreateVariable(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVariable", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVariable indicates an expected call of CreateVariable.
+func (mr *MockHandlerMockRecorder) CreateVariable(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVariable", reflect.TypeOf((*MockHandler)(nil).CreateVariable), c)
+}
+
+// DefineFunction mocks base method.
+func (m *MockHandler) DefineFunction(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefineFunction", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DefineFunction indicates an expected call of DefineFunction.
+func (mr *MockHandlerMockRecorder) DefineFunction(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefineFunction", reflect.TypeOf((*MockHandler)(nil).DefineFunction), c)
+}
+
+// DeleteFunction mocks base method.
+func (m *MockHandler) DeleteFunction(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFunction", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFunction indicates an expected call of DeleteFunction.
+func (mr *MockHandlerMockRecorder) DeleteFunction(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFunction", reflect.TypeOf((*MockHandler)(nil).DeleteFunction), c)
+}
+
+// DeleteVariable mocks base method.
+func (m *MockHandler) DeleteVariable(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVariable", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVariable indicates an expected call of DeleteVariable.
+func (mr *MockHandlerMockRecorder) DeleteVariable(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVariable", reflect.TypeOf((*MockHandler)(nil).DeleteVariable), c)
+}
+
+// DeriveExpression mocks base method.
+func (m *MockHandler) DeriveExpression(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeriveExpression", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeriveExpression indicates an expected call of DeriveExpression.
+func (mr *MockHandlerMockRecorder) DeriveExpression(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeriveExpression", reflect.TypeOf((*MockHandler)(nil).DeriveExpression), c)
+}
+
+// ExplainExpression mocks base method.
+func (m *MockHandler) ExplainExpression(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainExpression", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExplainExpression indicates an expected call of ExplainExpression.
+func (mr *MockHandlerMockRecorder) ExplainExpression(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainExpression", reflect.TypeOf((*MockHandler)(nil).ExplainExpression), c)
+}
+
+// GetAgents mocks base method.
+func (m *MockHandler) GetAgents(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAgents", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAgents indicates an expected call of GetAgents.
+func (mr *MockHandlerMockRecorder) GetAgents(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgents", reflect.TypeOf((*MockHandler)(nil).GetAgents), c)
+}
+
 // GetExpressionByID mocks base method.
 func (m *MockHandler) GetExpressionByID(c echo.Context) error {
 	m.ctrl.T.Helper()
@@ -68,6 +194,20 @@ func (mr *MockHandlerMockRecorder) GetExpressionByID(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpressionByID", reflect.TypeOf((*MockHandler)(nil).GetExpressionByID), c)
 }
 
+// GetExpressionTasks mocks base method.
+func (m *MockHandler) GetExpressionTasks(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpressionTasks", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetExpressionTasks indicates an expected call of GetExpressionTasks.
+func (mr *MockHandlerMockRecorder) GetExpressionTasks(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpressionTasks", reflect.TypeOf((*MockHandler)(nil).GetExpressionTasks), c)
+}
+
 // GetExpressions mocks base method.
 func (m *MockHandler) GetExpressions(c echo.Context) error {
 	m.ctrl.T.Helper()
@@ -82,6 +222,48 @@ func (mr *MockHandlerMockRecorder) GetExpressions(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpressions", reflect.TypeOf((*MockHandler)(nil).GetExpressions), c)
 }
 
+// GetFunctions mocks base method.
+func (m *MockHandler) GetFunctions(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFunctions", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetFunctions indicates an expected call of GetFunctions.
+func (mr *MockHandlerMockRecorder) GetFunctions(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFunctions", reflect.TypeOf((*MockHandler)(nil).GetFunctions), c)
+}
+
+// GetVariable mocks base method.
+func (m *MockHandler) GetVariable(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariable", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetVariable indicates an expected call of GetVariable.
+func (mr *MockHandlerMockRecorder) GetVariable(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariable", reflect.TypeOf((*MockHandler)(nil).GetVariable), c)
+}
+
+// GetVariables mocks base method.
+func (m *MockHandler) GetVariables(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariables", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetVariables indicates an expected call of GetVariables.
+func (mr *MockHandlerMockRecorder) GetVariables(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariables", reflect.TypeOf((*MockHandler)(nil).GetVariables), c)
+}
+
 // Login mocks base method.
 func (m *MockHandler) Login(c echo.Context) error {
 	m.ctrl.T.Helper()
@@ -123,3 +305,31 @@ func (mr *MockHandlerMockRecorder) Register(c any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockHandler)(nil).Register), c)
 }
+
+// SimplifyExpression mocks base method.
+func (m *MockHandler) SimplifyExpression(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimplifyExpression", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SimplifyExpression indicates an expected call of SimplifyExpression.
+func (mr *MockHandlerMockRecorder) SimplifyExpression(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimplifyExpression", reflect.TypeOf((*MockHandler)(nil).SimplifyExpression), c)
+}
+
+// UpdateVariable mocks base method.
+func (m *MockHandler) UpdateVariable(c echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVariable", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVariable indicates an expected call of UpdateVariable.
+func (mr *MockHandlerMockRecorder) UpdateVariable(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVariable", reflect.TypeOf((*MockHandler)(nil).UpdateVariable), c)
+}
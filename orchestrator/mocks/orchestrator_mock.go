@@ -43,25 +43,65 @@ func (m *MockOrchestratorServiceClient) EXPECT() *MockOrchestratorServiceClientM
 }
 
 // AssignTasks mocks base method.
-func (m *MockOrchestratorServiceClient) AssignTasks(ctx context.Context, in *proto.AssignTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[proto.Task], error) {
+func (m *MockOrchestratorServiceClient) AssignTasks(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[proto.AssignTasksRequest, proto.Task], error) {
 	m.ctrl.T.Helper()
-	varargs := []any{ctx, in}
+	varargs := []any{ctx}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "AssignTasks", varargs...)
-	ret0, _ := ret[0].(grpc.ServerStreamingClient[proto.Task])
+	ret0, _ := ret[0].(grpc.BidiStreamingClient[proto.AssignTasksRequest, proto.Task])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AssignTasks indicates an expected call of AssignTasks.
-func (mr *MockOrchestratorServiceClientMockRecorder) AssignTasks(ctx, in any, opts ...any) *gomock.Call {
+func (mr *MockOrchestratorServiceClientMockRecorder) AssignTasks(ctx any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{ctx, in}, opts...)
+	varargs := append([]any{ctx}, opts...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignTasks", reflect.TypeOf((*MockOrchestratorServiceClient)(nil).AssignTasks), varargs...)
 }
 
+// Heartbeat mocks base method.
+func (m *MockOrchestratorServiceClient) Heartbeat(ctx context.Context, in *proto.HeartbeatRequest, opts ...grpc.CallOption) (*proto.HeartbeatResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Heartbeat", varargs...)
+	ret0, _ := ret[0].(*proto.HeartbeatResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockOrchestratorServiceClientMockRecorder) Heartbeat(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockOrchestratorServiceClient)(nil).Heartbeat), varargs...)
+}
+
+// RegisterAgent mocks base method.
+func (m *MockOrchestratorServiceClient) RegisterAgent(ctx context.Context, in *proto.RegisterAgentRequest, opts ...grpc.CallOption) (*proto.RegisterAgentResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RegisterAgent", varargs...)
+	ret0, _ := ret[0].(*proto.RegisterAgentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterAgent indicates an expected call of RegisterAgent.
+func (mr *MockOrchestratorServiceClientMockRecorder) RegisterAgent(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAgent", reflect.TypeOf((*MockOrchestratorServiceClient)(nil).RegisterAgent), varargs...)
+}
+
 // SubmitTask mocks base method.
 func (m *MockOrchestratorServiceClient) SubmitTask(ctx context.Context, in *proto.SubmitTaskRequest, opts ...grpc.CallOption) (*proto.SubmitTaskResponse, error) {
 	m.ctrl.T.Helper()
@@ -107,17 +147,47 @@ func (m *MockOrchestratorServiceServer) EXPECT() *MockOrchestratorServiceServerM
 }
 
 // AssignTasks mocks base method.
-func (m *MockOrchestratorServiceServer) AssignTasks(arg0 *proto.AssignTasksRequest, arg1 grpc.ServerStreamingServer[proto.Task]) error {
+func (m *MockOrchestratorServiceServer) AssignTasks(arg0 grpc.BidiStreamingServer[proto.AssignTasksRequest, proto.Task]) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AssignTasks", arg0, arg1)
+	ret := m.ctrl.Call(m, "AssignTasks", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AssignTasks indicates an expected call of AssignTasks.
-func (mr *MockOrchestratorServiceServerMockRecorder) AssignTasks(arg0, arg1 any) *gomock.Call {
+func (mr *MockOrchestratorServiceServerMockRecorder) AssignTasks(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignTasks", reflect.TypeOf((*MockOrchestratorServiceServer)(nil).AssignTasks), arg0)
+}
+
+// Heartbeat mocks base method.
+func (m *MockOrchestratorServiceServer) Heartbeat(arg0 context.Context, arg1 *proto.HeartbeatRequest) (*proto.HeartbeatResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", arg0, arg1)
+	ret0, _ := ret[0].(*proto.HeartbeatResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockOrchestratorServiceServerMockRecorder) Heartbeat(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockOrchestratorServiceServer)(nil).Heartbeat), arg0, arg1)
+}
+
+// RegisterAgent mocks base method.
+func (m *MockOrchestratorServiceServer) RegisterAgent(arg0 context.Context, arg1 *proto.RegisterAgentRequest) (*proto.RegisterAgentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterAgent", arg0, arg1)
+	ret0, _ := ret[0].(*proto.RegisterAgentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterAgent indicates an expected call of RegisterAgent.
+func (mr *MockOrchestratorServiceServerMockRecorder) RegisterAgent(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignTasks", reflect.TypeOf((*MockOrchestratorServiceServer)(nil).AssignTasks), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAgent", reflect.TypeOf((*MockOrchestratorServiceServer)(nil).RegisterAgent), arg0, arg1)
 }
 
 // SubmitTask mocks base method.
@@ -16,6 +16,7 @@ import (
 
 	postgres "github.com/alexGoLyceum/calculator-service/orchestrator/internal/database/postgres"
 	models "github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	repository "github.com/alexGoLyceum/calculator-service/orchestrator/internal/repository"
 	proto "github.com/alexGoLyceum/calculator-service/orchestrator/internal/transport/grpc/proto"
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -75,6 +76,20 @@ func (mr *MockRepositoryMockRecorder) CreateExpressionTask(ctx, expression, task
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpressionTask", reflect.TypeOf((*MockRepository)(nil).CreateExpressionTask), ctx, expression, tasks)
 }
 
+// CreateExpressionTasks mocks base method.
+func (m *MockRepository) CreateExpressionTasks(ctx context.Context, expressions []*models.Expression, tasks []*models.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExpressionTasks", ctx, expressions, tasks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateExpressionTasks indicates an expected call of CreateExpressionTasks.
+func (mr *MockRepositoryMockRecorder) CreateExpressionTasks(ctx, expressions, tasks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpressionTasks", reflect.TypeOf((*MockRepository)(nil).CreateExpressionTasks), ctx, expressions, tasks)
+}
+
 // CreateUser mocks base method.
 func (m *MockRepository) CreateUser(ctx context.Context, login, password string) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -90,6 +105,62 @@ func (mr *MockRepositoryMockRecorder) CreateUser(ctx, login, password any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockRepository)(nil).CreateUser), ctx, login, password)
 }
 
+// CreateUserFunction mocks base method.
+func (m *MockRepository) CreateUserFunction(ctx context.Context, function *models.UserFunction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserFunction", ctx, function)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUserFunction indicates an expected call of CreateUserFunction.
+func (mr *MockRepositoryMockRecorder) CreateUserFunction(ctx, function any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserFunction", reflect.TypeOf((*MockRepository)(nil).CreateUserFunction), ctx, function)
+}
+
+// CreateVariable mocks base method.
+func (m *MockRepository) CreateVariable(ctx context.Context, variable *models.Variable) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVariable", ctx, variable)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVariable indicates an expected call of CreateVariable.
+func (mr *MockRepositoryMockRecorder) CreateVariable(ctx, variable any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVariable", reflect.TypeOf((*MockRepository)(nil).CreateVariable), ctx, variable)
+}
+
+// DeleteUserFunction mocks base method.
+func (m *MockRepository) DeleteUserFunction(ctx context.Context, userID uuid.UUID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserFunction", ctx, userID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserFunction indicates an expected call of DeleteUserFunction.
+func (mr *MockRepositoryMockRecorder) DeleteUserFunction(ctx, userID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserFunction", reflect.TypeOf((*MockRepository)(nil).DeleteUserFunction), ctx, userID, name)
+}
+
+// DeleteVariable mocks base method.
+func (m *MockRepository) DeleteVariable(ctx context.Context, userID uuid.UUID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVariable", ctx, userID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVariable indicates an expected call of DeleteVariable.
+func (mr *MockRepositoryMockRecorder) DeleteVariable(ctx, userID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVariable", reflect.TypeOf((*MockRepository)(nil).DeleteVariable), ctx, userID, name)
+}
+
 // GetAllExpressions mocks base method.
 func (m *MockRepository) GetAllExpressions(ctx context.Context, userID uuid.UUID) ([]*models.Expression, error) {
 	m.ctrl.T.Helper()
@@ -120,47 +191,193 @@ func (mr *MockRepositoryMockRecorder) GetExpressionByID(ctx, id any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpressionByID", reflect.TypeOf((*MockRepository)(nil).GetExpressionByID), ctx, id)
 }
 
+// GetExpressionTasks mocks base method.
+func (m *MockRepository) GetExpressionTasks(ctx context.Context, expressionID uuid.UUID) ([]*models.TaskRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpressionTasks", ctx, expressionID)
+	ret0, _ := ret[0].([]*models.TaskRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpressionTasks indicates an expected call of GetExpressionTasks.
+func (mr *MockRepositoryMockRecorder) GetExpressionTasks(ctx, expressionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpressionTasks", reflect.TypeOf((*MockRepository)(nil).GetExpressionTasks), ctx, expressionID)
+}
+
+// GetLiveAgents mocks base method.
+func (m *MockRepository) GetLiveAgents(ctx context.Context, window time.Duration) ([]*models.Agent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLiveAgents", ctx, window)
+	ret0, _ := ret[0].([]*models.Agent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLiveAgents indicates an expected call of GetLiveAgents.
+func (mr *MockRepositoryMockRecorder) GetLiveAgents(ctx, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLiveAgents", reflect.TypeOf((*MockRepository)(nil).GetLiveAgents), ctx, window)
+}
+
 // GetTask mocks base method.
-func (m *MockRepository) GetTask(ctx context.Context, getEndTime func(string) *timestamppb.Timestamp) (*proto.Task, error) {
+func (m *MockRepository) GetTask(ctx context.Context, agent string, lease time.Duration, getEndTime func(string) *timestamppb.Timestamp) (*proto.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTask", ctx, getEndTime)
+	ret := m.ctrl.Call(m, "GetTask", ctx, agent, lease, getEndTime)
 	ret0, _ := ret[0].(*proto.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTask indicates an expected call of GetTask.
-func (mr *MockRepositoryMockRecorder) GetTask(ctx, getEndTime any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetTask(ctx, agent, lease, getEndTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockRepository)(nil).GetTask), ctx, agent, lease, getEndTime)
+}
+
+// GetUserFunctions mocks base method.
+func (m *MockRepository) GetUserFunctions(ctx context.Context, userID uuid.UUID) ([]*models.UserFunction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserFunctions", ctx, userID)
+	ret0, _ := ret[0].([]*models.UserFunction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserFunctions indicates an expected call of GetUserFunctions.
+func (mr *MockRepositoryMockRecorder) GetUserFunctions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserFunctions", reflect.TypeOf((*MockRepository)(nil).GetUserFunctions), ctx, userID)
+}
+
+// GetVariable mocks base method.
+func (m *MockRepository) GetVariable(ctx context.Context, userID uuid.UUID, name string) (*models.Variable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariable", ctx, userID, name)
+	ret0, _ := ret[0].(*models.Variable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariable indicates an expected call of GetVariable.
+func (mr *MockRepositoryMockRecorder) GetVariable(ctx, userID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariable", reflect.TypeOf((*MockRepository)(nil).GetVariable), ctx, userID, name)
+}
+
+// GetVariables mocks base method.
+func (m *MockRepository) GetVariables(ctx context.Context, userID uuid.UUID) ([]*models.Variable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariables", ctx, userID)
+	ret0, _ := ret[0].([]*models.Variable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariables indicates an expected call of GetVariables.
+func (mr *MockRepositoryMockRecorder) GetVariables(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariables", reflect.TypeOf((*MockRepository)(nil).GetVariables), ctx, userID)
+}
+
+// RegisterAgent mocks base method.
+func (m *MockRepository) RegisterAgent(ctx context.Context, agent *models.Agent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterAgent", ctx, agent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterAgent indicates an expected call of RegisterAgent.
+func (mr *MockRepositoryMockRecorder) RegisterAgent(ctx, agent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAgent", reflect.TypeOf((*MockRepository)(nil).RegisterAgent), ctx, agent)
+}
+
+// ReleaseTask mocks base method.
+func (m *MockRepository) ReleaseTask(ctx context.Context, taskID, token uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseTask", ctx, taskID, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseTask indicates an expected call of ReleaseTask.
+func (mr *MockRepositoryMockRecorder) ReleaseTask(ctx, taskID, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockRepository)(nil).GetTask), ctx, getEndTime)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseTask", reflect.TypeOf((*MockRepository)(nil).ReleaseTask), ctx, taskID, token)
+}
+
+// RenewLeases mocks base method.
+func (m *MockRepository) RenewLeases(ctx context.Context, agent string, taskIDs []uuid.UUID, lease time.Duration) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewLeases", ctx, agent, taskIDs, lease)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewLeases indicates an expected call of RenewLeases.
+func (mr *MockRepositoryMockRecorder) RenewLeases(ctx, agent, taskIDs, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewLeases", reflect.TypeOf((*MockRepository)(nil).RenewLeases), ctx, agent, taskIDs, lease)
 }
 
 // ResetExpiredTasks mocks base method.
-func (m *MockRepository) ResetExpiredTasks(ctx context.Context, delay time.Duration) error {
+func (m *MockRepository) ResetExpiredTasks(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ResetExpiredTasks", ctx, delay)
+	ret := m.ctrl.Call(m, "ResetExpiredTasks", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ResetExpiredTasks indicates an expected call of ResetExpiredTasks.
-func (mr *MockRepositoryMockRecorder) ResetExpiredTasks(ctx, delay any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) ResetExpiredTasks(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetExpiredTasks", reflect.TypeOf((*MockRepository)(nil).ResetExpiredTasks), ctx, delay)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetExpiredTasks", reflect.TypeOf((*MockRepository)(nil).ResetExpiredTasks), ctx)
 }
 
 // SetTaskResult mocks base method.
-func (m *MockRepository) SetTaskResult(ctx context.Context, task *proto.Task, result float64) error {
+func (m *MockRepository) SetTaskResult(ctx context.Context, taskID, token uuid.UUID, result models.TaskResult, normalize repository.NormalizeResultFunc) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetTaskResult", ctx, task, result)
+	ret := m.ctrl.Call(m, "SetTaskResult", ctx, taskID, token, result, normalize)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetTaskResult indicates an expected call of SetTaskResult.
-func (mr *MockRepositoryMockRecorder) SetTaskResult(ctx, task, result any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) SetTaskResult(ctx, taskID, token, result, normalize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskResult", reflect.TypeOf((*MockRepository)(nil).SetTaskResult), ctx, taskID, token, result, normalize)
+}
+
+// TouchAgent mocks base method.
+func (m *MockRepository) TouchAgent(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAgent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAgent indicates an expected call of TouchAgent.
+func (mr *MockRepositoryMockRecorder) TouchAgent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAgent", reflect.TypeOf((*MockRepository)(nil).TouchAgent), ctx, id)
+}
+
+// UpdateVariable mocks base method.
+func (m *MockRepository) UpdateVariable(ctx context.Context, variable *models.Variable) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVariable", ctx, variable)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVariable indicates an expected call of UpdateVariable.
+func (mr *MockRepositoryMockRecorder) UpdateVariable(ctx, variable any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskResult", reflect.TypeOf((*MockRepository)(nil).SetTaskResult), ctx, task, result)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVariable", reflect.TypeOf((*MockRepository)(nil).UpdateVariable), ctx, variable)
 }
 
 // WithTransaction mocks base method.
@@ -19,31 +19,31 @@ import (
 )
 
 // MockOrchestratorService_AssignTasksServer is a mock of OrchestratorService_AssignTasksServer interface.
-type MockOrchestratorService_AssignTasksServer[Res any] struct {
+type MockOrchestratorService_AssignTasksServer[Req any, Res any] struct {
 	ctrl     *gomock.Controller
-	recorder *MockOrchestratorService_AssignTasksServerMockRecorder[Res]
+	recorder *MockOrchestratorService_AssignTasksServerMockRecorder[Req, Res]
 	isgomock struct{}
 }
 
 // MockOrchestratorService_AssignTasksServerMockRecorder is the mock recorder for MockOrchestratorService_AssignTasksServer.
-type MockOrchestratorService_AssignTasksServerMockRecorder[Res any] struct {
-	mock *MockOrchestratorService_AssignTasksServer[Res]
+type MockOrchestratorService_AssignTasksServerMockRecorder[Req any, Res any] struct {
+	mock *MockOrchestratorService_AssignTasksServer[Req, Res]
 }
 
 // NewMockOrchestratorService_AssignTasksServer creates a new mock instance.
-func NewMockOrchestratorService_AssignTasksServer[Res any](ctrl *gomock.Controller) *MockOrchestratorService_AssignTasksServer[Res] {
-	mock := &MockOrchestratorService_AssignTasksServer[Res]{ctrl: ctrl}
-	mock.recorder = &MockOrchestratorService_AssignTasksServerMockRecorder[Res]{mock}
+func NewMockOrchestratorService_AssignTasksServer[Req any, Res any](ctrl *gomock.Controller) *MockOrchestratorService_AssignTasksServer[Req, Res] {
+	mock := &MockOrchestratorService_AssignTasksServer[Req, Res]{ctrl: ctrl}
+	mock.recorder = &MockOrchestratorService_AssignTasksServerMockRecorder[Req, Res]{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockOrchestratorService_AssignTasksServer[Res]) EXPECT() *MockOrchestratorService_AssignTasksServerMockRecorder[Res] {
+func (m *MockOrchestratorService_AssignTasksServer[Req, Res]) EXPECT() *MockOrchestratorService_AssignTasksServerMockRecorder[Req, Res] {
 	return m.recorder
 }
 
 // Context mocks base method.
-func (m *MockOrchestratorService_AssignTasksServer[Res]) Context() context.Context {
+func (m *MockOrchestratorService_AssignTasksServer[Req, Res]) Context() context.Context {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Context")
 	ret0, _ := ret[0].(context.Context)
@@ -51,13 +51,28 @@ func (m *MockOrchestratorService_AssignTasksServer[Res]) Context() context.Conte
 }
 
 // Context indicates an expected call of Context.
-func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Res]) Context() *gomock.Call {
+func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Req, Res]) Context() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Res])(nil).Context))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Req, Res])(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockOrchestratorService_AssignTasksServer[Req, Res]) Recv() (*proto.AssignTasksRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*proto.AssignTasksRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Req, Res]) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Req, Res])(nil).Recv))
 }
 
 // RecvMsg mocks base method.
-func (m_2 *MockOrchestratorService_AssignTasksServer[Res]) RecvMsg(m any) error {
+func (m_2 *MockOrchestratorService_AssignTasksServer[Req, Res]) RecvMsg(m any) error {
 	m_2.ctrl.T.Helper()
 	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
 	ret0, _ := ret[0].(error)
@@ -65,13 +80,13 @@ func (m_2 *MockOrchestratorService_AssignTasksServer[Res]) RecvMsg(m any) error
 }
 
 // RecvMsg indicates an expected call of RecvMsg.
-func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Res]) RecvMsg(m any) *gomock.Call {
+func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Req, Res]) RecvMsg(m any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Res])(nil).RecvMsg), m)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Req, Res])(nil).RecvMsg), m)
 }
 
 // Send mocks base method.
-func (m *MockOrchestratorService_AssignTasksServer[Res]) Send(arg0 *proto.Task) error {
+func (m *MockOrchestratorService_AssignTasksServer[Req, Res]) Send(arg0 *proto.Task) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Send", arg0)
 	ret0, _ := ret[0].(error)
@@ -79,13 +94,13 @@ func (m *MockOrchestratorService_AssignTasksServer[Res]) Send(arg0 *proto.Task)
 }
 
 // Send indicates an expected call of Send.
-func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Res]) Send(arg0 any) *gomock.Call {
+func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Req, Res]) Send(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Res])(nil).Send), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Req, Res])(nil).Send), arg0)
 }
 
 // SendHeader mocks base method.
-func (m *MockOrchestratorService_AssignTasksServer[Res]) SendHeader(arg0 metadata.MD) error {
+func (m *MockOrchestratorService_AssignTasksServer[Req, Res]) SendHeader(arg0 metadata.MD) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SendHeader", arg0)
 	ret0, _ := ret[0].(error)
@@ -93,13 +108,13 @@ func (m *MockOrchestratorService_AssignTasksServer[Res]) SendHeader(arg0 metadat
 }
 
 // SendHeader indicates an expected call of SendHeader.
-func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Res]) SendHeader(arg0 any) *gomock.Call {
+func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Req, Res]) SendHeader(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Res])(nil).SendHeader), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Req, Res])(nil).SendHeader), arg0)
 }
 
 // SendMsg mocks base method.
-func (m_2 *MockOrchestratorService_AssignTasksServer[Res]) SendMsg(m any) error {
+func (m_2 *MockOrchestratorService_AssignTasksServer[Req, Res]) SendMsg(m any) error {
 	m_2.ctrl.T.Helper()
 	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
 	ret0, _ := ret[0].(error)
@@ -107,13 +122,13 @@ func (m_2 *MockOrchestratorService_AssignTasksServer[Res]) SendMsg(m any) error
 }
 
 // SendMsg indicates an expected call of SendMsg.
-func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Res]) SendMsg(m any) *gomock.Call {
+func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Req, Res]) SendMsg(m any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Res])(nil).SendMsg), m)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Req, Res])(nil).SendMsg), m)
 }
 
 // SetHeader mocks base method.
-func (m *MockOrchestratorService_AssignTasksServer[Res]) SetHeader(arg0 metadata.MD) error {
+func (m *MockOrchestratorService_AssignTasksServer[Req, Res]) SetHeader(arg0 metadata.MD) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetHeader", arg0)
 	ret0, _ := ret[0].(error)
@@ -121,19 +136,19 @@ func (m *MockOrchestratorService_AssignTasksServer[Res]) SetHeader(arg0 metadata
 }
 
 // SetHeader indicates an expected call of SetHeader.
-func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Res]) SetHeader(arg0 any) *gomock.Call {
+func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Req, Res]) SetHeader(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Res])(nil).SetHeader), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Req, Res])(nil).SetHeader), arg0)
 }
 
 // SetTrailer mocks base method.
-func (m *MockOrchestratorService_AssignTasksServer[Res]) SetTrailer(arg0 metadata.MD) {
+func (m *MockOrchestratorService_AssignTasksServer[Req, Res]) SetTrailer(arg0 metadata.MD) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "SetTrailer", arg0)
 }
 
 // SetTrailer indicates an expected call of SetTrailer.
-func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Res]) SetTrailer(arg0 any) *gomock.Call {
+func (mr *MockOrchestratorService_AssignTasksServerMockRecorder[Req, Res]) SetTrailer(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Res])(nil).SetTrailer), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockOrchestratorService_AssignTasksServer[Req, Res])(nil).SetTrailer), arg0)
 }
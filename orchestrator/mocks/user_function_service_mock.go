@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: orchestrator/internal/services/user_function_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=orchestrator/internal/services/user_function_service.go -destination=orchestrator/mocks/user_function_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserFunctionService is a mock of UserFunctionService interface.
+type MockUserFunctionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserFunctionServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockUserFunctionServiceMockRecorder is the mock recorder for MockUserFunctionService.
+type MockUserFunctionServiceMockRecorder struct {
+	mock *MockUserFunctionService
+}
+
+// NewMockUserFunctionService creates a new mock instance.
+func NewMockUserFunctionService(ctrl *gomock.Controller) *MockUserFunctionService {
+	mock := &MockUserFunctionService{ctrl: ctrl}
+	mock.recorder = &MockUserFunctionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserFunctionService) EXPECT() *MockUserFunctionServiceMockRecorder {
+	return m.recorder
+}
+
+// DefineFunction mocks base method.
+func (m *MockUserFunctionService) DefineFunction(ctx context.Context, userID uuid.UUID, name string, params []string, body string) (*models.UserFunction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefineFunction", ctx, userID, name, params, body)
+	ret0, _ := ret[0].(*models.UserFunction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DefineFunction indicates an expected call of DefineFunction.
+func (mr *MockUserFunctionServiceMockRecorder) DefineFunction(ctx, userID, name, params, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefineFunction", reflect.TypeOf((*MockUserFunctionService)(nil).DefineFunction), ctx, userID, name, params, body)
+}
+
+// DeleteFunction mocks base method.
+func (m *MockUserFunctionService) DeleteFunction(ctx context.Context, userID uuid.UUID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFunction", ctx, userID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFunction indicates an expected call of DeleteFunction.
+func (mr *MockUserFunctionServiceMockRecorder) DeleteFunction(ctx, userID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFunction", reflect.TypeOf((*MockUserFunctionService)(nil).DeleteFunction), ctx, userID, name)
+}
+
+// GetFunctions mocks base method.
+func (m *MockUserFunctionService) GetFunctions(ctx context.Context, userID uuid.UUID) ([]*models.UserFunction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFunctions", ctx, userID)
+	ret0, _ := ret[0].([]*models.UserFunction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFunctions indicates an expected call of GetFunctions.
+func (mr *MockUserFunctionServiceMockRecorder) GetFunctions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFunctions", reflect.TypeOf((*MockUserFunctionService)(nil).GetFunctions), ctx, userID)
+}
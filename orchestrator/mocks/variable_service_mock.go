@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: orchestrator/internal/services/variable_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=orchestrator/internal/services/variable_service.go -destination=orchestrator/mocks/variable_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/alexGoLyceum/calculator-service/orchestrator/internal/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVariableService is a mock of VariableService interface.
+type MockVariableService struct {
+	ctrl     *gomock.Controller
+	recorder *MockVariableServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockVariableServiceMockRecorder is the mock recorder for MockVariableService.
+type MockVariableServiceMockRecorder struct {
+	mock *MockVariableService
+}
+
+// NewMockVariableService creates a new mock instance.
+func NewMockVariableService(ctrl *gomock.Controller) *MockVariableService {
+	mock := &MockVariableService{ctrl: ctrl}
+	mock.recorder = &MockVariableServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVariableService) EXPECT() *MockVariableServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateVariable mocks base method.
+func (m *MockVariableService) CreateVariable(ctx context.Context, userID uuid.UUID, name, value string) (*models.Variable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVariable", ctx, userID, name, value)
+	ret0, _ := ret[0].(*models.Variable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVariable indicates an expected call of CreateVariable.
+func (mr *MockVariableServiceMockRecorder) CreateVariable(ctx, userID, name, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVariable", reflect.TypeOf((*MockVariableService)(nil).CreateVariable), ctx, userID, name, value)
+}
+
+// DeleteVariable mocks base method.
+func (m *MockVariableService) DeleteVariable(ctx context.Context, userID uuid.UUID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVariable", ctx, userID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVariable indicates an expected call of DeleteVariable.
+func (mr *MockVariableServiceMockRecorder) DeleteVariable(ctx, userID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVariable", reflect.TypeOf((*MockVariableService)(nil).DeleteVariable), ctx, userID, name)
+}
+
+// GetVariable mocks base method.
+func (m *MockVariableService) GetVariable(ctx context.Context, userID uuid.UUID, name string) (*models.Variable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariable", ctx, userID, name)
+	ret0, _ := ret[0].(*models.Variable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariable indicates an expected call of GetVariable.
+func (mr *MockVariableServiceMockRecorder) GetVariable(ctx, userID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariable", reflect.TypeOf((*MockVariableService)(nil).GetVariable), ctx, userID, name)
+}
+
+// GetVariables mocks base method.
+func (m *MockVariableService) GetVariables(ctx context.Context, userID uuid.UUID) ([]*models.Variable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariables", ctx, userID)
+	ret0, _ := ret[0].([]*models.Variable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariables indicates an expected call of GetVariables.
+func (mr *MockVariableServiceMockRecorder) GetVariables(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariables", reflect.TypeOf((*MockVariableService)(nil).GetVariables), ctx, userID)
+}
+
+// UpdateVariable mocks base method.
+func (m *MockVariableService) UpdateVariable(ctx context.Context, userID uuid.UUID, name, value string) (*models.Variable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVariable", ctx, userID, name, value)
+	ret0, _ := ret[0].(*models.Variable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVariable indicates an expected call of UpdateVariable.
+func (mr *MockVariableServiceMockRecorder) UpdateVariable(ctx, userID, name, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVariable", reflect.TypeOf((*MockVariableService)(nil).UpdateVariable), ctx, userID, name, value)
+}